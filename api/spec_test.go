@@ -0,0 +1,13 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, doc.Paths.Value("/pvz"))
+}
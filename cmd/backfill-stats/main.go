@@ -0,0 +1,57 @@
+// Command backfill-stats recomputes the daily PVZ statistics for a range of
+// local days, e.g. after deploying the rollup or repairing data:
+//
+//	backfill-stats -from 2025-01-01 -to 2025-03-31
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	config "github.com/senorUVE/pvz_service/configs"
+	"github.com/senorUVE/pvz_service/internal/controller"
+	"github.com/senorUVE/pvz_service/internal/repository"
+	loglib "github.com/senorUVE/pvz_service/log"
+	"github.com/sirupsen/logrus"
+
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	from := flag.String("from", "", "first day to backfill, YYYY-MM-DD")
+	to := flag.String("to", "", "last day to backfill, YYYY-MM-DD; defaults to -from")
+	flag.Parse()
+
+	logrus.SetFormatter(new(logrus.JSONFormatter))
+	loglib.InitLogger(loglib.WithEnv())
+
+	firstDay, err := time.Parse(time.DateOnly, *from)
+	if err != nil {
+		logrus.Fatalf("invalid -from: %v", err)
+	}
+	lastDay := firstDay
+	if *to != "" {
+		if lastDay, err = time.Parse(time.DateOnly, *to); err != nil {
+			logrus.Fatalf("invalid -to: %v", err)
+		}
+	}
+
+	cfg, err := config.LoadConfig("./configs")
+	if err != nil {
+		logrus.Fatalf("Failed to load Config: %v", err)
+	}
+
+	db, err := repository.NewRepository(cfg.DBConfig)
+	if err != nil {
+		logrus.Fatalf("Failed to init db: %v", err)
+	}
+	defer db.Close()
+
+	srv := controller.NewPvzService(db, nil, cfg.ServiceConfig)
+	rows, err := srv.BackfillDailyStats(context.Background(), firstDay, lastDay)
+	if err != nil {
+		logrus.Fatalf("failed to backfill daily stats: %v", err)
+	}
+	logrus.WithFields(logrus.Fields{"from": *from, "to": lastDay.Format(time.DateOnly), "rows": rows}).Info("daily stats backfilled")
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -37,7 +38,7 @@ func main() {
 	auth := auth.NewAuth(cfg.AuthConfig)
 	srv := controller.NewPvzService(db, auth, cfg.ServiceConfig)
 
-	sh := handler.NewPvzHandler(srv, auth, cfg.AppPort)
+	sh := handler.NewPvzHandler(srv, auth, cfg.AppPort, cfg.APIConfig)
 
 	go sh.Start()
 
@@ -49,8 +50,42 @@ func main() {
 		}
 	}()
 
+	workers, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var wg sync.WaitGroup
+
+	runEvery(workers, &wg, time.Hour, func(ctx context.Context) {
+		if _, err := srv.PurgeIdempotencyKeys(ctx); err != nil {
+			logrus.Errorf("failed to purge idempotency keys: %v", err)
+		}
+	})
+
+	webhooks := cfg.ServiceConfig.Webhooks.WithDefaults()
+	runEvery(workers, &wg, webhooks.PollInterval, func(ctx context.Context) {
+		// A full batch means more deliveries may already be due. Sends in
+		// flight are let finish, within the send timeout, so that stopping
+		// does not count as a failed attempt.
+		for ctx.Err() == nil {
+			sent, err := srv.DeliverWebhooks(context.WithoutCancel(ctx))
+			if err != nil {
+				logrus.Errorf("failed to deliver webhooks: %v", err)
+				break
+			}
+			if sent < webhooks.BatchSize {
+				break
+			}
+		}
+	})
+
+	runEvery(workers, &wg, cfg.ServiceConfig.DailyStats.WithDefaults().RefreshInterval, func(ctx context.Context) {
+		if _, err := srv.RefreshDailyStats(ctx); err != nil {
+			logrus.Errorf("failed to refresh daily stats: %v", err)
+		}
+	})
+
+	gs := grpc.NewGrpcServer(srv, auth, cfg.GrpcConfig)
 	go func() {
-		if err := grpc.StartGrpcServer(db); err != nil {
+		if err := gs.Start(); err != nil {
 			logrus.Fatalf("failed to start grpc server: %v", err)
 		}
 	}()
@@ -59,19 +94,41 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-quit
 
+	stopWorkers()
+	grpcCtx, grpcCancel := context.WithTimeout(context.Background(), cfg.GrpcConfig.WithDefaults().ShutdownTimeout)
+	defer grpcCancel()
+	if err := gs.Close(grpcCtx); err != nil {
+		logrus.Errorf("grpc server was stopped before all calls finished: %v", err)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	if err := db.Close(); err != nil {
-		logrus.Fatal(err)
-	}
 	if err := sh.Close(ctx); err != nil {
-		logrus.Fatal(err)
+		logrus.Errorf("http server was stopped before all requests finished: %v", err)
 	}
-
-	select {
-	case <-ctx.Done():
-		logrus.Println("timeout of 3 seconds")
+	wg.Wait()
+	if err := db.Close(); err != nil {
+		logrus.Fatal(err)
 	}
 	logrus.Println("Server exiting")
 }
+
+// runEvery calls fn every interval until ctx is done. wg is released once
+// the last call has returned.
+func runEvery(ctx context.Context, wg *sync.WaitGroup, interval time.Duration, fn func(ctx context.Context)) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				fn(ctx)
+			}
+		}
+	}()
+}
@@ -2,9 +2,13 @@ package config
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/senorUVE/pvz_service/internal/auth"
 	"github.com/senorUVE/pvz_service/internal/controller"
+	"github.com/senorUVE/pvz_service/internal/grpc"
+	"github.com/senorUVE/pvz_service/internal/handler"
 	"github.com/senorUVE/pvz_service/internal/repository"
 	"github.com/spf13/viper"
 )
@@ -15,6 +19,8 @@ type Config struct {
 	AuthConfig    auth.AuthConfig          `mapstructure:"auth_config"`
 	DBConfig      repository.DBConfig      `mapstructure:"db_config"`
 	ServiceConfig controller.ServiceConfig `mapstructure:"service_config"`
+	APIConfig     handler.APIConfig        `mapstructure:"api_config"`
+	GrpcConfig    grpc.Config              `mapstructure:"grpc_config"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -30,7 +36,12 @@ func LoadConfig(path string) (config Config, err error) {
 			return config, fmt.Errorf("failed to read config: %w", err)
 		}
 	}
-	if err := viper.Unmarshal(&config); err != nil {
+	decodeHook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
+	))
+	if err := viper.Unmarshal(&config, decodeHook); err != nil {
 		return config, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
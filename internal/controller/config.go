@@ -1,6 +1,43 @@
 package controller
 
+import (
+	"time"
+
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/senorUVE/pvz_service/internal/webhook"
+)
+
+const (
+	defaultIdempotencyTTL       = 24 * time.Hour
+	defaultStatsRefreshInterval = time.Minute
+	defaultStatsLag             = 5 * time.Minute
+)
+
 type ServiceConfig struct {
-	Salt string `mapstructure:"hash_salt"`
-	Cost int    `mapstructure:"hash_cost"`
+	Salt           string           `mapstructure:"hash_salt"`
+	Cost           int              `mapstructure:"hash_cost"`
+	IdempotencyTTL time.Duration    `mapstructure:"idempotency_ttl"`
+	Events         events.Config    `mapstructure:"events"`
+	Webhooks       webhook.Config   `mapstructure:"webhooks"`
+	DailyStats     DailyStatsConfig `mapstructure:"daily_stats"`
+}
+
+type DailyStatsConfig struct {
+	// RefreshInterval is how often recent activity is rolled up.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	// Lag is how far back every refresh re-reads, for changes that commit
+	// after the time they are stamped with. Reports only use days rolled up
+	// at least Lag ago.
+	Lag time.Duration `mapstructure:"lag"`
+}
+
+// WithDefaults fills in unset fields.
+func (c DailyStatsConfig) WithDefaults() DailyStatsConfig {
+	if c.RefreshInterval <= 0 {
+		c.RefreshInterval = defaultStatsRefreshInterval
+	}
+	if c.Lag <= 0 {
+		c.Lag = defaultStatsLag
+	}
+	return c
 }
@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/senorUVE/pvz_service/internal/auth"
 	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/senorUVE/pvz_service/internal/label"
 	"github.com/senorUVE/pvz_service/internal/metrics"
 	"github.com/senorUVE/pvz_service/internal/models"
 	"github.com/senorUVE/pvz_service/internal/repository"
+	"github.com/senorUVE/pvz_service/internal/webhook"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -19,26 +23,63 @@ type Repository interface {
 	GetUser(ctx context.Context, email string) (*models.User, error)
 	CreateUser(ctx context.Context, email, password, role string) (uuid.UUID, error)
 	CreatePvz(ctx context.Context, pvz models.PVZ) (*dto.PvzCreateResponse, error)
+	ImportPvz(ctx context.Context, pvzs []models.PVZ, dryRun bool) ([]bool, error)
+	GetPvzById(ctx context.Context, pvzId uuid.UUID) (*dto.PVZResponse, error)
 	CreateReception(ctx context.Context, pvzId uuid.UUID) (*dto.CreateReceptionResponse, error)
 	CreateProduct(ctx context.Context, typeOf string, receptionId uuid.UUID) (*dto.AddProductResponse, error)
 	CloseReception(ctx context.Context, pvzId uuid.UUID) (*dto.CloseLastReceptionResponse, error)
-	DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error
-	GetPvz(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]*dto.PVZWithReceptions, error)
+	DeleteLastProduct(ctx context.Context, pvzID, userId uuid.UUID) (*dto.ProductResponse, error)
+	RestoreLastProduct(ctx context.Context, pvzID, userId uuid.UUID) (*dto.ProductResponse, error)
+	GetPvz(ctx context.Context, filter dto.GetPvzRequest) (*dto.GetPvzResponse, error)
+	GetPvzReceptions(ctx context.Context, pvzIds []uuid.UUID, filter dto.GetPvzRequest) (map[uuid.UUID][]dto.ReceptionWithProducts, error)
 	GetActiveReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error)
 	DummyLogin(ctx context.Context, role string) (*models.User, error)
+	GetProductLabel(ctx context.Context, productId uuid.UUID) (*dto.ProductLabel, error)
+	GetReceptionLabels(ctx context.Context, receptionId uuid.UUID) ([]*dto.ProductLabel, error)
+	GetProductDetails(ctx context.Context, productId uuid.UUID) (*dto.ProductDetailsResponse, error)
+	LockProduct(ctx context.Context, productId uuid.UUID) error
+	AddProductEvent(ctx context.Context, productId uuid.UUID, eventType models.ProductEventType, userId uuid.UUID, pvzId *uuid.UUID) error
+	ReserveIdempotencyKey(ctx context.Context, record dto.IdempotencyRecord, ttl time.Duration) (*dto.IdempotencyRecord, error)
+	SaveIdempotencyResponse(ctx context.Context, record dto.IdempotencyRecord) error
+	DeleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	CreateWebhook(ctx context.Context, request dto.WebhookRequest, createdBy uuid.UUID) (*dto.WebhookResponse, error)
+	ListWebhooks(ctx context.Context) ([]dto.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error
+	EnqueueWebhookDeliveries(ctx context.Context, eventType string, payload []byte) (int64, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]dto.WebhookMessage, error)
+	SaveWebhookAttempt(ctx context.Context, attempt dto.WebhookAttempt) error
+	ListWebhookDeliveries(ctx context.Context, webhookId uuid.UUID, status string) ([]dto.WebhookDeliveryResponse, error)
+	RedeliverWebhook(ctx context.Context, deliveryId uuid.UUID) (*dto.WebhookDeliveryResponse, error)
+	StreamReceptionReport(ctx context.Context, filter dto.ReceptionReportFilter, fn func(dto.ReceptionReportRow) error) error
+	GetIntakeReport(ctx context.Context, filter dto.IntakeReportFilter) ([]dto.IntakeReportRow, error)
+	GetDailyIntakeReport(ctx context.Context, filter dto.IntakeReportFilter, firstDay, lastDay time.Time) ([]dto.IntakeReportRow, error)
+	RefreshRecentDailyStats(ctx context.Context, lag time.Duration) (int64, error)
+	BackfillDailyStats(ctx context.Context, from, to time.Time) (int64, error)
+	DailyStatsRefreshedUntil(ctx context.Context) (time.Time, error)
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+	GetSyncResult(ctx context.Context, userId, clientId uuid.UUID) (*dto.SyncResult, error)
+	SaveSyncResult(ctx context.Context, userId uuid.UUID, op dto.SyncOperation, result dto.SyncResult) error
+	Ping(ctx context.Context) error
 }
 
 type PvzService struct {
-	repo Repository
-	auth auth.AuthService
-	cfg  ServiceConfig
+	repo     Repository
+	auth     auth.AuthService
+	cfg      ServiceConfig
+	events   *events.Broker
+	webhooks *webhook.Sender
+	// cities caches PVZ cities for events; a PVZ never changes its city.
+	cities sync.Map
 }
 
 func NewPvzService(repo Repository, auth auth.AuthService, cfg ServiceConfig) *PvzService {
 	return &PvzService{
-		repo: repo,
-		auth: auth,
-		cfg:  cfg,
+		repo:     repo,
+		auth:     auth,
+		cfg:      cfg,
+		events:   events.NewBroker(cfg.Events),
+		webhooks: webhook.NewSender(cfg.Webhooks),
 	}
 }
 
@@ -112,6 +153,7 @@ func (p *PvzService) CreatePVZ(ctx context.Context, request *dto.PvzCreateReques
 		Id:               uuid.New(),
 		RegistrationDate: time.Now().UTC(),
 		City:             models.City(request.City),
+		ExternalCode:     request.ExternalCode,
 	}
 
 	created, err := p.repo.CreatePvz(ctx, pvz)
@@ -125,22 +167,16 @@ func (p *PvzService) CreatePVZ(ctx context.Context, request *dto.PvzCreateReques
 		Id:               created.Id,
 		RegistrationDate: created.RegistrationDate,
 		City:             created.City,
+		ExternalCode:     created.ExternalCode,
 	}, nil
 }
 
-func (p *PvzService) GetPvz(ctx context.Context, request *dto.GetPvzRequest) ([]*dto.PVZWithReceptions, error) {
+func (p *PvzService) GetPvz(ctx context.Context, request *dto.GetPvzRequest) (*dto.GetPvzResponse, error) {
 	if err := ValidateGetPvzRequest(request); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	filter := dto.GetPvzRequest{
-		StartDate: request.StartDate,
-		EndDate:   request.EndDate,
-		Page:      request.Page,
-		Limit:     request.Limit,
-	}
-
-	result, err := p.repo.GetPvz(ctx, filter.StartDate, filter.EndDate, filter.Page, filter.Limit)
+	result, err := p.repo.GetPvz(ctx, *request)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +184,24 @@ func (p *PvzService) GetPvz(ctx context.Context, request *dto.GetPvzRequest) ([]
 	return result, nil
 }
 
+// Ping reports whether the database is reachable.
+func (p *PvzService) Ping(ctx context.Context) error {
+	return p.repo.Ping(ctx)
+}
+
+func (p *PvzService) GetPvzById(ctx context.Context, pvzId uuid.UUID) (*dto.PVZResponse, error) {
+	return p.repo.GetPvzById(ctx, pvzId)
+}
+
+// GetPvzReceptions returns receptions of the given PVZ grouped by PVZ id.
+// Only reception filters of the request are used.
+func (p *PvzService) GetPvzReceptions(ctx context.Context, pvzIds []uuid.UUID, request *dto.GetPvzRequest) (map[uuid.UUID][]dto.ReceptionWithProducts, error) {
+	if err := ValidateGetPvzRequest(request); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return p.repo.GetPvzReceptions(ctx, pvzIds, *request)
+}
+
 func (p *PvzService) CreateReception(ctx context.Context, request *dto.CreateReceptionRequest) (*dto.CreateReceptionResponse, error) {
 
 	reception := &models.Reception{
@@ -157,7 +211,20 @@ func (p *PvzService) CreateReception(ctx context.Context, request *dto.CreateRec
 		Status:   models.StatusInProgress,
 	}
 
-	created, err := p.repo.CreateReception(ctx, reception.PvzId)
+	var created *dto.CreateReceptionResponse
+	err := p.withEvents(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = p.repo.CreateReception(ctx, reception.PvzId)
+		if err != nil {
+			return err
+		}
+		return p.publish(ctx, events.Event{
+			Type:        events.ReceptionOpened,
+			Time:        created.DateTime,
+			PvzId:       request.PvzId,
+			ReceptionId: created.Id,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +240,19 @@ func (p *PvzService) CreateReception(ctx context.Context, request *dto.CreateRec
 }
 
 func (p *PvzService) CloseReception(ctx context.Context, pvzID uuid.UUID) (*dto.CloseLastReceptionResponse, error) {
-	reception, err := p.repo.CloseReception(ctx, pvzID)
+	var reception *dto.CloseLastReceptionResponse
+	err := p.withEvents(ctx, func(ctx context.Context) error {
+		var err error
+		reception, err = p.repo.CloseReception(ctx, pvzID)
+		if err != nil || !reception.Closed {
+			return err
+		}
+		return p.publish(ctx, events.Event{
+			Type:        events.ReceptionClosed,
+			PvzId:       reception.PvzId,
+			ReceptionId: reception.Id,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -186,18 +265,31 @@ func (p *PvzService) AddProduct(ctx context.Context, request *dto.AddProductRequ
 		return nil, err
 	}
 
-	activeReception, err := p.repo.GetActiveReception(ctx, request.PvzId)
-	if err != nil {
-		return nil, err
-	}
-
 	product := &models.Product{
 		Id:       uuid.New(),
 		DateTime: time.Now().UTC(),
 		Type:     models.Type(request.Type),
 	}
 
-	created, err := p.repo.CreateProduct(ctx, string(product.Type), activeReception.Id)
+	var created *dto.AddProductResponse
+	err := p.withEvents(ctx, func(ctx context.Context) error {
+		activeReception, err := p.repo.GetActiveReception(ctx, request.PvzId)
+		if err != nil {
+			return err
+		}
+		created, err = p.repo.CreateProduct(ctx, string(product.Type), activeReception.Id)
+		if err != nil {
+			return err
+		}
+		return p.publish(ctx, events.Event{
+			Type:        events.ProductAdded,
+			Time:        created.DateTime,
+			PvzId:       request.PvzId,
+			ReceptionId: created.ReceptionId,
+			ProductId:   &created.Id,
+			ProductType: created.Type,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -212,15 +304,54 @@ func (p *PvzService) AddProduct(ctx context.Context, request *dto.AddProductRequ
 	}, nil
 }
 
-func (p *PvzService) DeleteLastProduct(ctx context.Context, pvzId uuid.UUID) error {
-	activeReception, err := p.repo.GetActiveReception(ctx, pvzId)
-	if err != nil {
-		if activeReception == nil {
-			return fmt.Errorf("no active reception: %w", err)
+func (p *PvzService) DeleteLastProduct(ctx context.Context, pvzId, userId uuid.UUID) error {
+	return p.withEvents(ctx, func(ctx context.Context) error {
+		activeReception, err := p.repo.GetActiveReception(ctx, pvzId)
+		if err != nil {
+			if activeReception == nil {
+				return fmt.Errorf("no active reception: %w", err)
+			}
+
+		}
+		product, err := p.repo.DeleteLastProduct(ctx, pvzId, userId)
+		if err != nil {
+			return err
 		}
+		return p.publish(ctx, events.Event{
+			Type:        events.ProductDeleted,
+			PvzId:       pvzId,
+			ReceptionId: product.ReceptionId,
+			ProductId:   &product.Id,
+			ProductType: product.Type,
+			UserId:      &userId,
+		})
+	})
+}
 
+func (p *PvzService) RestoreLastProduct(ctx context.Context, pvzId, userId uuid.UUID) (*dto.ProductResponse, error) {
+	var product *dto.ProductResponse
+	err := p.withEvents(ctx, func(ctx context.Context) error {
+		if _, err := p.repo.GetActiveReception(ctx, pvzId); err != nil {
+			return fmt.Errorf("undo is only possible while the reception is open: %w", err)
+		}
+		var err error
+		product, err = p.repo.RestoreLastProduct(ctx, pvzId, userId)
+		if err != nil {
+			return err
+		}
+		return p.publish(ctx, events.Event{
+			Type:        events.ProductRestored,
+			PvzId:       pvzId,
+			ReceptionId: product.ReceptionId,
+			ProductId:   &product.Id,
+			ProductType: product.Type,
+			UserId:      &userId,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
-	return p.repo.DeleteLastProduct(ctx, pvzId)
+	return product, nil
 }
 
 func (p *PvzService) DummyLogin(ctx context.Context, role string) (string, error) {
@@ -239,3 +370,140 @@ func (p *PvzService) DummyLogin(ctx context.Context, role string) (string, error
 	}
 	return token, nil
 }
+
+func toLabel(info *dto.ProductLabel) label.Label {
+	return label.Label{
+		ProductId:     info.Product.Id,
+		ProductType:   info.Product.Type,
+		ProductDate:   info.Product.DateTime,
+		ReceptionId:   info.Reception.Id,
+		ReceptionDate: info.Reception.DateTime,
+		PvzId:         info.PVZ.Id,
+		City:          info.PVZ.City,
+	}
+}
+
+func (p *PvzService) GetProductLabel(ctx context.Context, productId uuid.UUID, format string) (*dto.LabelResponse, error) {
+	labelFormat, err := label.ParseFormat(format)
+	if err != nil {
+		return nil, invalidField("format", err)
+	}
+
+	info, err := p.repo.GetProductLabel(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := label.Render(labelFormat, toLabel(info))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LabelResponse{
+		ContentType: labelFormat.ContentType(false),
+		FileName:    fmt.Sprintf("label-%s.%s", productId, labelFormat.Extension(false)),
+		Data:        data,
+	}, nil
+}
+
+func (p *PvzService) GetReceptionLabels(ctx context.Context, receptionId uuid.UUID, format string) (*dto.LabelResponse, error) {
+	labelFormat, err := label.ParseFormat(format)
+	if err != nil {
+		return nil, invalidField("format", err)
+	}
+
+	infos, err := p.repo.GetReceptionLabels(ctx, receptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]label.Label, 0, len(infos))
+	for _, info := range infos {
+		labels = append(labels, toLabel(info))
+	}
+
+	data, err := label.RenderBulk(labelFormat, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LabelResponse{
+		ContentType: labelFormat.ContentType(true),
+		FileName:    fmt.Sprintf("labels-%s.%s", receptionId, labelFormat.Extension(true)),
+		Data:        data,
+	}, nil
+}
+
+func (p *PvzService) GetProduct(ctx context.Context, productId uuid.UUID) (*dto.ProductDetailsResponse, error) {
+	return p.repo.GetProductDetails(ctx, productId)
+}
+
+func (p *PvzService) FindProduct(ctx context.Context, request *dto.GetProductRequest) (*dto.ProductDetailsResponse, error) {
+	productId, err := ValidateGetProductRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	return p.repo.GetProductDetails(ctx, productId)
+}
+
+// IssueProduct hands a product waiting at a PVZ over to its recipient.
+func (p *PvzService) IssueProduct(ctx context.Context, productId, userId uuid.UUID) (*dto.ProductDetailsResponse, error) {
+	return p.addProductEvent(ctx, productId, func(ctx context.Context, _ *dto.ProductDetailsResponse) error {
+		return p.repo.AddProductEvent(ctx, productId, models.ProductEventIssued, userId, nil)
+	})
+}
+
+// MoveProduct sends a product waiting at a PVZ on to another PVZ, where it
+// waits to be issued or moved again.
+func (p *PvzService) MoveProduct(ctx context.Context, productId uuid.UUID, request *dto.MoveProductRequest, userId uuid.UUID) (*dto.ProductDetailsResponse, error) {
+	if request.PvzId == uuid.Nil {
+		return nil, invalidField("pvzId", ErrInvalidUUID)
+	}
+	return p.addProductEvent(ctx, productId, func(ctx context.Context, current *dto.ProductDetailsResponse) error {
+		if productLocation(current) == request.PvzId {
+			return invalidField("pvzId", ErrSamePvz)
+		}
+		if _, err := p.repo.GetPvzById(ctx, request.PvzId); err != nil {
+			return err
+		}
+		return p.repo.AddProductEvent(ctx, productId, models.ProductEventMoved, userId, &request.PvzId)
+	})
+}
+
+// addProductEvent runs add with the product locked, if it is waiting at a
+// PVZ, and returns the product with its new timeline.
+func (p *PvzService) addProductEvent(ctx context.Context, productId uuid.UUID, add func(ctx context.Context, current *dto.ProductDetailsResponse) error) (*dto.ProductDetailsResponse, error) {
+	var details *dto.ProductDetailsResponse
+	err := p.repo.InTx(ctx, func(ctx context.Context) error {
+		if err := p.repo.LockProduct(ctx, productId); err != nil {
+			return err
+		}
+		current, err := p.repo.GetProductDetails(ctx, productId)
+		if err != nil {
+			return err
+		}
+		if !models.ProductStatus(current.Status).Available() {
+			return ErrProductNotAvailable
+		}
+		if err := add(ctx, current); err != nil {
+			return err
+		}
+		details, err = p.repo.GetProductDetails(ctx, productId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// productLocation is the PVZ a product was last moved to, or the one it
+// was accepted at.
+func productLocation(details *dto.ProductDetailsResponse) uuid.UUID {
+	for i := len(details.Timeline) - 1; i >= 0; i-- {
+		if event := details.Timeline[i]; event.Type == models.ProductEventMoved.String() && event.PvzId != nil {
+			return *event.PvzId
+		}
+	}
+	return details.PVZ.Id
+}
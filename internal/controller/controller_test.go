@@ -2,17 +2,23 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/senorUVE/pvz_service/internal/label"
 	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/internal/pagination"
 	"github.com/senorUVE/pvz_service/internal/repository"
 	"github.com/senorUVE/pvz_service/test/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -95,6 +101,7 @@ func TestPvzService_CreateReception_Success(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
 	ctx := context.Background()
 	pvzId := uuid.New()
 	req := &dto.CreateReceptionRequest{PvzId: pvzId}
@@ -105,7 +112,9 @@ func TestPvzService_CreateReception_Success(t *testing.T) {
 		Status:   "in_progress",
 	}
 
-	mockRepo.EXPECT().CreateReception(ctx, pvzId).Return(expected, nil)
+	mockRepo.EXPECT().CreateReception(gomock.Any(), pvzId).Return(expected, nil)
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZResponse{Id: pvzId, City: "Москва"}, nil)
+	mockRepo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), "reception.opened", gomock.Any()).Return(int64(1), nil)
 
 	resp, err := service.CreateReception(ctx, req)
 
@@ -120,18 +129,43 @@ func TestPvzService_DeleteLastProduct_Success(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
 	pvzID := uuid.New()
 
 	ctx := context.Background()
 	mockRepo.EXPECT().
-		GetActiveReception(ctx, pvzID).
+		GetActiveReception(gomock.Any(), pvzID).
 		Return(&models.Reception{Id: uuid.New()}, nil)
 
-	mockRepo.EXPECT().DeleteLastProduct(ctx, pvzID).Return(nil)
+	userID := uuid.New()
+	deleted := &dto.ProductResponse{Id: uuid.New(), Type: "обувь", ReceptionId: uuid.New()}
+	mockRepo.EXPECT().DeleteLastProduct(gomock.Any(), pvzID, userID).Return(deleted, nil)
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzID).Return(&dto.PVZResponse{Id: pvzID, City: "Москва"}, nil)
+	var payload []byte
+	mockRepo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), "product.deleted", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, p []byte) (int64, error) {
+			payload = p
+			return 1, nil
+		})
+
+	sub, err := service.SubscribeEvents(&dto.EventStreamRequest{})
+	assert.NoError(t, err)
+	defer sub.Close()
 
-	err := service.DeleteLastProduct(ctx, pvzID)
+	err = service.DeleteLastProduct(ctx, pvzID, userID)
 
 	assert.NoError(t, err)
+	event := <-sub.Events()
+	assert.Equal(t, events.ProductDeleted, event.Type)
+	assert.Equal(t, &deleted.Id, event.ProductId)
+	assert.Equal(t, deleted.ReceptionId, event.ReceptionId)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(payload, &body))
+	assert.NotContains(t, body, "userId")
+	assert.NotContains(t, body, "id")
+	assert.Equal(t, deleted.Id.String(), body["productId"])
+	assert.Equal(t, "Москва", body["city"])
 }
 
 func TestPvzService_GetPvz_ValidationError(t *testing.T) {
@@ -156,11 +190,12 @@ func TestPvzService_CloseReception_Error(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
 	ctx := context.Background()
 	pvzID := uuid.New()
 	expectedErr := errors.New("database error")
 
-	mockRepo.EXPECT().CloseReception(ctx, pvzID).Return(nil, expectedErr)
+	mockRepo.EXPECT().CloseReception(gomock.Any(), pvzID).Return(nil, expectedErr)
 
 	_, err := service.CloseReception(ctx, pvzID)
 
@@ -174,11 +209,12 @@ func TestPvzService_AddProduct_NoActiveReception(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
 	ctx := context.Background()
 	pvzId := uuid.New()
 	req := &dto.AddProductRequest{PvzId: pvzId, Type: "электроника"}
 
-	mockRepo.EXPECT().GetActiveReception(ctx, pvzId).Return(nil, repository.ErrNoActiveReception)
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzId).Return(nil, repository.ErrNoActiveReception)
 
 	_, err := service.AddProduct(ctx, req)
 
@@ -192,12 +228,13 @@ func TestPvzService_CreateReception_Error(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
 	ctx := context.Background()
 	pvzID := uuid.New()
 	req := &dto.CreateReceptionRequest{PvzId: pvzID}
 	expectedErr := errors.New("database error")
 
-	mockRepo.EXPECT().CreateReception(ctx, pvzID).Return(nil, expectedErr)
+	mockRepo.EXPECT().CreateReception(gomock.Any(), pvzID).Return(nil, expectedErr)
 
 	_, err := service.CreateReception(ctx, req)
 
@@ -259,18 +296,23 @@ func TestPvzService_GetPvz_Success(t *testing.T) {
 		EndDate:   time.Now(),
 		Page:      1,
 		Limit:     10,
+		Include:   dto.IncludeProducts,
+		Sort:      dto.SortRegistrationDate,
+		Order:     dto.OrderAsc,
 	}
-	expected := []*dto.PVZWithReceptions{
-		{
-			PVZ: dto.PVZResponse{
-				Id:               uuid.New(),
-				RegistrationDate: time.Now(),
-				City:             "Москва",
+	expected := &dto.GetPvzResponse{
+		Items: []*dto.PVZWithReceptions{
+			{
+				PVZ: dto.PVZResponse{
+					Id:               uuid.New(),
+					RegistrationDate: time.Now(),
+					City:             "Москва",
+				},
 			},
 		},
 	}
 
-	mockRepo.EXPECT().GetPvz(ctx, req.StartDate, req.EndDate, req.Page, req.Limit).Return(expected, nil)
+	mockRepo.EXPECT().GetPvz(ctx, *req).Return(expected, nil)
 
 	result, err := service.GetPvz(ctx, req)
 
@@ -284,20 +326,40 @@ func TestPvzService_CloseReception_Success(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
 	ctx := context.Background()
 	pvzID := uuid.New()
 	expected := &dto.CloseLastReceptionResponse{
 		Status: "closed",
+		Closed: true,
 	}
 
+	sub, err := service.SubscribeEvents(&dto.EventStreamRequest{})
+	assert.NoError(t, err)
+	defer sub.Close()
+
 	mockRepo.EXPECT().
-		CloseReception(ctx, pvzID).
+		CloseReception(gomock.Any(), pvzID).
 		Return(expected, nil)
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), gomock.Any()).Return(&dto.PVZResponse{City: "Москва"}, nil)
+	mockRepo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), "reception.closed", gomock.Any()).Return(int64(1), nil)
 
 	resp, err := service.CloseReception(ctx, pvzID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "closed", resp.Status)
+	assert.Len(t, sub.Events(), 1)
+
+	// A retried close finds the reception closed already and publishes nothing.
+	mockRepo.EXPECT().
+		CloseReception(gomock.Any(), pvzID).
+		Return(&dto.CloseLastReceptionResponse{Status: "closed"}, nil)
+
+	resp, err = service.CloseReception(ctx, pvzID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "closed", resp.Status)
+	assert.Len(t, sub.Events(), 1)
 }
 
 func TestPvzService_AddProduct_Success(t *testing.T) {
@@ -306,6 +368,7 @@ func TestPvzService_AddProduct_Success(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
 	ctx := context.Background()
 	pvzID := uuid.New()
 	req := &dto.AddProductRequest{
@@ -318,12 +381,14 @@ func TestPvzService_AddProduct_Success(t *testing.T) {
 	}
 
 	mockRepo.EXPECT().
-		GetActiveReception(ctx, pvzID).
+		GetActiveReception(gomock.Any(), pvzID).
 		Return(reception, nil)
 
 	mockRepo.EXPECT().
-		CreateProduct(ctx, req.Type, reception.Id).
+		CreateProduct(gomock.Any(), req.Type, reception.Id).
 		Return(expected, nil)
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzID).Return(&dto.PVZResponse{Id: pvzID, City: "Москва"}, nil)
+	mockRepo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), "product.added", gomock.Any()).Return(int64(1), nil)
 
 	resp, err := service.AddProduct(ctx, req)
 
@@ -472,18 +537,19 @@ func TestPvzService_DeleteLastProduct_Error(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
 	pvzID := uuid.New()
 	expectedErr := errors.New("delete error")
 
 	ctx := context.Background()
 	mockRepo.EXPECT().
-		GetActiveReception(ctx, pvzID).
+		GetActiveReception(gomock.Any(), pvzID).
 		Return(&models.Reception{Id: uuid.New()}, nil)
 	mockRepo.EXPECT().
-		DeleteLastProduct(ctx, pvzID).
-		Return(expectedErr)
+		DeleteLastProduct(gomock.Any(), pvzID, uuid.Nil).
+		Return(nil, expectedErr)
 
-	err := service.DeleteLastProduct(ctx, pvzID)
+	err := service.DeleteLastProduct(ctx, pvzID, uuid.Nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
@@ -681,3 +747,422 @@ func TestPvzService_DummyLogin(t *testing.T) {
 		assert.ErrorIs(t, err, expectedErr)
 	})
 }
+
+func TestPvzService_GetProductLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	ctx := context.Background()
+	productId := uuid.New()
+	info := &dto.ProductLabel{
+		Product:   dto.ProductResponse{Id: productId, Type: "обувь", DateTime: time.Now()},
+		Reception: dto.ReceptionResponse{Id: uuid.New(), DateTime: time.Now()},
+		PVZ:       dto.PVZResponse{Id: uuid.New(), City: "Казань"},
+	}
+
+	t.Run("zpl", func(t *testing.T) {
+		mockRepo.EXPECT().GetProductLabel(ctx, productId).Return(info, nil)
+
+		resp, err := service.GetProductLabel(ctx, productId, "zpl")
+		assert.NoError(t, err)
+		assert.Equal(t, "application/x-zpl", resp.ContentType)
+		assert.Equal(t, "label-"+productId.String()+".zpl", resp.FileName)
+		assert.Contains(t, string(resp.Data), "Казань")
+	})
+
+	t.Run("png by default", func(t *testing.T) {
+		mockRepo.EXPECT().GetProductLabel(ctx, productId).Return(info, nil)
+
+		resp, err := service.GetProductLabel(ctx, productId, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", resp.ContentType)
+		assert.NotEmpty(t, resp.Data)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := service.GetProductLabel(ctx, productId, "pdf")
+		assert.ErrorIs(t, err, label.ErrInvalidFormat)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetProductLabel(ctx, productId).Return(nil, repository.ErrProductNotFound)
+
+		_, err := service.GetProductLabel(ctx, productId, "zpl")
+		assert.ErrorIs(t, err, repository.ErrProductNotFound)
+	})
+}
+
+func TestPvzService_GetReceptionLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	ctx := context.Background()
+	receptionId := uuid.New()
+	infos := []*dto.ProductLabel{
+		{Product: dto.ProductResponse{Id: uuid.New(), Type: "обувь"}, Reception: dto.ReceptionResponse{Id: receptionId}},
+		{Product: dto.ProductResponse{Id: uuid.New(), Type: "одежда"}, Reception: dto.ReceptionResponse{Id: receptionId}},
+	}
+
+	mockRepo.EXPECT().GetReceptionLabels(ctx, receptionId).Return(infos, nil).Times(2)
+
+	resp, err := service.GetReceptionLabels(ctx, receptionId, "zpl")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(resp.Data), "^XA"))
+
+	resp, err = service.GetReceptionLabels(ctx, receptionId, "png")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/zip", resp.ContentType)
+	assert.Equal(t, "labels-"+receptionId.String()+".zip", resp.FileName)
+}
+
+func TestPvzService_FindProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	ctx := context.Background()
+	productId := uuid.MustParse("87c17529-99bb-4815-be06-900c4612902a")
+	expected := &dto.ProductDetailsResponse{
+		Product: dto.ProductResponse{Id: productId},
+		Status:  string(models.ProductStatusInReception),
+	}
+
+	mockRepo.EXPECT().GetProductDetails(ctx, productId).Return(expected, nil).Times(2)
+
+	resp, err := service.FindProduct(ctx, &dto.GetProductRequest{Barcode: "87c1752999bb4815be06900c4612902a"})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, resp)
+
+	resp, err = service.FindProduct(ctx, &dto.GetProductRequest{Barcode: productId.String()})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, resp)
+
+	_, err = service.FindProduct(ctx, &dto.GetProductRequest{Barcode: "4600000000000"})
+	assert.ErrorIs(t, err, ErrInvalidBarcode)
+}
+
+func TestPvzService_RestoreLastProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
+	ctx := context.Background()
+	pvzID := uuid.New()
+	userID := uuid.New()
+	expected := &dto.ProductResponse{Id: uuid.New(), Type: "обувь"}
+
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzID).Return(&models.Reception{}, nil)
+	mockRepo.EXPECT().RestoreLastProduct(gomock.Any(), pvzID, userID).Return(expected, nil)
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzID).Return(&dto.PVZResponse{Id: pvzID, City: "Москва"}, nil)
+	mockRepo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), "product.restored", gomock.Any()).Return(int64(1), nil)
+
+	resp, err := service.RestoreLastProduct(ctx, pvzID, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, resp)
+
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzID).Return(nil, repository.ErrNoActiveReception)
+
+	_, err = service.RestoreLastProduct(ctx, pvzID, userID)
+	assert.ErrorIs(t, err, repository.ErrNoActiveReception)
+}
+
+func TestValidateGetPvzRequest_Cursor(t *testing.T) {
+	cursor := pagination.Cursor{Sort: dto.SortRegistrationDate, Value: "2025-04-01 10:00:00+00", Id: uuid.New()}.Encode()
+
+	assert.NoError(t, ValidateGetPvzRequest(&dto.GetPvzRequest{Cursor: cursor}))
+	assert.ErrorIs(t, ValidateGetPvzRequest(&dto.GetPvzRequest{Cursor: cursor, Page: 2}), ErrCursorWithPage)
+	assert.ErrorIs(t, ValidateGetPvzRequest(&dto.GetPvzRequest{Cursor: "garbage"}), pagination.ErrInvalidCursor)
+	assert.ErrorIs(t, ValidateGetPvzRequest(&dto.GetPvzRequest{Cursor: cursor, Order: dto.OrderDesc}), ErrCursorSortMismatch)
+	assert.ErrorIs(t, ValidateGetPvzRequest(&dto.GetPvzRequest{Cursor: cursor, Sort: dto.SortCity}), ErrCursorSortMismatch)
+}
+
+func TestValidateGetPvzRequest_Filters(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name    string
+		request dto.GetPvzRequest
+		wantErr error
+	}{
+		{"valid filters", dto.GetPvzRequest{Cities: []string{"Москва", "Казань"}, ReceptionStatus: "close", ProductType: "обувь"}, nil},
+		{"unknown city", dto.GetPvzRequest{Cities: []string{"Москва", "Тверь"}}, ErrInvalidCity},
+		{"unknown status", dto.GetPvzRequest{ReceptionStatus: "open"}, ErrInvalidStatus},
+		{"unknown product type", dto.GetPvzRequest{ProductType: "мебель"}, ErrInvalidProductType},
+		{"inverted registration range", dto.GetPvzRequest{RegisteredFrom: now, RegisteredTo: now.Add(-time.Hour)}, ErrInvalidDateRange},
+		{"include headers only", dto.GetPvzRequest{Include: dto.IncludeNone}, nil},
+		{"unknown include", dto.GetPvzRequest{Include: "products"}, ErrInvalidInclude},
+		{"sort by product count", dto.GetPvzRequest{Sort: dto.SortProductCount, Order: dto.OrderDesc}, nil},
+		{"unknown sort", dto.GetPvzRequest{Sort: "id; DROP TABLE pvz"}, ErrInvalidSort},
+		{"unknown order", dto.GetPvzRequest{Order: "up"}, ErrInvalidOrder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGetPvzRequest(&tt.request)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPvzService_ReserveIdempotencyKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{IdempotencyTTL: time.Hour})
+	ctx := context.Background()
+	record := dto.IdempotencyRecord{UserId: uuid.New(), Key: "key", RequestHash: "hash"}
+
+	mockRepo.EXPECT().ReserveIdempotencyKey(ctx, record, time.Hour).Return(nil, nil)
+	stored, err := service.ReserveIdempotencyKey(ctx, record)
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+
+	done := &dto.IdempotencyRecord{RequestHash: "hash", StatusCode: 201, Body: []byte("{}")}
+	mockRepo.EXPECT().ReserveIdempotencyKey(ctx, record, time.Hour).Return(done, nil)
+	stored, err = service.ReserveIdempotencyKey(ctx, record)
+	assert.NoError(t, err)
+	assert.Equal(t, done, stored)
+
+	mockRepo.EXPECT().ReserveIdempotencyKey(ctx, record, time.Hour).Return(&dto.IdempotencyRecord{RequestHash: "other", StatusCode: 201}, nil)
+	_, err = service.ReserveIdempotencyKey(ctx, record)
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+
+	mockRepo.EXPECT().ReserveIdempotencyKey(ctx, record, time.Hour).Return(&dto.IdempotencyRecord{RequestHash: "hash"}, nil)
+	_, err = service.ReserveIdempotencyKey(ctx, record)
+	assert.ErrorIs(t, err, ErrIdempotencyKeyInProgress)
+}
+
+func TestPvzService_PublishesEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
+	ctx := context.Background()
+	pvzID := uuid.New()
+	reception := &models.Reception{Id: uuid.New(), PvzId: pvzID}
+	sub, err := service.SubscribeEvents(&dto.EventStreamRequest{Cities: []string{"Казань"}})
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzID).Return(reception, nil).Times(2)
+	mockRepo.EXPECT().CreateProduct(gomock.Any(), "обувь", reception.Id).
+		Return(&dto.AddProductResponse{Id: uuid.New(), Type: "обувь", ReceptionId: reception.Id}, nil).Times(2)
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzID).Return(&dto.PVZResponse{Id: pvzID, City: "Казань"}, nil).Times(1)
+	mockRepo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), "product.added", gomock.Any()).Return(int64(1), nil).Times(2)
+
+	for range 2 {
+		_, err := service.AddProduct(ctx, &dto.AddProductRequest{PvzId: pvzID, Type: "обувь"})
+		assert.NoError(t, err)
+	}
+
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzID).Return(reception, nil)
+	mockRepo.EXPECT().DeleteLastProduct(gomock.Any(), pvzID, gomock.Any()).Return(nil, repository.ErrProductNotFound)
+	assert.Error(t, service.DeleteLastProduct(ctx, pvzID, uuid.New()))
+
+	assert.Len(t, sub.Events(), 2)
+	event := <-sub.Events()
+	assert.Equal(t, events.ProductAdded, event.Type)
+	assert.Equal(t, "Казань", event.City)
+	assert.Equal(t, reception.Id, event.ReceptionId)
+}
+
+func TestPvzService_EnqueueFailureFailsChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	dbErr := errors.New("connection reset")
+	pvzID := uuid.New()
+	sub, err := service.SubscribeEvents(&dto.EventStreamRequest{})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	mockRepo.EXPECT().InTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+	mockRepo.EXPECT().CreateReception(gomock.Any(), pvzID).
+		Return(&dto.CreateReceptionResponse{Id: uuid.New(), PvzId: pvzID}, nil)
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzID).Return(&dto.PVZResponse{Id: pvzID, City: "Казань"}, nil)
+	mockRepo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), "reception.opened", gomock.Any()).Return(int64(0), dbErr)
+
+	_, err = service.CreateReception(context.Background(), &dto.CreateReceptionRequest{PvzId: pvzID})
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Empty(t, sub.Events())
+}
+
+func TestPvzService_IssueAndMoveProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	expectInTx(mockRepo)
+	ctx := context.Background()
+	productId, userId := uuid.New(), uuid.New()
+	origin, target := uuid.New(), uuid.New()
+	details := func(status string, events ...dto.ProductEventResponse) *dto.ProductDetailsResponse {
+		return &dto.ProductDetailsResponse{
+			Product:  dto.ProductResponse{Id: productId},
+			PVZ:      dto.PVZResponse{Id: origin},
+			Status:   status,
+			Timeline: append([]dto.ProductEventResponse{{Type: "added"}}, events...),
+		}
+	}
+	moved := dto.ProductEventResponse{Type: "moved", PvzId: &target}
+
+	t.Run("move", func(t *testing.T) {
+		mockRepo.EXPECT().LockProduct(gomock.Any(), productId).Return(nil)
+		mockRepo.EXPECT().GetProductDetails(gomock.Any(), productId).Return(details("accepted"), nil)
+		mockRepo.EXPECT().GetPvzById(gomock.Any(), target).Return(&dto.PVZResponse{Id: target}, nil)
+		mockRepo.EXPECT().AddProductEvent(gomock.Any(), productId, models.ProductEventMoved, userId, &target).Return(nil)
+		mockRepo.EXPECT().GetProductDetails(gomock.Any(), productId).Return(details("moved", moved), nil)
+
+		resp, err := service.MoveProduct(ctx, productId, &dto.MoveProductRequest{PvzId: target}, userId)
+		require.NoError(t, err)
+		assert.Equal(t, "moved", resp.Status)
+	})
+
+	t.Run("move to where it already is", func(t *testing.T) {
+		mockRepo.EXPECT().LockProduct(gomock.Any(), productId).Return(nil)
+		mockRepo.EXPECT().GetProductDetails(gomock.Any(), productId).Return(details("moved", moved), nil)
+
+		_, err := service.MoveProduct(ctx, productId, &dto.MoveProductRequest{PvzId: target}, userId)
+		assert.ErrorIs(t, err, ErrSamePvz)
+	})
+
+	t.Run("issue a moved product", func(t *testing.T) {
+		mockRepo.EXPECT().LockProduct(gomock.Any(), productId).Return(nil)
+		mockRepo.EXPECT().GetProductDetails(gomock.Any(), productId).Return(details("moved", moved), nil)
+		mockRepo.EXPECT().AddProductEvent(gomock.Any(), productId, models.ProductEventIssued, userId, nil).Return(nil)
+		mockRepo.EXPECT().GetProductDetails(gomock.Any(), productId).
+			Return(details("issued", moved, dto.ProductEventResponse{Type: "issued"}), nil)
+
+		resp, err := service.IssueProduct(ctx, productId, userId)
+		require.NoError(t, err)
+		assert.Equal(t, "issued", resp.Status)
+	})
+
+	for _, status := range []string{"in_reception", "deleted", "issued"} {
+		t.Run("issue "+status, func(t *testing.T) {
+			mockRepo.EXPECT().LockProduct(gomock.Any(), productId).Return(nil)
+			mockRepo.EXPECT().GetProductDetails(gomock.Any(), productId).Return(details(status), nil)
+
+			_, err := service.IssueProduct(ctx, productId, userId)
+			assert.ErrorIs(t, err, ErrProductNotAvailable)
+		})
+	}
+
+	t.Run("missing product", func(t *testing.T) {
+		mockRepo.EXPECT().LockProduct(gomock.Any(), productId).Return(repository.ErrProductNotFound)
+
+		_, err := service.IssueProduct(ctx, productId, userId)
+		assert.ErrorIs(t, err, repository.ErrProductNotFound)
+	})
+}
+
+func TestValidateEventStreamRequest(t *testing.T) {
+	pvzID := uuid.New()
+	filter, after, err := ValidateEventStreamRequest(&dto.EventStreamRequest{
+		PvzIds:      []string{pvzID.String()},
+		Types:       []string{"product.added"},
+		LastEventId: "42",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, events.Filter{PvzIds: []uuid.UUID{pvzID}, Types: []events.Type{events.ProductAdded}}, filter)
+	assert.Equal(t, uint64(42), *after)
+
+	tests := []struct {
+		name    string
+		request dto.EventStreamRequest
+		err     error
+	}{
+		{"invalid pvz", dto.EventStreamRequest{PvzIds: []string{"42"}}, ErrInvalidUUID},
+		{"invalid city", dto.EventStreamRequest{Cities: []string{"Тверь"}}, ErrInvalidCity},
+		{"invalid type", dto.EventStreamRequest{Types: []string{"stream.reset"}}, ErrInvalidEventType},
+		{"invalid last event id", dto.EventStreamRequest{LastEventId: "-1"}, ErrInvalidEventId},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ValidateEventStreamRequest(&tt.request)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestValidateReceptionReportRequest(t *testing.T) {
+	pvzID := uuid.New()
+	now := time.Now()
+	filter, err := ValidateReceptionReportRequest(&dto.ReceptionReportRequest{
+		StartDate: now.Add(-time.Hour),
+		EndDate:   now,
+		Cities:    []string{"Москва"},
+		PvzIds:    []string{pvzID.String()},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{pvzID}, filter.PvzIds)
+
+	_, err = ValidateReceptionReportRequest(&dto.ReceptionReportRequest{StartDate: now, EndDate: now.Add(-time.Hour)})
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+	_, err = ValidateReceptionReportRequest(&dto.ReceptionReportRequest{Cities: []string{"Тверь"}})
+	assert.ErrorIs(t, err, ErrInvalidCity)
+	_, err = ValidateReceptionReportRequest(&dto.ReceptionReportRequest{PvzIds: []string{"bad"}})
+	assert.ErrorIs(t, err, ErrInvalidUUID)
+}
+
+func TestValidateIntakeReportRequest(t *testing.T) {
+	filter, err := ValidateIntakeReportRequest(&dto.IntakeReportRequest{GroupBy: []string{"city, type", "month"}})
+	require.NoError(t, err)
+	assert.Equal(t, []models.IntakeDimension{models.IntakeByCity, models.IntakeByType, models.IntakeByMonth}, filter.GroupBy)
+
+	tests := []struct {
+		name    string
+		request dto.IntakeReportRequest
+		err     error
+	}{
+		{"unknown dimension", dto.IntakeReportRequest{GroupBy: []string{"year"}}, ErrInvalidGroupBy},
+		{"repeated dimension", dto.IntakeReportRequest{GroupBy: []string{"city", "city"}}, ErrInvalidGroupBy},
+		{"two periods", dto.IntakeReportRequest{GroupBy: []string{"day,week"}}, ErrManyIntakePeriods},
+		{"city", dto.IntakeReportRequest{Cities: []string{"Тверь"}}, ErrInvalidCity},
+		{"date range", dto.IntakeReportRequest{StartDate: time.Now(), EndDate: time.Now().Add(-time.Hour)}, ErrInvalidDateRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateIntakeReportRequest(&tt.request)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestPvzService_GetIntakeReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	city := "Москва"
+
+	mockRepo.EXPECT().GetIntakeReport(gomock.Any(), dto.IntakeReportFilter{GroupBy: []models.IntakeDimension{models.IntakeByCity}}).
+		Return([]dto.IntakeReportRow{{City: &city, Products: 3, Receptions: 1}}, nil)
+
+	response, err := service.GetIntakeReport(context.Background(), &dto.IntakeReportRequest{GroupBy: []string{"city"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"city"}, response.GroupBy)
+	assert.Equal(t, int64(3), response.Rows[0].Products)
+}
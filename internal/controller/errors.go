@@ -1,6 +1,9 @@
 package controller
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrShortPassword      = errors.New("password is too short")
@@ -16,4 +19,75 @@ var (
 	ErrInvalidDateRange   = errors.New("endDate must be ≥ startDate")
 	ErrWeakPassword       = errors.New("password must be ≥ 8 characters with special chars")
 	ErrInvalidRole        = errors.New("invalid role, allowed: moderator, employee")
+	ErrInvalidBarcode     = errors.New("invalid barcode, expected product UUID")
+	ErrCursorWithPage     = errors.New("cursor cannot be combined with page")
+	ErrInvalidInclude     = errors.New("include must be one of: none, receptions, receptions.products")
+	ErrInvalidSort        = errors.New("sort must be one of: registrationDate, city, lastReceptionAt, productCount")
+	ErrInvalidOrder       = errors.New("order must be asc or desc")
+	ErrCursorSortMismatch = errors.New("cursor was issued for a different sort")
+	ErrInvalidEventType   = errors.New("type must be one of: reception.opened, reception.closed, product.added, product.deleted, product.restored")
+	ErrInvalidEventId     = errors.New("last event id must be a non-negative integer")
+	ErrInvalidWebhookURL  = errors.New("url must be an absolute http or https URL")
+	ErrEmptyEventTypes    = errors.New("at least one event type is required")
+	ErrShortWebhookSecret = errors.New("secret must be at least 16 characters")
+
+	ErrInvalidDeliveryStatus = errors.New("status must be one of: pending, delivered, dead")
+
+	ErrLongExternalCode  = errors.New("external code must be at most 64 characters")
+	ErrInvalidDate       = errors.New("date must be RFC 3339, YYYY-MM-DD or DD.MM.YYYY")
+	ErrInvalidImportFile = errors.New("import file is not a valid CSV with a city column")
+	ErrTooManyImportRows = errors.New("import file has too many rows")
+	ErrInvalidImportRows = errors.New("import has invalid rows, nothing was imported")
+
+	ErrEmptySyncOperations   = errors.New("at least one operation is required")
+	ErrTooManySyncOperations = errors.New("too many operations in one sync")
+	ErrInvalidSyncOperation  = errors.New("type must be one of: open_reception, add_product, delete_last_product, close_reception")
+	ErrDuplicateClientId     = errors.New("clientId is used by more than one operation")
+	ErrReceptionChanged      = errors.New("reception was closed or replaced on the server after the operation")
+
+	ErrProductNotAvailable = errors.New("product is not waiting at a pvz: it is in reception, deleted or issued")
+	ErrSamePvz             = errors.New("product is already at this pvz")
+
+	ErrInvalidGroupBy    = errors.New("groupBy must list distinct dimensions of: city, pvz, type, day, week, month")
+	ErrManyIntakePeriods = errors.New("groupBy may contain only one of: day, week, month")
+
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
 )
+
+// FieldError ties a validation error to the request field that caused it.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+func invalidField(field string, err error) error {
+	return &FieldError{Field: field, Err: err}
+}
+
+// RowError is a failed row of an import, Line counts the header as line 1.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+// ImportError rejects a whole import and lists every row that caused it.
+type ImportError struct {
+	Rows []RowError
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("%s: %d rows", ErrInvalidImportRows, len(e.Rows))
+}
+
+func (e *ImportError) Unwrap() error {
+	return ErrInvalidImportRows
+}
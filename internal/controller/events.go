@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/sirupsen/logrus"
+)
+
+type eventBufferKey struct{}
+
+// withEventBuffer makes publish collect events into buf instead of sending
+// them, for changes that are not committed yet.
+func withEventBuffer(ctx context.Context, buf *[]events.Event) context.Context {
+	return context.WithValue(ctx, eventBufferKey{}, buf)
+}
+
+// withEvents runs a change and the events it publishes in one transaction,
+// then sends the events to subscribers once it commits. Inside a sync batch
+// the change joins the batch transaction and its events wait for the batch.
+func (p *PvzService) withEvents(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(eventBufferKey{}).(*[]events.Event); ok {
+		return fn(ctx)
+	}
+	var buffered []events.Event
+	if err := p.repo.InTx(withEventBuffer(ctx, &buffered), fn); err != nil {
+		return err
+	}
+	for _, event := range buffered {
+		p.events.Publish(event)
+	}
+	return nil
+}
+
+// publish queues event for webhooks in the transaction of the change, so a
+// delivery exists if and only if the change is committed, and holds it back
+// from subscribers until then. A failed city lookup only leaves the event
+// without a city, it never fails the request.
+func (p *PvzService) publish(ctx context.Context, event events.Event) error {
+	const op = "internal.controller.publish"
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	city, err := p.pvzCity(ctx, event.PvzId)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"event": op, "pvzId": event.PvzId}).Warn(err)
+	}
+	event.City = city
+	if err := p.enqueueWebhooks(ctx, event); err != nil {
+		return err
+	}
+	if buf, ok := ctx.Value(eventBufferKey{}).(*[]events.Event); ok {
+		*buf = append(*buf, event)
+		return nil
+	}
+	p.events.Publish(event)
+	return nil
+}
+
+func (p *PvzService) pvzCity(ctx context.Context, pvzId uuid.UUID) (string, error) {
+	if city, ok := p.cities.Load(pvzId); ok {
+		return city.(string), nil
+	}
+	pvz, err := p.repo.GetPvzById(ctx, pvzId)
+	if err != nil {
+		return "", err
+	}
+	p.cities.Store(pvzId, pvz.City)
+	return pvz.City, nil
+}
+
+// SubscribeEvents subscribes to reception activity. The caller must close
+// the subscription.
+func (p *PvzService) SubscribeEvents(request *dto.EventStreamRequest) (*events.Subscription, error) {
+	filter, after, err := ValidateEventStreamRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	return p.events.Subscribe(filter, after), nil
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+// ReserveIdempotencyKey returns nil when the request should be processed, or
+// the stored response when it is a retry of an already finished request.
+func (p *PvzService) ReserveIdempotencyKey(ctx context.Context, record dto.IdempotencyRecord) (*dto.IdempotencyRecord, error) {
+	ttl := p.cfg.IdempotencyTTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+
+	stored, err := p.repo.ReserveIdempotencyKey(ctx, record, ttl)
+	if err != nil || stored == nil {
+		return nil, err
+	}
+	if stored.RequestHash != record.RequestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if stored.StatusCode == 0 {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return stored, nil
+}
+
+func (p *PvzService) SaveIdempotencyResponse(ctx context.Context, record dto.IdempotencyRecord) error {
+	return p.repo.SaveIdempotencyResponse(ctx, record)
+}
+
+func (p *PvzService) ReleaseIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) error {
+	return p.repo.DeleteIdempotencyKey(ctx, userId, key)
+}
+
+func (p *PvzService) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	return p.repo.DeleteExpiredIdempotencyKeys(ctx)
+}
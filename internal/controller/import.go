@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/metrics"
+	"github.com/senorUVE/pvz_service/internal/models"
+)
+
+const (
+	maxImportRows = 10000
+
+	importCreated = "created"
+	importSkipped = "skipped"
+)
+
+var importDateLayouts = []string{time.RFC3339, time.DateOnly, "02.01.2006"}
+
+type importRow struct {
+	line    int
+	request dto.PvzCreateRequest
+}
+
+// ImportPvz creates PVZs from CSV with a header row. The city column is
+// required, registrationDate and externalCode are optional and other columns
+// are ignored. Either every row is valid and the import runs in a single
+// transaction, or nothing is imported and an *ImportError lists the rows.
+func (p *PvzService) ImportPvz(ctx context.Context, r io.Reader, request *dto.PvzImportRequest) (*dto.PvzImportResponse, error) {
+	rows, rowErrs, err := parsePvzImport(r)
+	if err != nil {
+		return nil, err
+	}
+
+	pvzs := make([]models.PVZ, len(rows))
+	now := time.Now().UTC()
+	for i, row := range rows {
+		if err := ValidatePvzCreateRequest(&row.request); err != nil {
+			rowErrs = append(rowErrs, RowError{Line: row.line, Err: err})
+			continue
+		}
+		pvzs[i] = models.PVZ{
+			Id:               uuid.New(),
+			RegistrationDate: row.request.RegistrationDate,
+			City:             models.City(row.request.City),
+			ExternalCode:     row.request.ExternalCode,
+		}
+		if pvzs[i].RegistrationDate.IsZero() {
+			pvzs[i].RegistrationDate = now
+		}
+	}
+	if len(rowErrs) > 0 {
+		slices.SortFunc(rowErrs, func(a, b RowError) int { return a.Line - b.Line })
+		return nil, &ImportError{Rows: rowErrs}
+	}
+
+	created, err := p.repo.ImportPvz(ctx, pvzs, request.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.PvzImportResponse{DryRun: request.DryRun, Rows: make([]dto.PvzImportRow, len(rows))}
+	for i, row := range rows {
+		result := dto.PvzImportRow{
+			Line:         row.line,
+			Status:       importSkipped,
+			City:         row.request.City,
+			ExternalCode: row.request.ExternalCode,
+		}
+		if created[i] {
+			result.Status = importCreated
+			response.Created++
+			if !request.DryRun {
+				result.Id = &pvzs[i].Id
+				metrics.IncPvzCreated()
+			}
+		} else {
+			response.Skipped++
+		}
+		response.Rows[i] = result
+	}
+	return response, nil
+}
+
+// parsePvzImport returns the non-empty rows that could be parsed and the
+// errors of those that could not.
+func parsePvzImport(r io.Reader) ([]importRow, []RowError, error) {
+	br := bufio.NewReader(r)
+	header, err := br.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, nil, err
+	}
+	header = strings.TrimPrefix(header, "\ufeff")
+
+	reader := csv.NewReader(io.MultiReader(strings.NewReader(header), br))
+	// Spreadsheets saved with a Russian locale separate fields with ';'.
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns, err := reader.Read()
+	if err != nil {
+		return nil, nil, invalidField("file", ErrInvalidImportFile)
+	}
+	index := map[string]int{}
+	for i, column := range columns {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := index["city"]; !ok {
+		return nil, nil, invalidField("file", ErrInvalidImportFile)
+	}
+	value := func(record []string, column string) string {
+		if i, ok := index[strings.ToLower(column)]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var (
+		rows    []importRow
+		rowErrs []RowError
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, nil, invalidField("file", fmt.Errorf("%w: line %d: %v", ErrInvalidImportFile, parseErr.Line, parseErr.Err))
+			}
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows)+len(rowErrs) == maxImportRows {
+			return nil, nil, invalidField("file", ErrTooManyImportRows)
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+
+		row := importRow{
+			line: line,
+			request: dto.PvzCreateRequest{
+				City:         value(record, "city"),
+				ExternalCode: value(record, "externalCode"),
+			},
+		}
+		if date := value(record, "registrationDate"); date != "" {
+			row.request.RegistrationDate, err = parseImportDate(date)
+			if err != nil {
+				rowErrs = append(rowErrs, RowError{Line: line, Err: invalidField("registrationDate", ErrInvalidDate)})
+				continue
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrs, nil
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, ErrInvalidDate
+}
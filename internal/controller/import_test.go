@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPvzService_ImportPvz(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	ctx := context.Background()
+
+	t.Run("creates and skips", func(t *testing.T) {
+		file := "\ufeffCity;externalCode;registrationDate;note\n" +
+			"Москва;MSK-1;2024-05-01;первый\n" +
+			"\n" +
+			"Казань;KZN-1;;\n"
+		mockRepo.EXPECT().ImportPvz(ctx, gomock.Any(), false).
+			DoAndReturn(func(_ context.Context, pvzs []models.PVZ, _ bool) ([]bool, error) {
+				require.Len(t, pvzs, 2)
+				assert.Equal(t, models.City("Москва"), pvzs[0].City)
+				assert.Equal(t, "MSK-1", pvzs[0].ExternalCode)
+				assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), pvzs[0].RegistrationDate)
+				assert.False(t, pvzs[1].RegistrationDate.IsZero())
+				return []bool{true, false}, nil
+			})
+
+		response, err := service.ImportPvz(ctx, strings.NewReader(file), &dto.PvzImportRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Created)
+		assert.Equal(t, 1, response.Skipped)
+		assert.Equal(t, 2, response.Rows[0].Line)
+		assert.NotNil(t, response.Rows[0].Id)
+		assert.Equal(t, "skipped", response.Rows[1].Status)
+		assert.Equal(t, 4, response.Rows[1].Line)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		mockRepo.EXPECT().ImportPvz(ctx, gomock.Any(), true).Return([]bool{true}, nil)
+
+		response, err := service.ImportPvz(ctx, strings.NewReader("city\nКазань\n"), &dto.PvzImportRequest{DryRun: true})
+		require.NoError(t, err)
+		assert.True(t, response.DryRun)
+		assert.Equal(t, 1, response.Created)
+		assert.Nil(t, response.Rows[0].Id)
+	})
+
+	t.Run("invalid rows reject the import", func(t *testing.T) {
+		file := "city,registrationDate,externalCode\n" +
+			"Тверь,,\n" +
+			"Москва,вчера,\n" +
+			"Казань,,\n" +
+			"Москва,2999-01-01,\n"
+
+		_, err := service.ImportPvz(ctx, strings.NewReader(file), &dto.PvzImportRequest{})
+		var importErr *ImportError
+		require.ErrorAs(t, err, &importErr)
+		assert.ErrorIs(t, err, ErrInvalidImportRows)
+		require.Len(t, importErr.Rows, 3)
+		assert.Equal(t, 2, importErr.Rows[0].Line)
+		assert.ErrorIs(t, importErr.Rows[0].Err, ErrInvalidCity)
+		assert.Equal(t, 3, importErr.Rows[1].Line)
+		assert.ErrorIs(t, importErr.Rows[1].Err, ErrInvalidDate)
+		assert.Equal(t, 5, importErr.Rows[2].Line)
+		assert.ErrorIs(t, importErr.Rows[2].Err, ErrFutureDate)
+	})
+
+	t.Run("missing city column", func(t *testing.T) {
+		_, err := service.ImportPvz(ctx, strings.NewReader("name,code\nx,y\n"), &dto.PvzImportRequest{})
+		assert.ErrorIs(t, err, ErrInvalidImportFile)
+	})
+
+	t.Run("malformed csv", func(t *testing.T) {
+		_, err := service.ImportPvz(ctx, strings.NewReader("city\n\"Москва\n"), &dto.PvzImportRequest{})
+		assert.ErrorIs(t, err, ErrInvalidImportFile)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		dbErr := errors.New("database error")
+		mockRepo.EXPECT().ImportPvz(ctx, gomock.Any(), false).Return(nil, dbErr)
+
+		_, err := service.ImportPvz(ctx, strings.NewReader("city\nКазань\n"), &dto.PvzImportRequest{})
+		assert.ErrorIs(t, err, dbErr)
+	})
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+// ExportReceptions validates the request and then streams report rows to fn
+// until they run out or fn fails.
+func (p *PvzService) ExportReceptions(ctx context.Context, request *dto.ReceptionReportRequest, fn func(dto.ReceptionReportRow) error) error {
+	filter, err := ValidateReceptionReportRequest(request)
+	if err != nil {
+		return err
+	}
+	return p.repo.StreamReceptionReport(ctx, filter, fn)
+}
+
+// GetIntakeReport reads pvz_daily_stats when the requested range allows,
+// and aggregates products directly otherwise.
+func (p *PvzService) GetIntakeReport(ctx context.Context, request *dto.IntakeReportRequest) (*dto.IntakeReportResponse, error) {
+	filter, err := ValidateIntakeReportRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	firstDay, lastDay, daily, err := p.dailyStatsDays(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []dto.IntakeReportRow
+	if daily {
+		rows, err = p.repo.GetDailyIntakeReport(ctx, filter, firstDay, lastDay)
+	} else {
+		rows, err = p.repo.GetIntakeReport(ctx, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.IntakeReportResponse{GroupBy: make([]string, len(filter.GroupBy)), Rows: rows}
+	for i, dimension := range filter.GroupBy {
+		response.GroupBy[i] = dimension.String()
+	}
+	return response, nil
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/models"
+)
+
+// maxZoneOffset covers every UTC offset, so that a backfill by UTC
+// timestamps reaches all activity of the requested local days.
+const maxZoneOffset = 14 * time.Hour
+
+// RefreshDailyStats rolls up activity since the previous refresh.
+func (p *PvzService) RefreshDailyStats(ctx context.Context) (int64, error) {
+	return p.repo.RefreshRecentDailyStats(ctx, p.cfg.DailyStats.WithDefaults().Lag)
+}
+
+// BackfillDailyStats recomputes the daily stats of firstDay through lastDay.
+// Neighbouring days may be recomputed too, which is harmless.
+func (p *PvzService) BackfillDailyStats(ctx context.Context, firstDay, lastDay time.Time) (int64, error) {
+	if firstDay.IsZero() || lastDay.IsZero() {
+		return 0, ErrInvalidDate
+	}
+	if lastDay.Before(firstDay) {
+		return 0, ErrInvalidDateRange
+	}
+	from := time.Date(firstDay.Year(), firstDay.Month(), firstDay.Day(), 0, 0, 0, 0, time.UTC).Add(-maxZoneOffset)
+	to := time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day()+1, 0, 0, 0, 0, time.UTC).Add(maxZoneOffset)
+	return p.repo.BackfillDailyStats(ctx, from, to)
+}
+
+// dailyStatsDays returns the local days the daily stats must cover to
+// answer filter, and whether they can: the range has to consist of whole
+// days in the zone of every city involved, the same days in each, and end
+// before what has been rolled up settled. Product times are whole seconds,
+// so an end at 23:59:59 closes a day.
+func (p *PvzService) dailyStatsDays(ctx context.Context, filter dto.IntakeReportFilter) (time.Time, time.Time, bool, error) {
+	var none time.Time
+	if filter.EndDate.IsZero() {
+		return none, none, false, nil
+	}
+
+	cities := models.Cities
+	if len(filter.Cities) > 0 {
+		cities = make([]models.City, len(filter.Cities))
+		for i, city := range filter.Cities {
+			cities[i] = models.City(city)
+		}
+	}
+
+	var firstDay, lastDay time.Time
+	for i, city := range cities {
+		loc, err := time.LoadLocation(city.TimeZone())
+		if err != nil {
+			return none, none, false, nil
+		}
+
+		var first time.Time
+		if !filter.StartDate.IsZero() {
+			start := filter.StartDate.In(loc)
+			if !isMidnight(start) {
+				return none, none, false, nil
+			}
+			first = start
+		}
+		next := filter.EndDate.Truncate(time.Second).Add(time.Second).In(loc)
+		if !isMidnight(next) {
+			return none, none, false, nil
+		}
+		last := next.AddDate(0, 0, -1)
+
+		if i > 0 && (!sameDay(first, firstDay) || !sameDay(last, lastDay)) {
+			return none, none, false, nil
+		}
+		firstDay, lastDay = first, last
+	}
+
+	refreshedUntil, err := p.repo.DailyStatsRefreshedUntil(ctx)
+	if err != nil {
+		return none, none, false, err
+	}
+	if filter.EndDate.After(refreshedUntil.Add(-p.cfg.DailyStats.WithDefaults().Lag)) {
+		return none, none, false, nil
+	}
+	return localDate(firstDay), localDate(lastDay), true, nil
+}
+
+func isMidnight(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
+
+func sameDay(a, b time.Time) bool {
+	return localDate(a).Equal(localDate(b))
+}
+
+// localDate keeps the calendar date of t, zero stays zero.
+func localDate(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
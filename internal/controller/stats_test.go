@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPvzService_GetIntakeReportSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{DailyStats: DailyStatsConfig{Lag: time.Minute}})
+	ctx := context.Background()
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	march := &dto.IntakeReportRequest{
+		StartDate: time.Date(2025, 3, 1, 0, 0, 0, 0, moscow),
+		EndDate:   time.Date(2025, 3, 31, 23, 59, 59, 0, moscow),
+	}
+
+	t.Run("whole rolled up days read the daily stats", func(t *testing.T) {
+		mockRepo.EXPECT().DailyStatsRefreshedUntil(ctx).Return(time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC), nil)
+		mockRepo.EXPECT().GetDailyIntakeReport(ctx, gomock.Any(),
+			time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)).
+			Return([]dto.IntakeReportRow{{Products: 9}}, nil)
+
+		response, err := service.GetIntakeReport(ctx, march)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), response.Rows[0].Products)
+	})
+
+	t.Run("days not rolled up yet", func(t *testing.T) {
+		mockRepo.EXPECT().DailyStatsRefreshedUntil(ctx).Return(time.Date(2025, 4, 1, 0, 0, 0, 0, moscow), nil)
+		mockRepo.EXPECT().GetIntakeReport(ctx, gomock.Any()).Return(nil, nil)
+
+		_, err := service.GetIntakeReport(ctx, march)
+		require.NoError(t, err)
+	})
+
+	t.Run("partial day", func(t *testing.T) {
+		request := *march
+		request.StartDate = request.StartDate.Add(time.Hour)
+		mockRepo.EXPECT().GetIntakeReport(ctx, gomock.Any()).Return(nil, nil)
+
+		_, err := service.GetIntakeReport(ctx, &request)
+		require.NoError(t, err)
+	})
+
+	t.Run("days of another zone", func(t *testing.T) {
+		request := &dto.IntakeReportRequest{
+			StartDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC),
+		}
+		mockRepo.EXPECT().GetIntakeReport(ctx, gomock.Any()).Return(nil, nil)
+
+		_, err := service.GetIntakeReport(ctx, request)
+		require.NoError(t, err)
+	})
+}
+
+func TestPvzService_BackfillDailyStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	ctx := context.Background()
+	firstDay := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.EXPECT().BackfillDailyStats(ctx, firstDay.Add(-maxZoneOffset), lastDay.AddDate(0, 0, 1).Add(maxZoneOffset)).
+		Return(int64(6), nil)
+	rows, err := service.BackfillDailyStats(ctx, firstDay, lastDay)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), rows)
+
+	_, err = service.BackfillDailyStats(ctx, lastDay, firstDay)
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+}
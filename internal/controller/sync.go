@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/internal/repository"
+)
+
+const (
+	maxSyncOperations = 500
+	// maxSyncClockSkew is how far ahead of the server a scanner clock may be.
+	maxSyncClockSkew = 5 * time.Minute
+)
+
+// syncReasons turns the errors an operation may legitimately fail with into
+// the reason of a rejected result. Any other error fails the whole sync.
+var syncReasons = []struct {
+	err    error
+	reason string
+}{
+	{ErrReceptionChanged, "reception_changed"},
+	{repository.ErrNoActiveReception, "no_active_reception"},
+	{repository.ErrProductNotFound, "nothing_to_delete"},
+	{repository.ErrPVZNotFound, "pvz_not_found"},
+}
+
+// Sync replays operations a scanner recorded offline, in order and in one
+// transaction. Every operation goes through the regular service method under
+// its own savepoint, so a conflicting one is dropped without undoing the
+// others. Conflicts are resolved as follows:
+//   - open_reception while a reception is in progress adopts it (merged);
+//   - add_product, delete_last_product and close_reception only act on the
+//     reception the scanner worked with: one opened earlier in the batch, or
+//     one opened on the server before clientTime. Otherwise the reception was
+//     replaced behind the scanner's back and the operation is rejected;
+//   - close_reception of a reception that is already closed is skipped;
+//   - a clientId that was synced before returns its stored outcome as
+//     duplicate, so a batch may be resent safely.
+//
+// Webhook deliveries are queued in the transaction and events are sent to
+// subscribers once it commits.
+func (p *PvzService) Sync(ctx context.Context, request *dto.SyncRequest, userId uuid.UUID) (*dto.SyncResponse, error) {
+	if err := ValidateSyncRequest(request, time.Now()); err != nil {
+		return nil, err
+	}
+
+	response := &dto.SyncResponse{Results: make([]dto.SyncResult, 0, len(request.Operations))}
+	var published []events.Event
+	err := p.repo.InTx(ctx, func(ctx context.Context) error {
+		// opened tracks, per PVZ, the reception the batch opened or adopted.
+		opened := make(map[uuid.UUID]uuid.UUID)
+		for _, op := range request.Operations {
+			result, err := p.syncOperation(ctx, userId, op, opened, &published)
+			if err != nil {
+				return err
+			}
+			response.Results = append(response.Results, *result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range published {
+		p.events.Publish(event)
+	}
+	return response, nil
+}
+
+func (p *PvzService) syncOperation(ctx context.Context, userId uuid.UUID, op dto.SyncOperation, opened map[uuid.UUID]uuid.UUID, published *[]events.Event) (*dto.SyncResult, error) {
+	stored, err := p.repo.GetSyncResult(ctx, userId, op.ClientId)
+	if err == nil {
+		stored.Status = string(models.SyncDuplicate)
+		return stored, nil
+	}
+	if !errors.Is(err, repository.ErrSyncOperationNotFound) {
+		return nil, err
+	}
+
+	result := dto.SyncResult{ClientId: op.ClientId}
+	var buffered []events.Event
+	err = p.repo.InTx(withEventBuffer(ctx, &buffered), func(ctx context.Context) error {
+		return p.applySyncOperation(ctx, userId, op, opened, &result)
+	})
+	if err != nil {
+		reason, ok := syncReason(err)
+		if !ok {
+			return nil, err
+		}
+		result = dto.SyncResult{ClientId: op.ClientId, Status: string(models.SyncRejected), Reason: reason}
+	} else {
+		*published = append(*published, buffered...)
+	}
+
+	if err := p.repo.SaveSyncResult(ctx, userId, op, result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (p *PvzService) applySyncOperation(ctx context.Context, userId uuid.UUID, op dto.SyncOperation, opened map[uuid.UUID]uuid.UUID, result *dto.SyncResult) error {
+	result.Status = string(models.SyncApplied)
+
+	switch models.SyncOperationType(op.Type) {
+	case models.SyncOpenReception:
+		active, err := p.repo.GetActiveReception(ctx, op.PvzId)
+		if err == nil {
+			opened[op.PvzId] = active.Id
+			result.Status = string(models.SyncMerged)
+			result.ReceptionId = &active.Id
+			return nil
+		}
+		if !errors.Is(err, repository.ErrNoActiveReception) {
+			return err
+		}
+		created, err := p.CreateReception(ctx, &dto.CreateReceptionRequest{PvzId: op.PvzId})
+		if err != nil {
+			return err
+		}
+		opened[op.PvzId] = created.Id
+		result.ReceptionId = &created.Id
+
+	case models.SyncAddProduct:
+		if _, err := p.syncReception(ctx, op, opened); err != nil {
+			return err
+		}
+		created, err := p.AddProduct(ctx, &dto.AddProductRequest{Type: op.ProductType, PvzId: op.PvzId})
+		if err != nil {
+			return err
+		}
+		result.ReceptionId = &created.ReceptionId
+		result.ProductId = &created.Id
+
+	case models.SyncDeleteLastProduct:
+		active, err := p.syncReception(ctx, op, opened)
+		if err != nil {
+			return err
+		}
+		if err := p.DeleteLastProduct(ctx, op.PvzId, userId); err != nil {
+			return err
+		}
+		result.ReceptionId = &active.Id
+
+	case models.SyncCloseReception:
+		active, err := p.syncReception(ctx, op, opened)
+		if errors.Is(err, repository.ErrNoActiveReception) {
+			result.Status = string(models.SyncSkipped)
+			result.Reason = "already_closed"
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := p.CloseReception(ctx, op.PvzId); err != nil {
+			return err
+		}
+		delete(opened, op.PvzId)
+		result.ReceptionId = &active.Id
+	}
+	return nil
+}
+
+// syncReception returns the reception in progress if it is the one the
+// scanner was working with when it recorded op.
+func (p *PvzService) syncReception(ctx context.Context, op dto.SyncOperation, opened map[uuid.UUID]uuid.UUID) (*models.Reception, error) {
+	active, err := p.repo.GetActiveReception(ctx, op.PvzId)
+	if err != nil {
+		return nil, err
+	}
+	if id, ok := opened[op.PvzId]; ok {
+		if id != active.Id {
+			return nil, ErrReceptionChanged
+		}
+	} else if active.DateTime.After(op.ClientTime) {
+		return nil, ErrReceptionChanged
+	}
+	return active, nil
+}
+
+func syncReason(err error) (string, bool) {
+	for _, r := range syncReasons {
+		if errors.Is(err, r.err) {
+			return r.reason, true
+		}
+	}
+	return "", false
+}
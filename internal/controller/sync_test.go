@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/internal/repository"
+	"github.com/senorUVE/pvz_service/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectInTx(mockRepo *mocks.MockRepository) {
+	mockRepo.EXPECT().InTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+}
+
+func TestPvzService_Sync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	ctx := context.Background()
+	userId := uuid.New()
+	offline := time.Now().Add(-time.Hour)
+	pvzA, pvzB, pvzC := uuid.New(), uuid.New(), uuid.New()
+	opened := &models.Reception{Id: uuid.New(), PvzId: pvzA, DateTime: time.Now()}
+	replaced := &models.Reception{Id: uuid.New(), PvzId: pvzB, DateTime: time.Now()}
+	productId := uuid.New()
+	sub, err := service.SubscribeEvents(&dto.EventStreamRequest{})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	request := &dto.SyncRequest{Operations: []dto.SyncOperation{
+		{ClientId: uuid.New(), ClientTime: offline, Type: "open_reception", PvzId: pvzA},
+		{ClientId: uuid.New(), ClientTime: offline, Type: "add_product", PvzId: pvzA, ProductType: "обувь"},
+		{ClientId: uuid.New(), ClientTime: offline, Type: "close_reception", PvzId: pvzB},
+		{ClientId: uuid.New(), ClientTime: offline, Type: "close_reception", PvzId: pvzC},
+		{ClientId: uuid.New(), ClientTime: offline, Type: "delete_last_product", PvzId: pvzA},
+	}}
+	synced := request.Operations[4].ClientId
+
+	expectInTx(mockRepo)
+	mockRepo.EXPECT().GetSyncResult(gomock.Any(), userId, synced).
+		Return(&dto.SyncResult{ClientId: synced, Status: "applied", ReceptionId: &opened.Id}, nil)
+	mockRepo.EXPECT().GetSyncResult(gomock.Any(), userId, gomock.Any()).
+		Return(nil, repository.ErrSyncOperationNotFound).Times(4)
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzA).Return(nil, repository.ErrNoActiveReception)
+	mockRepo.EXPECT().CreateReception(gomock.Any(), pvzA).
+		Return(&dto.CreateReceptionResponse{Id: opened.Id, DateTime: opened.DateTime, PvzId: pvzA, Status: "in_progress"}, nil)
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzA).Return(opened, nil).Times(2)
+	mockRepo.EXPECT().CreateProduct(gomock.Any(), "обувь", opened.Id).
+		Return(&dto.AddProductResponse{Id: productId, Type: "обувь", ReceptionId: opened.Id}, nil)
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzB).Return(replaced, nil)
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzC).Return(nil, repository.ErrNoActiveReception)
+	mockRepo.EXPECT().SaveSyncResult(gomock.Any(), userId, gomock.Any(), gomock.Any()).Return(nil).Times(4)
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzA).Return(&dto.PVZResponse{Id: pvzA, City: "Казань"}, nil)
+	mockRepo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), nil).Times(2)
+
+	response, err := service.Sync(ctx, request, userId)
+	require.NoError(t, err)
+	require.Len(t, response.Results, 5)
+
+	assert.Equal(t, "applied", response.Results[0].Status)
+	assert.Equal(t, opened.Id, *response.Results[0].ReceptionId)
+	assert.Equal(t, "applied", response.Results[1].Status)
+	assert.Equal(t, productId, *response.Results[1].ProductId)
+	assert.Equal(t, dto.SyncResult{ClientId: request.Operations[2].ClientId, Status: "rejected", Reason: "reception_changed"}, response.Results[2])
+	assert.Equal(t, "skipped", response.Results[3].Status)
+	assert.Equal(t, "already_closed", response.Results[3].Reason)
+	assert.Equal(t, "duplicate", response.Results[4].Status)
+
+	require.Len(t, sub.Events(), 2)
+	assert.Equal(t, events.ReceptionOpened, (<-sub.Events()).Type)
+	assert.Equal(t, events.ProductAdded, (<-sub.Events()).Type)
+}
+
+func TestPvzService_SyncMergesOpenReception(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	userId, pvzId := uuid.New(), uuid.New()
+	active := &models.Reception{Id: uuid.New(), PvzId: pvzId, DateTime: time.Now()}
+	offline := time.Now().Add(-time.Hour)
+
+	expectInTx(mockRepo)
+	mockRepo.EXPECT().GetSyncResult(gomock.Any(), userId, gomock.Any()).
+		Return(nil, repository.ErrSyncOperationNotFound).Times(2)
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzId).Return(active, nil).Times(3)
+	mockRepo.EXPECT().DeleteLastProduct(gomock.Any(), pvzId, userId).Return(nil, repository.ErrProductNotFound)
+	mockRepo.EXPECT().SaveSyncResult(gomock.Any(), userId, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	response, err := service.Sync(context.Background(), &dto.SyncRequest{Operations: []dto.SyncOperation{
+		{ClientId: uuid.New(), ClientTime: offline, Type: "open_reception", PvzId: pvzId},
+		{ClientId: uuid.New(), ClientTime: offline, Type: "delete_last_product", PvzId: pvzId},
+	}}, userId)
+	require.NoError(t, err)
+	assert.Equal(t, "merged", response.Results[0].Status)
+	assert.Equal(t, active.Id, *response.Results[0].ReceptionId)
+	// The adopted reception was opened after clientTime, but the batch
+	// opened it, so the delete reaches the repository.
+	assert.Equal(t, "rejected", response.Results[1].Status)
+	assert.Equal(t, "nothing_to_delete", response.Results[1].Reason)
+}
+
+func TestPvzService_SyncFailsWholeBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewPvzService(mockRepo, nil, ServiceConfig{})
+	userId, pvzId := uuid.New(), uuid.New()
+	dbErr := errors.New("connection reset")
+
+	expectInTx(mockRepo)
+	mockRepo.EXPECT().GetSyncResult(gomock.Any(), userId, gomock.Any()).
+		Return(nil, repository.ErrSyncOperationNotFound).Times(2)
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzId).Return(nil, repository.ErrNoActiveReception)
+	mockRepo.EXPECT().CreateReception(gomock.Any(), pvzId).
+		Return(&dto.CreateReceptionResponse{Id: uuid.New(), PvzId: pvzId}, nil)
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(&dto.PVZResponse{Id: pvzId, City: "Казань"}, nil)
+	mockRepo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), "reception.opened", gomock.Any()).Return(int64(1), nil)
+	mockRepo.EXPECT().SaveSyncResult(gomock.Any(), userId, gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzId).Return(nil, dbErr)
+	sub, err := service.SubscribeEvents(&dto.EventStreamRequest{})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	_, err = service.Sync(context.Background(), &dto.SyncRequest{Operations: []dto.SyncOperation{
+		{ClientId: uuid.New(), ClientTime: time.Now(), Type: "open_reception", PvzId: pvzId},
+		{ClientId: uuid.New(), ClientTime: time.Now(), Type: "close_reception", PvzId: pvzId},
+	}}, userId)
+	// The delivery queued for the first operation is rolled back with the
+	// batch, and its event never reaches subscribers.
+	assert.ErrorIs(t, err, dbErr)
+	assert.Empty(t, sub.Events())
+}
+
+func TestValidateSyncRequest(t *testing.T) {
+	now := time.Now()
+	clientId := uuid.New()
+	op := dto.SyncOperation{ClientId: clientId, ClientTime: now, Type: "add_product", PvzId: uuid.New(), ProductType: "одежда"}
+
+	tests := []struct {
+		name  string
+		ops   []dto.SyncOperation
+		field string
+		err   error
+	}{
+		{"empty", nil, "operations", ErrEmptySyncOperations},
+		{"too many", make([]dto.SyncOperation, maxSyncOperations+1), "operations", ErrTooManySyncOperations},
+		{"duplicate client id", []dto.SyncOperation{op, op}, "operations[1].clientId", ErrDuplicateClientId},
+		{"future", []dto.SyncOperation{{ClientId: clientId, ClientTime: now.Add(time.Hour), Type: "close_reception", PvzId: op.PvzId}},
+			"operations[0].clientTime", ErrFutureDate},
+		{"unknown type", []dto.SyncOperation{{ClientId: clientId, ClientTime: now, Type: "reopen", PvzId: op.PvzId}},
+			"operations[0].type", ErrInvalidSyncOperation},
+		{"product type", []dto.SyncOperation{{ClientId: clientId, ClientTime: now, Type: "add_product", PvzId: op.PvzId}},
+			"operations[0].productType", ErrInvalidProductType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSyncRequest(&dto.SyncRequest{Operations: tt.ops}, now)
+			var fieldErr *FieldError
+			require.ErrorAs(t, err, &fieldErr)
+			assert.Equal(t, tt.field, fieldErr.Field)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	assert.NoError(t, ValidateSyncRequest(&dto.SyncRequest{Operations: []dto.SyncOperation{op}}, now))
+}
@@ -1,22 +1,29 @@
 package controller
 
 import (
+	"fmt"
+	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/senorUVE/pvz_service/internal/label"
 	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/internal/pagination"
 )
 
 func ValidateAuth(request *dto.AuthRequest) error {
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	if !emailRegex.MatchString(request.Email) {
-		return ErrInvalidEmail
+		return invalidField("email", ErrInvalidEmail)
 	}
 
 	if len(request.Password) < 8 {
-		return ErrShortPassword
+		return invalidField("password", ErrShortPassword)
 	}
 
 	return nil
@@ -24,7 +31,7 @@ func ValidateAuth(request *dto.AuthRequest) error {
 
 func ValidateCloseLastReceptionRequest(request *dto.CloseLastReceptionRequest) error {
 	if request.PvzId == uuid.Nil {
-		return ErrInvalidUUID
+		return invalidField("pvzId", ErrInvalidUUID)
 	}
 	return nil
 }
@@ -41,6 +48,8 @@ func ValidateCloseLastReceptionResponse(response *dto.CloseLastReceptionResponse
 	return nil
 }
 
+const maxExternalCode = 64
+
 func ValidatePvzCreateRequest(request *dto.PvzCreateRequest) error {
 	validCities := map[string]bool{
 		"Москва":          true,
@@ -48,11 +57,15 @@ func ValidatePvzCreateRequest(request *dto.PvzCreateRequest) error {
 		"Казань":          true,
 	}
 	if !validCities[request.City] {
-		return ErrInvalidCity
+		return invalidField("city", ErrInvalidCity)
 	}
 
 	if request.RegistrationDate.After(time.Now()) {
-		return ErrFutureDate
+		return invalidField("registrationDate", ErrFutureDate)
+	}
+
+	if len(request.ExternalCode) > maxExternalCode {
+		return invalidField("externalCode", ErrLongExternalCode)
 	}
 
 	return nil
@@ -65,11 +78,11 @@ func ValidateAddProductRequest(request *dto.AddProductRequest) error {
 		"обувь":       true,
 	}
 	if !validTypes[request.Type] {
-		return ErrInvalidProductType
+		return invalidField("type", ErrInvalidProductType)
 	}
 
 	if request.PvzId == uuid.Nil {
-		return ErrInvalidUUID
+		return invalidField("pvzId", ErrInvalidUUID)
 	}
 
 	return nil
@@ -77,6 +90,34 @@ func ValidateAddProductRequest(request *dto.AddProductRequest) error {
 
 func ValidateGetPvzRequest(request *dto.GetPvzRequest) error {
 
+	switch request.Sort {
+	case "":
+		request.Sort = dto.SortRegistrationDate
+	case dto.SortRegistrationDate, dto.SortCity, dto.SortLastReception, dto.SortProductCount:
+	default:
+		return invalidField("sort", ErrInvalidSort)
+	}
+	switch request.Order {
+	case "":
+		request.Order = dto.OrderAsc
+	case dto.OrderAsc, dto.OrderDesc:
+	default:
+		return invalidField("order", ErrInvalidOrder)
+	}
+
+	if request.Cursor != "" {
+		if request.Page > 1 {
+			return invalidField("cursor", ErrCursorWithPage)
+		}
+		cursor, err := pagination.DecodeCursor(request.Cursor)
+		if err != nil {
+			return invalidField("cursor", err)
+		}
+		if !cursor.Matches(request.Sort, request.Order == dto.OrderDesc) {
+			return invalidField("cursor", ErrCursorSortMismatch)
+		}
+	}
+
 	if request.Page == 0 {
 		request.Page = 1
 	}
@@ -85,20 +126,51 @@ func ValidateGetPvzRequest(request *dto.GetPvzRequest) error {
 	}
 
 	if request.Page < 1 {
-		return ErrInvalidPage
+		return invalidField("page", ErrInvalidPage)
 	}
 
 	if request.Limit < 1 || request.Limit > 30 {
-		return ErrInvalidLimit
+		return invalidField("limit", ErrInvalidLimit)
 	}
 
 	if !request.StartDate.IsZero() && !request.EndDate.IsZero() && request.EndDate.Before(request.StartDate) {
-		return ErrInvalidDateRange
+		return invalidField("endDate", ErrInvalidDateRange)
 	}
 
 	currentTime := time.Now().UTC()
-	if request.StartDate.After(currentTime) || request.EndDate.After(currentTime) {
-		return ErrFutureDate
+	if request.StartDate.After(currentTime) {
+		return invalidField("startDate", ErrFutureDate)
+	}
+	if request.EndDate.After(currentTime) {
+		return invalidField("endDate", ErrFutureDate)
+	}
+
+	if !request.RegisteredFrom.IsZero() && !request.RegisteredTo.IsZero() && request.RegisteredTo.Before(request.RegisteredFrom) {
+		return invalidField("registeredTo", ErrInvalidDateRange)
+	}
+
+	validCities := map[string]bool{"Москва": true, "Санкт-Петербург": true, "Казань": true}
+	for _, city := range request.Cities {
+		if !validCities[city] {
+			return invalidField("city", ErrInvalidCity)
+		}
+	}
+
+	if request.ReceptionStatus != "" && request.ReceptionStatus != "in_progress" && request.ReceptionStatus != "close" {
+		return invalidField("receptionStatus", ErrInvalidStatus)
+	}
+
+	validTypes := map[string]bool{"электроника": true, "одежда": true, "обувь": true}
+	if request.ProductType != "" && !validTypes[request.ProductType] {
+		return invalidField("productType", ErrInvalidProductType)
+	}
+
+	switch request.Include {
+	case "":
+		request.Include = dto.IncludeProducts
+	case dto.IncludeNone, dto.IncludeReceptions, dto.IncludeProducts:
+	default:
+		return invalidField("include", ErrInvalidInclude)
 	}
 
 	return nil
@@ -106,15 +178,15 @@ func ValidateGetPvzRequest(request *dto.GetPvzRequest) error {
 
 func ValidateRegisterRequest(request *dto.RegisterRequest) error {
 	if !regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`).MatchString(request.Email) {
-		return ErrInvalidEmail
+		return invalidField("email", ErrInvalidEmail)
 	}
 
 	if len(request.Password) < 8 {
-		return ErrWeakPassword
+		return invalidField("password", ErrWeakPassword)
 	}
 
 	if request.Role != "moderator" && request.Role != "employee" {
-		return ErrInvalidRole
+		return invalidField("role", ErrInvalidRole)
 	}
 
 	return nil
@@ -122,14 +194,14 @@ func ValidateRegisterRequest(request *dto.RegisterRequest) error {
 
 func ValidateDummyLogin(role string) error {
 	if role != string(models.RoleModerator) && role != string(models.RoleEmployee) {
-		return ErrInvalidRole
+		return invalidField("role", ErrInvalidRole)
 	}
 	return nil
 }
 
 func ValidateDeleteProductRequest(request *dto.DeleteProductRequest) error {
 	if request.PvzId == uuid.Nil || request.PvzId.Version() != 4 {
-		return ErrInvalidUUID
+		return invalidField("pvzId", ErrInvalidUUID)
 	}
 	return nil
 }
@@ -156,3 +228,177 @@ func ValidatePVZ(pvz *dto.PVZResponse) error {
 	}
 	return nil
 }
+
+func ValidateGetProductRequest(request *dto.GetProductRequest) (uuid.UUID, error) {
+	productId, err := label.ParseBarcode(request.Barcode)
+	if err != nil {
+		return uuid.Nil, invalidField("barcode", ErrInvalidBarcode)
+	}
+	return productId, nil
+}
+
+// ValidateEventStreamRequest builds the subscription filter and the id of
+// the last event the client has seen, if any.
+func ValidateEventStreamRequest(request *dto.EventStreamRequest) (events.Filter, *uint64, error) {
+	var filter events.Filter
+	for _, id := range request.PvzIds {
+		pvzId, err := uuid.Parse(id)
+		if err != nil {
+			return filter, nil, invalidField("pvzId", ErrInvalidUUID)
+		}
+		filter.PvzIds = append(filter.PvzIds, pvzId)
+	}
+
+	validCities := map[string]bool{"Москва": true, "Санкт-Петербург": true, "Казань": true}
+	for _, city := range request.Cities {
+		if !validCities[city] {
+			return filter, nil, invalidField("city", ErrInvalidCity)
+		}
+	}
+	filter.Cities = request.Cities
+
+	for _, typ := range request.Types {
+		if !events.Type(typ).Valid() {
+			return filter, nil, invalidField("type", ErrInvalidEventType)
+		}
+		filter.Types = append(filter.Types, events.Type(typ))
+	}
+
+	if request.LastEventId == "" {
+		return filter, nil, nil
+	}
+	after, err := strconv.ParseUint(request.LastEventId, 10, 64)
+	if err != nil {
+		return filter, nil, invalidField("lastEventId", ErrInvalidEventId)
+	}
+	return filter, &after, nil
+}
+
+const minWebhookSecret = 16
+
+func ValidateWebhookRequest(request *dto.WebhookRequest) error {
+	target, err := url.Parse(request.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return invalidField("url", ErrInvalidWebhookURL)
+	}
+
+	if len(request.EventTypes) == 0 {
+		return invalidField("eventTypes", ErrEmptyEventTypes)
+	}
+	for _, typ := range request.EventTypes {
+		if !events.Type(typ).Valid() {
+			return invalidField("eventTypes", ErrInvalidEventType)
+		}
+	}
+
+	if len(request.Secret) < minWebhookSecret {
+		return invalidField("secret", ErrShortWebhookSecret)
+	}
+	return nil
+}
+
+func ValidateReceptionReportRequest(request *dto.ReceptionReportRequest) (dto.ReceptionReportFilter, error) {
+	filter := dto.ReceptionReportFilter{
+		StartDate: request.StartDate,
+		EndDate:   request.EndDate,
+		Cities:    request.Cities,
+	}
+	if !request.StartDate.IsZero() && !request.EndDate.IsZero() && request.EndDate.Before(request.StartDate) {
+		return filter, invalidField("endDate", ErrInvalidDateRange)
+	}
+
+	validCities := map[string]bool{"Москва": true, "Санкт-Петербург": true, "Казань": true}
+	for _, city := range request.Cities {
+		if !validCities[city] {
+			return filter, invalidField("city", ErrInvalidCity)
+		}
+	}
+
+	for _, id := range request.PvzIds {
+		pvzId, err := uuid.Parse(id)
+		if err != nil {
+			return filter, invalidField("pvzId", ErrInvalidUUID)
+		}
+		filter.PvzIds = append(filter.PvzIds, pvzId)
+	}
+	return filter, nil
+}
+
+// ValidateSyncRequest checks every operation up front, so that a batch that
+// can't be replayed is refused before anything runs.
+func ValidateSyncRequest(request *dto.SyncRequest, now time.Time) error {
+	if len(request.Operations) == 0 {
+		return invalidField("operations", ErrEmptySyncOperations)
+	}
+	if len(request.Operations) > maxSyncOperations {
+		return invalidField("operations", ErrTooManySyncOperations)
+	}
+
+	clientIds := make(map[uuid.UUID]bool, len(request.Operations))
+	for i, op := range request.Operations {
+		field := fmt.Sprintf("operations[%d].", i)
+		if op.ClientId == uuid.Nil {
+			return invalidField(field+"clientId", ErrInvalidUUID)
+		}
+		if clientIds[op.ClientId] {
+			return invalidField(field+"clientId", ErrDuplicateClientId)
+		}
+		clientIds[op.ClientId] = true
+
+		if op.ClientTime.IsZero() {
+			return invalidField(field+"clientTime", ErrInvalidDate)
+		}
+		if op.ClientTime.After(now.Add(maxSyncClockSkew)) {
+			return invalidField(field+"clientTime", ErrFutureDate)
+		}
+		if !models.SyncOperationType(op.Type).Valid() {
+			return invalidField(field+"type", ErrInvalidSyncOperation)
+		}
+		if op.PvzId == uuid.Nil {
+			return invalidField(field+"pvzId", ErrInvalidUUID)
+		}
+		if models.SyncOperationType(op.Type) == models.SyncAddProduct {
+			if err := ValidateAddProductRequest(&dto.AddProductRequest{Type: op.ProductType, PvzId: op.PvzId}); err != nil {
+				return invalidField(field+"productType", ErrInvalidProductType)
+			}
+		}
+	}
+	return nil
+}
+
+func ValidateIntakeReportRequest(request *dto.IntakeReportRequest) (dto.IntakeReportFilter, error) {
+	filter := dto.IntakeReportFilter{
+		StartDate: request.StartDate,
+		EndDate:   request.EndDate,
+		Cities:    request.Cities,
+	}
+	if !request.StartDate.IsZero() && !request.EndDate.IsZero() && request.EndDate.Before(request.StartDate) {
+		return filter, invalidField("endDate", ErrInvalidDateRange)
+	}
+
+	for _, city := range request.Cities {
+		if _, err := models.City("").Parse(city); err != nil {
+			return filter, invalidField("city", ErrInvalidCity)
+		}
+	}
+
+	seen := make(map[models.IntakeDimension]bool)
+	period := false
+	for _, value := range request.GroupBy {
+		for _, name := range strings.Split(value, ",") {
+			dimension := models.IntakeDimension(strings.TrimSpace(name))
+			if !dimension.Valid() || seen[dimension] {
+				return filter, invalidField("groupBy", ErrInvalidGroupBy)
+			}
+			if dimension.Period() {
+				if period {
+					return filter, invalidField("groupBy", ErrManyIntakePeriods)
+				}
+				period = true
+			}
+			seen[dimension] = true
+			filter.GroupBy = append(filter.GroupBy, dimension)
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/internal/repository"
+	"github.com/senorUVE/pvz_service/internal/webhook"
+	"github.com/sirupsen/logrus"
+)
+
+func (p *PvzService) CreateWebhook(ctx context.Context, request *dto.WebhookRequest, userId uuid.UUID) (*dto.WebhookResponse, error) {
+	if err := ValidateWebhookRequest(request); err != nil {
+		return nil, err
+	}
+	return p.repo.CreateWebhook(ctx, *request, userId)
+}
+
+func (p *PvzService) ListWebhooks(ctx context.Context) ([]dto.WebhookResponse, error) {
+	return p.repo.ListWebhooks(ctx)
+}
+
+func (p *PvzService) DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error {
+	return p.repo.DeleteWebhook(ctx, webhookId)
+}
+
+func (p *PvzService) GetWebhookDeliveries(ctx context.Context, webhookId uuid.UUID, request *dto.GetWebhookDeliveriesRequest) ([]dto.WebhookDeliveryResponse, error) {
+	if request.Status != "" && !models.WebhookDeliveryStatus(request.Status).Valid() {
+		return nil, invalidField("status", ErrInvalidDeliveryStatus)
+	}
+	return p.repo.ListWebhookDeliveries(ctx, webhookId, request.Status)
+}
+
+func (p *PvzService) RedeliverWebhook(ctx context.Context, deliveryId uuid.UUID) (*dto.WebhookDeliveryResponse, error) {
+	return p.repo.RedeliverWebhook(ctx, deliveryId)
+}
+
+// enqueueWebhooks queues event for its subscribers.
+func (p *PvzService) enqueueWebhooks(ctx context.Context, event events.Event) error {
+	payload, err := json.Marshal(dto.WebhookPayload{
+		Type:        string(event.Type),
+		Time:        event.Time,
+		PvzId:       event.PvzId,
+		City:        event.City,
+		ReceptionId: event.ReceptionId,
+		ProductId:   event.ProductId,
+		ProductType: event.ProductType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	_, err = p.repo.EnqueueWebhookDeliveries(ctx, string(event.Type), payload)
+	return err
+}
+
+// DeliverWebhooks sends one batch of due deliveries and records the outcome
+// of each: delivered, retried after a backoff, or dead once the attempts are
+// used up. It returns how many deliveries were attempted.
+func (p *PvzService) DeliverWebhooks(ctx context.Context) (int, error) {
+	cfg := p.cfg.Webhooks.WithDefaults()
+	// The lease outlives the send timeout so a claim is not handed out twice.
+	messages, err := p.repo.ClaimWebhookDeliveries(ctx, cfg.BatchSize, 2*cfg.Timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, message := range messages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.deliverWebhook(ctx, cfg, message)
+		}()
+	}
+	wg.Wait()
+	return len(messages), nil
+}
+
+func (p *PvzService) deliverWebhook(ctx context.Context, cfg webhook.Config, message dto.WebhookMessage) {
+	const op = "internal.controller.deliverWebhook"
+	statusCode, err := p.webhooks.Send(ctx, webhook.Message{
+		DeliveryId: message.Id,
+		EventType:  message.EventType,
+		URL:        message.URL,
+		Secret:     message.Secret,
+		Payload:    message.Payload,
+	})
+
+	now := time.Now().UTC()
+	attempt := dto.WebhookAttempt{
+		DeliveryId:    message.Id,
+		Attempts:      message.Attempts + 1,
+		NextAttemptAt: now,
+		StatusCode:    statusCode,
+	}
+	switch {
+	case err == nil:
+		attempt.Status = string(models.WebhookDeliveryDelivered)
+		attempt.DeliveredAt = &now
+	case attempt.Attempts >= cfg.MaxAttempts:
+		attempt.Status = string(models.WebhookDeliveryDead)
+		attempt.Error = err.Error()
+	default:
+		attempt.Status = string(models.WebhookDeliveryPending)
+		attempt.NextAttemptAt = now.Add(cfg.Backoff(attempt.Attempts))
+		attempt.Error = err.Error()
+	}
+
+	err = p.repo.SaveWebhookAttempt(context.WithoutCancel(ctx), attempt)
+	switch {
+	case errors.Is(err, repository.ErrWebhookAttemptStale):
+		// The lease ran out during the send and another worker took over.
+		logrus.WithFields(logrus.Fields{"event": op, "deliveryId": message.Id}).Warn(err)
+	case err != nil:
+		logrus.WithFields(logrus.Fields{"event": op, "deliveryId": message.Id}).Error(err)
+	}
+}
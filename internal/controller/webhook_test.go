@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/webhook"
+	"github.com/senorUVE/pvz_service/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPvzService_DeliverWebhooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var failing atomic.Bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if err := webhook.Verify("0123456789abcdef", r.Header.Get(webhook.SignatureHeader), body, time.Minute, time.Now()); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	cfg := webhook.Config{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour, BatchSize: 10, Timeout: time.Second, AllowPrivateAddresses: true}
+	service := NewPvzService(mockRepo, nil, ServiceConfig{Webhooks: cfg})
+	ctx := context.Background()
+
+	message := dto.WebhookMessage{
+		Id:        uuid.New(),
+		EventType: "product.added",
+		Payload:   []byte(`{"type":"product.added"}`),
+		URL:       receiver.URL,
+		Secret:    "0123456789abcdef",
+	}
+
+	deliver := func(message dto.WebhookMessage) dto.WebhookAttempt {
+		var saved dto.WebhookAttempt
+		mockRepo.EXPECT().ClaimWebhookDeliveries(ctx, 10, 2*time.Second).Return([]dto.WebhookMessage{message}, nil)
+		mockRepo.EXPECT().SaveWebhookAttempt(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, attempt dto.WebhookAttempt) error {
+				saved = attempt
+				return nil
+			})
+		sent, err := service.DeliverWebhooks(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		return saved
+	}
+
+	t.Run("delivered", func(t *testing.T) {
+		attempt := deliver(message)
+		assert.Equal(t, "delivered", attempt.Status)
+		assert.Equal(t, 1, attempt.Attempts)
+		assert.Equal(t, http.StatusOK, attempt.StatusCode)
+		assert.NotNil(t, attempt.DeliveredAt)
+	})
+
+	t.Run("retried with backoff", func(t *testing.T) {
+		failing.Store(true)
+		retry := message
+		retry.Attempts = 1
+		attempt := deliver(retry)
+		assert.Equal(t, "pending", attempt.Status)
+		assert.Equal(t, 2, attempt.Attempts)
+		assert.Equal(t, http.StatusInternalServerError, attempt.StatusCode)
+		assert.NotEmpty(t, attempt.Error)
+		assert.WithinDuration(t, time.Now().Add(2*time.Minute), attempt.NextAttemptAt, 5*time.Second)
+	})
+
+	t.Run("dead after the last attempt", func(t *testing.T) {
+		failing.Store(true)
+		last := message
+		last.Attempts = 2
+		attempt := deliver(last)
+		assert.Equal(t, "dead", attempt.Status)
+		assert.Equal(t, 3, attempt.Attempts)
+		assert.Nil(t, attempt.DeliveredAt)
+	})
+
+	t.Run("wrong secret is rejected", func(t *testing.T) {
+		failing.Store(false)
+		forged := message
+		forged.Secret = "fedcba9876543210"
+		attempt := deliver(forged)
+		assert.Equal(t, "pending", attempt.Status)
+		assert.Equal(t, http.StatusUnauthorized, attempt.StatusCode)
+	})
+}
+
+func TestValidateWebhookRequest(t *testing.T) {
+	valid := dto.WebhookRequest{URL: "https://example.com/hook", EventTypes: []string{"product.added"}, Secret: "0123456789abcdef"}
+	assert.NoError(t, ValidateWebhookRequest(&valid))
+
+	tests := []struct {
+		name   string
+		modify func(*dto.WebhookRequest)
+		err    error
+	}{
+		{"relative url", func(r *dto.WebhookRequest) { r.URL = "/hook" }, ErrInvalidWebhookURL},
+		{"ftp url", func(r *dto.WebhookRequest) { r.URL = "ftp://example.com" }, ErrInvalidWebhookURL},
+		{"no event types", func(r *dto.WebhookRequest) { r.EventTypes = nil }, ErrEmptyEventTypes},
+		{"unknown event type", func(r *dto.WebhookRequest) { r.EventTypes = []string{"stream.reset"} }, ErrInvalidEventType},
+		{"short secret", func(r *dto.WebhookRequest) { r.Secret = "short" }, ErrShortWebhookSecret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid
+			tt.modify(&request)
+			assert.ErrorIs(t, ValidateWebhookRequest(&request), tt.err)
+		})
+	}
+}
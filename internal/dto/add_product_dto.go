@@ -8,7 +8,7 @@ import (
 
 type AddProductRequest struct {
 	Type  string    `json:"type" db:"type"`
-	PvzId uuid.UUID `query:"pvzId" db:"pvz_id"`
+	PvzId uuid.UUID `json:"pvzId" db:"pvz_id"`
 }
 
 type AddProductResponse struct {
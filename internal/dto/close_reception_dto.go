@@ -15,4 +15,7 @@ type CloseLastReceptionResponse struct {
 	DateTime time.Time `json:"dateTime" db:"date_time"`
 	PvzId    uuid.UUID `json:"pvzId" db:"pvz_id"`
 	Status   string    `json:"status" db:"status"`
+	// Closed is false when the reception was already closed, as it is for a
+	// retried request.
+	Closed bool `json:"-" db:"closed"`
 }
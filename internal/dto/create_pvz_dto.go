@@ -10,10 +10,33 @@ type PvzCreateRequest struct {
 	Id               uuid.UUID `json:"id" db:"id"`
 	RegistrationDate time.Time `json:"registrationDate" db:"registration_date"`
 	City             string    `json:"city" db:"city"`
+	ExternalCode     string    `json:"externalCode,omitempty" db:"external_code"`
 }
 
 type PvzCreateResponse struct {
 	Id               uuid.UUID `json:"id" db:"id"`
 	RegistrationDate time.Time `json:"registrationDate" db:"registration_date"`
 	City             string    `json:"city" db:"city"`
+	ExternalCode     string    `json:"externalCode,omitempty" db:"external_code"`
+}
+
+type PvzImportRequest struct {
+	DryRun bool `query:"dryRun"`
+}
+
+type PvzImportResponse struct {
+	DryRun  bool           `json:"dryRun"`
+	Created int            `json:"created"`
+	Skipped int            `json:"skipped"`
+	Rows    []PvzImportRow `json:"rows"`
+}
+
+// PvzImportRow reports what happened to a CSV row. Line is the line number
+// in the file, counting the header as line 1.
+type PvzImportRow struct {
+	Line         int        `json:"line"`
+	Status       string     `json:"status"`
+	Id           *uuid.UUID `json:"id,omitempty"`
+	City         string     `json:"city"`
+	ExternalCode string     `json:"externalCode,omitempty"`
 }
@@ -0,0 +1,8 @@
+package dto
+
+type EventStreamRequest struct {
+	PvzIds      []string `query:"pvzId"`
+	Cities      []string `query:"city"`
+	Types       []string `query:"type"`
+	LastEventId string   `query:"lastEventId"`
+}
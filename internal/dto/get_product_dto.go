@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GetProductRequest struct {
+	Barcode string `query:"barcode"`
+}
+
+type ProductEventResponse struct {
+	Type        string     `json:"type" db:"type"`
+	DateTime    time.Time  `json:"dateTime" db:"date_time"`
+	ReceptionId uuid.UUID  `json:"receptionId" db:"reception_id"`
+	UserId      *uuid.UUID `json:"userId,omitempty" db:"user_id"`
+	// PvzId is where a moved product was sent.
+	PvzId *uuid.UUID `json:"pvzId,omitempty" db:"pvz_id"`
+}
+
+type MoveProductRequest struct {
+	PvzId uuid.UUID `json:"pvzId"`
+}
+
+type ProductDetailsResponse struct {
+	Product   ProductResponse        `json:"product"`
+	Reception ReceptionResponse      `json:"reception"`
+	PVZ       PVZResponse            `json:"pvz"`
+	Status    string                 `json:"status"`
+	Timeline  []ProductEventResponse `json:"timeline"`
+}
@@ -6,11 +6,40 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	IncludeNone       = "none"
+	IncludeReceptions = "receptions"
+	IncludeProducts   = "receptions.products"
+)
+
+const (
+	SortRegistrationDate = "registrationDate"
+	SortCity             = "city"
+	SortLastReception    = "lastReceptionAt"
+	SortProductCount     = "productCount"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
 type GetPvzRequest struct {
 	StartDate time.Time `query:"startDate"`
 	EndDate   time.Time `query:"endDate"`
 	Page      int       `query:"page"`
 	Limit     int       `query:"limit"`
+	Cursor    string    `query:"cursor"`
+	Include   string    `query:"include"`
+	Sort      string    `query:"sort"`
+	Order     string    `query:"order"`
+
+	Cities           []string  `query:"city"`
+	ReceptionStatus  string    `query:"receptionStatus"`
+	ProductType      string    `query:"productType"`
+	HasOpenReception *bool     `query:"hasOpenReception"`
+	RegisteredFrom   time.Time `query:"registeredFrom"`
+	RegisteredTo     time.Time `query:"registeredTo"`
+
+	IncludeDeleted bool `query:"includeDeleted"`
 }
 
 type ReceptionResponse struct {
@@ -21,15 +50,17 @@ type ReceptionResponse struct {
 }
 
 type ProductResponse struct {
-	Id          uuid.UUID `json:"id" db:"id"`
-	DateTime    time.Time `json:"dateTime" db:"date_time"`
-	Type        string    `json:"type" db:"type"`
-	ReceptionId uuid.UUID `json:"receptionId" db:"reception_Id"`
+	Id          uuid.UUID  `json:"id" db:"id"`
+	DateTime    time.Time  `json:"dateTime" db:"date_time"`
+	Type        string     `json:"type" db:"type"`
+	ReceptionId uuid.UUID  `json:"receptionId" db:"reception_Id"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty" db:"deleted_at"`
+	DeletedBy   *uuid.UUID `json:"deletedBy,omitempty" db:"deleted_by"`
 }
 
 type ReceptionWithProducts struct {
 	Reception ReceptionResponse `json:"reception"`
-	Products  []ProductResponse `json:"products"`
+	Products  []ProductResponse `json:"products,omitempty"`
 }
 
 type PVZResponse struct {
@@ -38,7 +69,24 @@ type PVZResponse struct {
 	City             string    `json:"city" db:"city"`
 }
 
+type PVZSummary struct {
+	Receptions     int `json:"receptions"`
+	OpenReceptions int `json:"openReceptions"`
+	Products       int `json:"products"`
+}
+
 type PVZWithReceptions struct {
 	PVZ        PVZResponse             `json:"pvz"`
-	Receptions []ReceptionWithProducts `json:"receptions"`
+	Summary    PVZSummary              `json:"summary"`
+	Receptions []ReceptionWithProducts `json:"receptions,omitempty"`
+}
+
+type GetPvzResponse struct {
+	Items      []*PVZWithReceptions `json:"items"`
+	Total      int                  `json:"total"`
+	Page       int                  `json:"page,omitempty"`
+	Limit      int                  `json:"limit"`
+	Cursor     string               `json:"cursor,omitempty"`
+	NextCursor string               `json:"nextCursor,omitempty"`
+	PrevCursor string               `json:"prevCursor,omitempty"`
 }
@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/google/uuid"
+
+// IdempotencyRecord is the stored outcome of a request sent with an
+// Idempotency-Key header. StatusCode is zero while the first request is still
+// being processed.
+type IdempotencyRecord struct {
+	UserId      uuid.UUID
+	Key         string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
@@ -0,0 +1,13 @@
+package dto
+
+type ProductLabel struct {
+	Product   ProductResponse
+	Reception ReceptionResponse
+	PVZ       PVZResponse
+}
+
+type LabelResponse struct {
+	ContentType string
+	FileName    string
+	Data        []byte
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/models"
+)
+
+type ReceptionReportRequest struct {
+	StartDate time.Time `query:"startDate"`
+	EndDate   time.Time `query:"endDate"`
+	Cities    []string  `query:"city"`
+	PvzIds    []string  `query:"pvzId"`
+	// BOM prepends a UTF-8 byte order mark so that Excel detects the
+	// encoding of CSV files.
+	BOM bool `query:"bom"`
+}
+
+type ReceptionReportFilter struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Cities    []string
+	PvzIds    []uuid.UUID
+}
+
+// ReceptionReportRow is a product of a reception, or the reception alone
+// when it has no products.
+type ReceptionReportRow struct {
+	PvzId             uuid.UUID
+	City              string
+	ReceptionId       uuid.UUID
+	ReceptionDateTime time.Time
+	ReceptionStatus   string
+	ProductId         *uuid.UUID
+	ProductDateTime   *time.Time
+	ProductType       *string
+}
+
+type IntakeReportRequest struct {
+	StartDate time.Time `query:"startDate"`
+	EndDate   time.Time `query:"endDate"`
+	// GroupBy may be repeated or comma separated.
+	GroupBy []string `query:"groupBy"`
+	Cities  []string `query:"city"`
+}
+
+type IntakeReportFilter struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Cities    []string
+	GroupBy   []models.IntakeDimension
+}
+
+// IntakeReportRow counts products received in one group. Only the fields of
+// the requested dimensions are set; Period is the local date the day, week
+// or month starts on in the PVZ's city.
+type IntakeReportRow struct {
+	City        *string    `json:"city,omitempty"`
+	PvzId       *uuid.UUID `json:"pvzId,omitempty"`
+	ProductType *string    `json:"type,omitempty"`
+	Period      *string    `json:"period,omitempty"`
+	Products    int64      `json:"products"`
+	Receptions  int64      `json:"receptions"`
+}
+
+type IntakeReportResponse struct {
+	GroupBy []string          `json:"groupBy"`
+	Rows    []IntakeReportRow `json:"rows"`
+}
@@ -1,25 +1,19 @@
 package dto
 
-type BadRequestResponse struct {
-	Errors string `json:"errors"`
+// ProblemResponse is an RFC 7807 problem details body. Code is stable and
+// meant for clients to switch on; Detail is human readable and may change.
+type ProblemResponse struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Code          string         `json:"code"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalidParams,omitempty"`
 }
 
-type WrongDataResponse struct {
-	Errors string `json:"errors"`
-}
-
-type AccessDeniedResponse struct {
-	Errors string `json:"errors"`
-}
-
-type UnauthorizedResponse struct {
-	Errors string `json:"errors"`
-}
-
-type InternalServerErrorResponse struct {
-	Errors string `json:"errors"`
-}
-
-type ErrorResponse struct {
-	Errors string `json:"errors"`
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
 }
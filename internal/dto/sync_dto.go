@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SyncOperation struct {
+	ClientId    uuid.UUID `json:"clientId"`
+	ClientTime  time.Time `json:"clientTime"`
+	Type        string    `json:"type"`
+	PvzId       uuid.UUID `json:"pvzId"`
+	ProductType string    `json:"productType,omitempty"`
+}
+
+type SyncRequest struct {
+	Operations []SyncOperation `json:"operations"`
+}
+
+type SyncResult struct {
+	ClientId    uuid.UUID  `json:"clientId"`
+	Status      string     `json:"status"`
+	Reason      string     `json:"reason,omitempty"`
+	ReceptionId *uuid.UUID `json:"receptionId,omitempty"`
+	ProductId   *uuid.UUID `json:"productId,omitempty"`
+}
+
+type SyncResponse struct {
+	Results []SyncResult `json:"results"`
+}
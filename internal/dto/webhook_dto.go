@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	Secret     string   `json:"secret"`
+}
+
+// WebhookResponse never includes the secret.
+type WebhookResponse struct {
+	Id         uuid.UUID  `json:"id"`
+	URL        string     `json:"url"`
+	EventTypes []string   `json:"eventTypes"`
+	CreatedBy  *uuid.UUID `json:"createdBy,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type GetWebhookDeliveriesRequest struct {
+	Status string `query:"status"`
+}
+
+type WebhookDeliveryResponse struct {
+	Id             uuid.UUID  `json:"id" db:"id"`
+	SubscriptionId uuid.UUID  `json:"subscriptionId" db:"subscription_id"`
+	EventType      string     `json:"eventType" db:"event_type"`
+	Status         string     `json:"status" db:"status"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt" db:"next_attempt_at"`
+	LastStatusCode *int       `json:"lastStatusCode,omitempty" db:"last_status_code"`
+	LastError      *string    `json:"lastError,omitempty" db:"last_error"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty" db:"delivered_at"`
+}
+
+// WebhookPayload is the body posted to subscribers. Its id is the delivery
+// id, added when the delivery is queued, so that it survives restarts and
+// receivers can drop duplicates by it. Who made a change is left out: the
+// receivers are outside the service.
+type WebhookPayload struct {
+	Type        string     `json:"type"`
+	Time        time.Time  `json:"time"`
+	PvzId       uuid.UUID  `json:"pvzId"`
+	City        string     `json:"city,omitempty"`
+	ReceptionId uuid.UUID  `json:"receptionId"`
+	ProductId   *uuid.UUID `json:"productId,omitempty"`
+	ProductType string     `json:"productType,omitempty"`
+}
+
+// WebhookMessage is a claimed delivery together with its subscription.
+type WebhookMessage struct {
+	Id        uuid.UUID
+	EventType string
+	Payload   []byte
+	Attempts  int
+	URL       string
+	Secret    string
+}
+
+// WebhookAttempt is the outcome of sending a WebhookMessage.
+type WebhookAttempt struct {
+	DeliveryId    uuid.UUID
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	StatusCode    int
+	Error         string
+	DeliveredAt   *time.Time
+}
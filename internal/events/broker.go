@@ -0,0 +1,139 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Broker fans events out to in-process subscribers and keeps the most recent
+// ones in a ring buffer so that reconnecting clients can resume.
+type Broker struct {
+	mu          sync.Mutex
+	lastId      uint64
+	buffer      []Event
+	next        int
+	full        bool
+	subscribers map[*Subscription]struct{}
+	subBuffer   int
+}
+
+func NewBroker(cfg Config) *Broker {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.SubscriberBuffer <= 0 {
+		cfg.SubscriberBuffer = defaultSubscriberBuffer
+	}
+	return &Broker{
+		buffer:      make([]Event, cfg.BufferSize),
+		subscribers: make(map[*Subscription]struct{}),
+		subBuffer:   cfg.SubscriberBuffer,
+	}
+}
+
+// Publish assigns the next id to event and delivers it. Subscribers that
+// cannot keep up are closed instead of blocking the publisher.
+func (b *Broker) Publish(event Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastId++
+	event.Id = b.lastId
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	b.buffer[b.next] = event
+	b.next = (b.next + 1) % len(b.buffer)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			b.remove(sub)
+		}
+	}
+	return event
+}
+
+// Subscribe starts delivering events that match filter. When after is set,
+// buffered events newer than it are returned in Replay; Reset is reported if
+// some of them were already evicted or after comes from a previous process.
+func (b *Broker) Subscribe(filter Filter, after *uint64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &Subscription{
+		broker: b,
+		filter: filter,
+		events: make(chan Event, b.subBuffer),
+		LastId: b.lastId,
+	}
+	if after != nil {
+		sub.Replay, sub.Reset = b.since(*after, filter)
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (b *Broker) since(after uint64, filter Filter) ([]Event, bool) {
+	if after > b.lastId {
+		return nil, true
+	}
+
+	buffered := b.ordered()
+	reset := len(buffered) > 0 && after+1 < buffered[0].Id
+	var replay []Event
+	for _, event := range buffered {
+		if event.Id > after && filter.Match(event) {
+			replay = append(replay, event)
+		}
+	}
+	return replay, reset
+}
+
+func (b *Broker) ordered() []Event {
+	if !b.full {
+		return b.buffer[:b.next]
+	}
+	ordered := make([]Event, 0, len(b.buffer))
+	ordered = append(ordered, b.buffer[b.next:]...)
+	return append(ordered, b.buffer[:b.next]...)
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
+
+type Subscription struct {
+	// Replay holds buffered events the subscriber missed, oldest first.
+	Replay []Event
+	Reset  bool
+	// LastId is the id of the last event published before subscribing.
+	LastId uint64
+
+	broker *Broker
+	filter Filter
+	events chan Event
+}
+
+// Events is closed when the subscription is closed or falls behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
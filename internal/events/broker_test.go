@@ -0,0 +1,79 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ids(events []Event) []uint64 {
+	result := make([]uint64, 0, len(events))
+	for _, event := range events {
+		result = append(result, event.Id)
+	}
+	return result
+}
+
+func TestBroker_PublishFilters(t *testing.T) {
+	broker := NewBroker(Config{})
+	pvzId := uuid.New()
+
+	sub := broker.Subscribe(Filter{PvzIds: []uuid.UUID{pvzId}, Types: []Type{ProductAdded}}, nil)
+	defer sub.Close()
+
+	broker.Publish(Event{Type: ProductAdded, PvzId: uuid.New()})
+	broker.Publish(Event{Type: ReceptionOpened, PvzId: pvzId})
+	published := broker.Publish(Event{Type: ProductAdded, PvzId: pvzId, City: "Казань"})
+
+	require.Len(t, sub.Events(), 1)
+	event := <-sub.Events()
+	assert.Equal(t, uint64(3), event.Id)
+	assert.Equal(t, published, event)
+	assert.False(t, event.Time.IsZero())
+}
+
+func TestBroker_Resume(t *testing.T) {
+	broker := NewBroker(Config{BufferSize: 3})
+	for range 5 {
+		broker.Publish(Event{Type: ProductAdded, City: "Москва"})
+	}
+
+	tests := []struct {
+		name   string
+		after  uint64
+		replay []uint64
+		reset  bool
+	}{
+		{name: "within buffer", after: 3, replay: []uint64{4, 5}},
+		{name: "up to date", after: 5, replay: []uint64{}},
+		{name: "oldest buffered is next", after: 2, replay: []uint64{3, 4, 5}},
+		{name: "evicted", after: 1, replay: []uint64{3, 4, 5}, reset: true},
+		{name: "from previous process", after: 42, replay: []uint64{}, reset: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := broker.Subscribe(Filter{}, &tt.after)
+			defer sub.Close()
+			assert.Equal(t, tt.replay, ids(sub.Replay))
+			assert.Equal(t, tt.reset, sub.Reset)
+		})
+	}
+}
+
+func TestBroker_SlowSubscriberIsDropped(t *testing.T) {
+	broker := NewBroker(Config{SubscriberBuffer: 1})
+	sub := broker.Subscribe(Filter{}, nil)
+
+	broker.Publish(Event{Type: ProductAdded})
+	broker.Publish(Event{Type: ProductAdded})
+
+	event, ok := <-sub.Events()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), event.Id)
+	_, ok = <-sub.Events()
+	assert.False(t, ok)
+
+	sub.Close()
+}
@@ -0,0 +1,14 @@
+package events
+
+const (
+	defaultBufferSize       = 1024
+	defaultSubscriberBuffer = 64
+)
+
+type Config struct {
+	// BufferSize is how many recent events are kept for Last-Event-ID resume.
+	BufferSize int `mapstructure:"buffer_size"`
+	// SubscriberBuffer is how far a subscriber may fall behind before it is
+	// disconnected and has to resume.
+	SubscriberBuffer int `mapstructure:"subscriber_buffer"`
+}
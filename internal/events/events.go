@@ -0,0 +1,60 @@
+package events
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Type string
+
+const (
+	ReceptionOpened Type = "reception.opened"
+	ReceptionClosed Type = "reception.closed"
+	ProductAdded    Type = "product.added"
+	ProductDeleted  Type = "product.deleted"
+	ProductRestored Type = "product.restored"
+
+	// StreamReset tells a resuming subscriber that events were lost and its
+	// state has to be reloaded.
+	StreamReset Type = "stream.reset"
+)
+
+var Types = []Type{ReceptionOpened, ReceptionClosed, ProductAdded, ProductDeleted, ProductRestored}
+
+func (t Type) Valid() bool {
+	return slices.Contains(Types, t)
+}
+
+type Event struct {
+	Id          uint64     `json:"id"`
+	Type        Type       `json:"type"`
+	Time        time.Time  `json:"time"`
+	PvzId       uuid.UUID  `json:"pvzId"`
+	City        string     `json:"city,omitempty"`
+	ReceptionId uuid.UUID  `json:"receptionId"`
+	ProductId   *uuid.UUID `json:"productId,omitempty"`
+	ProductType string     `json:"productType,omitempty"`
+	UserId      *uuid.UUID `json:"userId,omitempty"`
+}
+
+// Filter matches events by PVZ, city and type; an empty list matches all.
+type Filter struct {
+	PvzIds []uuid.UUID
+	Cities []string
+	Types  []Type
+}
+
+func (f Filter) Match(event Event) bool {
+	if len(f.PvzIds) > 0 && !slices.Contains(f.PvzIds, event.PvzId) {
+		return false
+	}
+	if len(f.Cities) > 0 && !slices.Contains(f.Cities, event.City) {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, event.Type) {
+		return false
+	}
+	return true
+}
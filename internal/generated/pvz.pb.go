@@ -68,11 +68,184 @@ func (ReceptionStatus) EnumDescriptor() ([]byte, []int) {
 	return file_proto_pvz_proto_rawDescGZIP(), []int{0}
 }
 
+type PVZInclude int32
+
+const (
+	PVZInclude_PVZ_INCLUDE_NONE       PVZInclude = 0
+	PVZInclude_PVZ_INCLUDE_RECEPTIONS PVZInclude = 1
+	PVZInclude_PVZ_INCLUDE_PRODUCTS   PVZInclude = 2
+)
+
+// Enum value maps for PVZInclude.
+var (
+	PVZInclude_name = map[int32]string{
+		0: "PVZ_INCLUDE_NONE",
+		1: "PVZ_INCLUDE_RECEPTIONS",
+		2: "PVZ_INCLUDE_PRODUCTS",
+	}
+	PVZInclude_value = map[string]int32{
+		"PVZ_INCLUDE_NONE":       0,
+		"PVZ_INCLUDE_RECEPTIONS": 1,
+		"PVZ_INCLUDE_PRODUCTS":   2,
+	}
+)
+
+func (x PVZInclude) Enum() *PVZInclude {
+	p := new(PVZInclude)
+	*p = x
+	return p
+}
+
+func (x PVZInclude) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PVZInclude) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_pvz_proto_enumTypes[1].Descriptor()
+}
+
+func (PVZInclude) Type() protoreflect.EnumType {
+	return &file_proto_pvz_proto_enumTypes[1]
+}
+
+func (x PVZInclude) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PVZInclude.Descriptor instead.
+func (PVZInclude) EnumDescriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{1}
+}
+
+type IntakeDimension int32
+
+const (
+	IntakeDimension_INTAKE_DIMENSION_UNSPECIFIED IntakeDimension = 0
+	IntakeDimension_INTAKE_DIMENSION_CITY        IntakeDimension = 1
+	IntakeDimension_INTAKE_DIMENSION_PVZ         IntakeDimension = 2
+	IntakeDimension_INTAKE_DIMENSION_TYPE        IntakeDimension = 3
+	IntakeDimension_INTAKE_DIMENSION_DAY         IntakeDimension = 4
+	IntakeDimension_INTAKE_DIMENSION_WEEK        IntakeDimension = 5
+	IntakeDimension_INTAKE_DIMENSION_MONTH       IntakeDimension = 6
+)
+
+// Enum value maps for IntakeDimension.
+var (
+	IntakeDimension_name = map[int32]string{
+		0: "INTAKE_DIMENSION_UNSPECIFIED",
+		1: "INTAKE_DIMENSION_CITY",
+		2: "INTAKE_DIMENSION_PVZ",
+		3: "INTAKE_DIMENSION_TYPE",
+		4: "INTAKE_DIMENSION_DAY",
+		5: "INTAKE_DIMENSION_WEEK",
+		6: "INTAKE_DIMENSION_MONTH",
+	}
+	IntakeDimension_value = map[string]int32{
+		"INTAKE_DIMENSION_UNSPECIFIED": 0,
+		"INTAKE_DIMENSION_CITY":        1,
+		"INTAKE_DIMENSION_PVZ":         2,
+		"INTAKE_DIMENSION_TYPE":        3,
+		"INTAKE_DIMENSION_DAY":         4,
+		"INTAKE_DIMENSION_WEEK":        5,
+		"INTAKE_DIMENSION_MONTH":       6,
+	}
+)
+
+func (x IntakeDimension) Enum() *IntakeDimension {
+	p := new(IntakeDimension)
+	*p = x
+	return p
+}
+
+func (x IntakeDimension) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IntakeDimension) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_pvz_proto_enumTypes[2].Descriptor()
+}
+
+func (IntakeDimension) Type() protoreflect.EnumType {
+	return &file_proto_pvz_proto_enumTypes[2]
+}
+
+func (x IntakeDimension) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IntakeDimension.Descriptor instead.
+func (IntakeDimension) EnumDescriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{2}
+}
+
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED      EventType = 0
+	EventType_EVENT_TYPE_RECEPTION_OPENED EventType = 1
+	EventType_EVENT_TYPE_RECEPTION_CLOSED EventType = 2
+	EventType_EVENT_TYPE_PRODUCT_ADDED    EventType = 3
+	EventType_EVENT_TYPE_PRODUCT_DELETED  EventType = 4
+	EventType_EVENT_TYPE_PRODUCT_RESTORED EventType = 5
+	// Events after resume_token were lost, the watcher has to reload its state.
+	EventType_EVENT_TYPE_STREAM_RESET EventType = 6
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_TYPE_UNSPECIFIED",
+		1: "EVENT_TYPE_RECEPTION_OPENED",
+		2: "EVENT_TYPE_RECEPTION_CLOSED",
+		3: "EVENT_TYPE_PRODUCT_ADDED",
+		4: "EVENT_TYPE_PRODUCT_DELETED",
+		5: "EVENT_TYPE_PRODUCT_RESTORED",
+		6: "EVENT_TYPE_STREAM_RESET",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED":      0,
+		"EVENT_TYPE_RECEPTION_OPENED": 1,
+		"EVENT_TYPE_RECEPTION_CLOSED": 2,
+		"EVENT_TYPE_PRODUCT_ADDED":    3,
+		"EVENT_TYPE_PRODUCT_DELETED":  4,
+		"EVENT_TYPE_PRODUCT_RESTORED": 5,
+		"EVENT_TYPE_STREAM_RESET":     6,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_pvz_proto_enumTypes[3].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_proto_pvz_proto_enumTypes[3]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{3}
+}
+
 type PVZ struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	RegistrationDate *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=registration_date,json=registrationDate,proto3" json:"registration_date,omitempty"`
 	City             string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	ExternalCode     string                 `protobuf:"bytes,4,opt,name=external_code,json=externalCode,proto3" json:"external_code,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -128,26 +301,37 @@ func (x *PVZ) GetCity() string {
 	return ""
 }
 
-type GetPVZListRequest struct {
+func (x *PVZ) GetExternalCode() string {
+	if x != nil {
+		return x.ExternalCode
+	}
+	return ""
+}
+
+type Reception struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DateTime      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`
+	PvzId         string                 `protobuf:"bytes,3,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	Status        ReceptionStatus        `protobuf:"varint,4,opt,name=status,proto3,enum=pvz.v1.ReceptionStatus" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPVZListRequest) Reset() {
-	*x = GetPVZListRequest{}
+func (x *Reception) Reset() {
+	*x = Reception{}
 	mi := &file_proto_pvz_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPVZListRequest) String() string {
+func (x *Reception) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPVZListRequest) ProtoMessage() {}
+func (*Reception) ProtoMessage() {}
 
-func (x *GetPVZListRequest) ProtoReflect() protoreflect.Message {
+func (x *Reception) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_pvz_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -159,32 +343,66 @@ func (x *GetPVZListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPVZListRequest.ProtoReflect.Descriptor instead.
-func (*GetPVZListRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Reception.ProtoReflect.Descriptor instead.
+func (*Reception) Descriptor() ([]byte, []int) {
 	return file_proto_pvz_proto_rawDescGZIP(), []int{1}
 }
 
-type GetPVZListResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Pvzs          []*PVZ                 `protobuf:"bytes,1,rep,name=pvzs,proto3" json:"pvzs,omitempty"`
+func (x *Reception) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Reception) GetDateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTime
+	}
+	return nil
+}
+
+func (x *Reception) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *Reception) GetStatus() ReceptionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS
+}
+
+type Product struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DateTime    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`
+	Type        string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	ReceptionId string                 `protobuf:"bytes,4,opt,name=reception_id,json=receptionId,proto3" json:"reception_id,omitempty"`
+	// Set only on deleted products, which are listed with include_deleted.
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	DeletedBy     *string                `protobuf:"bytes,6,opt,name=deleted_by,json=deletedBy,proto3,oneof" json:"deleted_by,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPVZListResponse) Reset() {
-	*x = GetPVZListResponse{}
+func (x *Product) Reset() {
+	*x = Product{}
 	mi := &file_proto_pvz_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPVZListResponse) String() string {
+func (x *Product) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPVZListResponse) ProtoMessage() {}
+func (*Product) ProtoMessage() {}
 
-func (x *GetPVZListResponse) ProtoReflect() protoreflect.Message {
+func (x *Product) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_pvz_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -196,83 +414,2047 @@ func (x *GetPVZListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPVZListResponse.ProtoReflect.Descriptor instead.
-func (*GetPVZListResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
 	return file_proto_pvz_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetPVZListResponse) GetPvzs() []*PVZ {
+func (x *Product) GetId() string {
 	if x != nil {
-		return x.Pvzs
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Product) GetDateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTime
 	}
 	return nil
 }
 
-var File_proto_pvz_proto protoreflect.FileDescriptor
+func (x *Product) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
 
-const file_proto_pvz_proto_rawDesc = "" +
-	"\n" +
-	"\x0fproto/pvz.proto\x12\x06pvz.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"r\n" +
-	"\x03PVZ\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12G\n" +
-	"\x11registration_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x10registrationDate\x12\x12\n" +
-	"\x04city\x18\x03 \x01(\tR\x04city\"\x13\n" +
-	"\x11GetPVZListRequest\"5\n" +
-	"\x12GetPVZListResponse\x12\x1f\n" +
-	"\x04pvzs\x18\x01 \x03(\v2\v.pvz.v1.PVZR\x04pvzs*P\n" +
-	"\x0fReceptionStatus\x12 \n" +
-	"\x1cRECEPTION_STATUS_IN_PROGRESS\x10\x00\x12\x1b\n" +
-	"\x17RECEPTION_STATUS_CLOSED\x10\x012Q\n" +
-	"\n" +
-	"PVZService\x12C\n" +
-	"\n" +
-	"GetPVZList\x12\x19.pvz.v1.GetPVZListRequest\x1a\x1a.pvz.v1.GetPVZListResponseB\x14Z\x12internal/generatedb\x06proto3"
+func (x *Product) GetReceptionId() string {
+	if x != nil {
+		return x.ReceptionId
+	}
+	return ""
+}
 
-var (
-	file_proto_pvz_proto_rawDescOnce sync.Once
-	file_proto_pvz_proto_rawDescData []byte
-)
+func (x *Product) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
 
-func file_proto_pvz_proto_rawDescGZIP() []byte {
-	file_proto_pvz_proto_rawDescOnce.Do(func() {
-		file_proto_pvz_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_pvz_proto_rawDesc), len(file_proto_pvz_proto_rawDesc)))
-	})
-	return file_proto_pvz_proto_rawDescData
+func (x *Product) GetDeletedBy() string {
+	if x != nil && x.DeletedBy != nil {
+		return *x.DeletedBy
+	}
+	return ""
 }
 
-var file_proto_pvz_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_pvz_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
-var file_proto_pvz_proto_goTypes = []any{
-	(ReceptionStatus)(0),          // 0: pvz.v1.ReceptionStatus
-	(*PVZ)(nil),                   // 1: pvz.v1.PVZ
-	(*GetPVZListRequest)(nil),     // 2: pvz.v1.GetPVZListRequest
-	(*GetPVZListResponse)(nil),    // 3: pvz.v1.GetPVZListResponse
-	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+type ProductEvent struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Type        string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	DateTime    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date_time,json=dateTime,proto3" json:"date_time,omitempty"`
+	ReceptionId string                 `protobuf:"bytes,3,opt,name=reception_id,json=receptionId,proto3" json:"reception_id,omitempty"`
+	// PVZ a moved product was sent to, empty for other events.
+	PvzId         string `protobuf:"bytes,4,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
-var file_proto_pvz_proto_depIdxs = []int32{
-	4, // 0: pvz.v1.PVZ.registration_date:type_name -> google.protobuf.Timestamp
-	1, // 1: pvz.v1.GetPVZListResponse.pvzs:type_name -> pvz.v1.PVZ
-	2, // 2: pvz.v1.PVZService.GetPVZList:input_type -> pvz.v1.GetPVZListRequest
-	3, // 3: pvz.v1.PVZService.GetPVZList:output_type -> pvz.v1.GetPVZListResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+
+func (x *ProductEvent) Reset() {
+	*x = ProductEvent{}
+	mi := &file_proto_pvz_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func init() { file_proto_pvz_proto_init() }
-func file_proto_pvz_proto_init() {
-	if File_proto_pvz_proto != nil {
-		return
+func (x *ProductEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductEvent) ProtoMessage() {}
+
+func (x *ProductEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductEvent.ProtoReflect.Descriptor instead.
+func (*ProductEvent) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProductEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ProductEvent) GetDateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTime
+	}
+	return nil
+}
+
+func (x *ProductEvent) GetReceptionId() string {
+	if x != nil {
+		return x.ReceptionId
+	}
+	return ""
+}
+
+func (x *ProductEvent) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+type DummyLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DummyLoginRequest) Reset() {
+	*x = DummyLoginRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DummyLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DummyLoginRequest) ProtoMessage() {}
+
+func (x *DummyLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DummyLoginRequest.ProtoReflect.Descriptor instead.
+func (*DummyLoginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DummyLoginRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type LoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	mi := &file_proto_pvz_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type CreatePVZRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	City          string                 `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	ExternalCode  string                 `protobuf:"bytes,2,opt,name=external_code,json=externalCode,proto3" json:"external_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePVZRequest) Reset() {
+	*x = CreatePVZRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePVZRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePVZRequest) ProtoMessage() {}
+
+func (x *CreatePVZRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePVZRequest.ProtoReflect.Descriptor instead.
+func (*CreatePVZRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreatePVZRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *CreatePVZRequest) GetExternalCode() string {
+	if x != nil {
+		return x.ExternalCode
+	}
+	return ""
+}
+
+type CreatePVZResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pvz           *PVZ                   `protobuf:"bytes,1,opt,name=pvz,proto3" json:"pvz,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePVZResponse) Reset() {
+	*x = CreatePVZResponse{}
+	mi := &file_proto_pvz_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePVZResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePVZResponse) ProtoMessage() {}
+
+func (x *CreatePVZResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePVZResponse.ProtoReflect.Descriptor instead.
+func (*CreatePVZResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreatePVZResponse) GetPvz() *PVZ {
+	if x != nil {
+		return x.Pvz
+	}
+	return nil
+}
+
+type GetPVZRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPVZRequest) Reset() {
+	*x = GetPVZRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZRequest) ProtoMessage() {}
+
+func (x *GetPVZRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZRequest.ProtoReflect.Descriptor instead.
+func (*GetPVZRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetPVZRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetPVZResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pvz           *PVZ                   `protobuf:"bytes,1,opt,name=pvz,proto3" json:"pvz,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPVZResponse) Reset() {
+	*x = GetPVZResponse{}
+	mi := &file_proto_pvz_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZResponse) ProtoMessage() {}
+
+func (x *GetPVZResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZResponse.ProtoReflect.Descriptor instead.
+func (*GetPVZResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetPVZResponse) GetPvz() *PVZ {
+	if x != nil {
+		return x.Pvz
+	}
+	return nil
+}
+
+type GetPVZListRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Cities           []string               `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	ReceptionStatus  *ReceptionStatus       `protobuf:"varint,2,opt,name=reception_status,json=receptionStatus,proto3,enum=pvz.v1.ReceptionStatus,oneof" json:"reception_status,omitempty"`
+	ProductType      string                 `protobuf:"bytes,3,opt,name=product_type,json=productType,proto3" json:"product_type,omitempty"`
+	HasOpenReception *bool                  `protobuf:"varint,4,opt,name=has_open_reception,json=hasOpenReception,proto3,oneof" json:"has_open_reception,omitempty"`
+	// start_date and end_date filter receptions by their date_time.
+	StartDate      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	RegisteredFrom *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=registered_from,json=registeredFrom,proto3" json:"registered_from,omitempty"`
+	RegisteredTo   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=registered_to,json=registeredTo,proto3" json:"registered_to,omitempty"`
+	// At most 30, 30 when unset.
+	PageSize int32 `protobuf:"varint,9,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page, the filters must stay the same.
+	PageToken string     `protobuf:"bytes,10,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Include   PVZInclude `protobuf:"varint,11,opt,name=include,proto3,enum=pvz.v1.PVZInclude" json:"include,omitempty"`
+	// Lists deleted products too, for moderators only.
+	IncludeDeleted bool `protobuf:"varint,12,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetPVZListRequest) Reset() {
+	*x = GetPVZListRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZListRequest) ProtoMessage() {}
+
+func (x *GetPVZListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZListRequest.ProtoReflect.Descriptor instead.
+func (*GetPVZListRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetPVZListRequest) GetCities() []string {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *GetPVZListRequest) GetReceptionStatus() ReceptionStatus {
+	if x != nil && x.ReceptionStatus != nil {
+		return *x.ReceptionStatus
+	}
+	return ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS
+}
+
+func (x *GetPVZListRequest) GetProductType() string {
+	if x != nil {
+		return x.ProductType
+	}
+	return ""
+}
+
+func (x *GetPVZListRequest) GetHasOpenReception() bool {
+	if x != nil && x.HasOpenReception != nil {
+		return *x.HasOpenReception
+	}
+	return false
+}
+
+func (x *GetPVZListRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *GetPVZListRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *GetPVZListRequest) GetRegisteredFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RegisteredFrom
+	}
+	return nil
+}
+
+func (x *GetPVZListRequest) GetRegisteredTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RegisteredTo
+	}
+	return nil
+}
+
+func (x *GetPVZListRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetPVZListRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetPVZListRequest) GetInclude() PVZInclude {
+	if x != nil {
+		return x.Include
+	}
+	return PVZInclude_PVZ_INCLUDE_NONE
+}
+
+func (x *GetPVZListRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type PVZSummary struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Receptions     int32                  `protobuf:"varint,1,opt,name=receptions,proto3" json:"receptions,omitempty"`
+	OpenReceptions int32                  `protobuf:"varint,2,opt,name=open_receptions,json=openReceptions,proto3" json:"open_receptions,omitempty"`
+	Products       int32                  `protobuf:"varint,3,opt,name=products,proto3" json:"products,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PVZSummary) Reset() {
+	*x = PVZSummary{}
+	mi := &file_proto_pvz_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PVZSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PVZSummary) ProtoMessage() {}
+
+func (x *PVZSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PVZSummary.ProtoReflect.Descriptor instead.
+func (*PVZSummary) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PVZSummary) GetReceptions() int32 {
+	if x != nil {
+		return x.Receptions
+	}
+	return 0
+}
+
+func (x *PVZSummary) GetOpenReceptions() int32 {
+	if x != nil {
+		return x.OpenReceptions
+	}
+	return 0
+}
+
+func (x *PVZSummary) GetProducts() int32 {
+	if x != nil {
+		return x.Products
+	}
+	return 0
+}
+
+type ReceptionWithProducts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reception     *Reception             `protobuf:"bytes,1,opt,name=reception,proto3" json:"reception,omitempty"`
+	Products      []*Product             `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceptionWithProducts) Reset() {
+	*x = ReceptionWithProducts{}
+	mi := &file_proto_pvz_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceptionWithProducts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceptionWithProducts) ProtoMessage() {}
+
+func (x *ReceptionWithProducts) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceptionWithProducts.ProtoReflect.Descriptor instead.
+func (*ReceptionWithProducts) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReceptionWithProducts) GetReception() *Reception {
+	if x != nil {
+		return x.Reception
+	}
+	return nil
+}
+
+func (x *ReceptionWithProducts) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type PVZListItem struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Pvz     *PVZ                   `protobuf:"bytes,1,opt,name=pvz,proto3" json:"pvz,omitempty"`
+	Summary *PVZSummary            `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	// Filled according to include.
+	Receptions    []*ReceptionWithProducts `protobuf:"bytes,3,rep,name=receptions,proto3" json:"receptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PVZListItem) Reset() {
+	*x = PVZListItem{}
+	mi := &file_proto_pvz_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PVZListItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PVZListItem) ProtoMessage() {}
+
+func (x *PVZListItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PVZListItem.ProtoReflect.Descriptor instead.
+func (*PVZListItem) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PVZListItem) GetPvz() *PVZ {
+	if x != nil {
+		return x.Pvz
+	}
+	return nil
+}
+
+func (x *PVZListItem) GetSummary() *PVZSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *PVZListItem) GetReceptions() []*ReceptionWithProducts {
+	if x != nil {
+		return x.Receptions
+	}
+	return nil
+}
+
+type GetPVZListResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The same PVZ as items, kept for clients written before items.
+	Pvzs []*PVZ `protobuf:"bytes,1,rep,name=pvzs,proto3" json:"pvzs,omitempty"`
+	// Empty on the last page.
+	NextPageToken string         `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	TotalSize     int32          `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	Items         []*PVZListItem `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPVZListResponse) Reset() {
+	*x = GetPVZListResponse{}
+	mi := &file_proto_pvz_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZListResponse) ProtoMessage() {}
+
+func (x *GetPVZListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZListResponse.ProtoReflect.Descriptor instead.
+func (*GetPVZListResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetPVZListResponse) GetPvzs() []*PVZ {
+	if x != nil {
+		return x.Pvzs
+	}
+	return nil
+}
+
+func (x *GetPVZListResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetPVZListResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *GetPVZListResponse) GetItems() []*PVZListItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type CreateReceptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReceptionRequest) Reset() {
+	*x = CreateReceptionRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReceptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReceptionRequest) ProtoMessage() {}
+
+func (x *CreateReceptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReceptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateReceptionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CreateReceptionRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+type CreateReceptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reception     *Reception             `protobuf:"bytes,1,opt,name=reception,proto3" json:"reception,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReceptionResponse) Reset() {
+	*x = CreateReceptionResponse{}
+	mi := &file_proto_pvz_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReceptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReceptionResponse) ProtoMessage() {}
+
+func (x *CreateReceptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReceptionResponse.ProtoReflect.Descriptor instead.
+func (*CreateReceptionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CreateReceptionResponse) GetReception() *Reception {
+	if x != nil {
+		return x.Reception
+	}
+	return nil
+}
+
+type CloseLastReceptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseLastReceptionRequest) Reset() {
+	*x = CloseLastReceptionRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseLastReceptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseLastReceptionRequest) ProtoMessage() {}
+
+func (x *CloseLastReceptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseLastReceptionRequest.ProtoReflect.Descriptor instead.
+func (*CloseLastReceptionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CloseLastReceptionRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+type CloseLastReceptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reception     *Reception             `protobuf:"bytes,1,opt,name=reception,proto3" json:"reception,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseLastReceptionResponse) Reset() {
+	*x = CloseLastReceptionResponse{}
+	mi := &file_proto_pvz_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseLastReceptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseLastReceptionResponse) ProtoMessage() {}
+
+func (x *CloseLastReceptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseLastReceptionResponse.ProtoReflect.Descriptor instead.
+func (*CloseLastReceptionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CloseLastReceptionResponse) GetReception() *Reception {
+	if x != nil {
+		return x.Reception
+	}
+	return nil
+}
+
+type AddProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddProductRequest) Reset() {
+	*x = AddProductRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductRequest) ProtoMessage() {}
+
+func (x *AddProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductRequest.ProtoReflect.Descriptor instead.
+func (*AddProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AddProductRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *AddProductRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type AddProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddProductResponse) Reset() {
+	*x = AddProductResponse{}
+	mi := &file_proto_pvz_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductResponse) ProtoMessage() {}
+
+func (x *AddProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductResponse.ProtoReflect.Descriptor instead.
+func (*AddProductResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AddProductResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type DeleteLastProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLastProductRequest) Reset() {
+	*x = DeleteLastProductRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLastProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLastProductRequest) ProtoMessage() {}
+
+func (x *DeleteLastProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLastProductRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLastProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteLastProductRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+type DeleteLastProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLastProductResponse) Reset() {
+	*x = DeleteLastProductResponse{}
+	mi := &file_proto_pvz_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLastProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLastProductResponse) ProtoMessage() {}
+
+func (x *DeleteLastProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLastProductResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLastProductResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{23}
+}
+
+type GetProductRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Lookup:
+	//
+	//	*GetProductRequest_Id
+	//	*GetProductRequest_Barcode
+	Lookup        isGetProductRequest_Lookup `protobuf_oneof:"lookup"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductRequest) Reset() {
+	*x = GetProductRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductRequest) ProtoMessage() {}
+
+func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
+func (*GetProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetProductRequest) GetLookup() isGetProductRequest_Lookup {
+	if x != nil {
+		return x.Lookup
+	}
+	return nil
+}
+
+func (x *GetProductRequest) GetId() string {
+	if x != nil {
+		if x, ok := x.Lookup.(*GetProductRequest_Id); ok {
+			return x.Id
+		}
+	}
+	return ""
+}
+
+func (x *GetProductRequest) GetBarcode() string {
+	if x != nil {
+		if x, ok := x.Lookup.(*GetProductRequest_Barcode); ok {
+			return x.Barcode
+		}
+	}
+	return ""
+}
+
+type isGetProductRequest_Lookup interface {
+	isGetProductRequest_Lookup()
+}
+
+type GetProductRequest_Id struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3,oneof"`
+}
+
+type GetProductRequest_Barcode struct {
+	Barcode string `protobuf:"bytes,2,opt,name=barcode,proto3,oneof"`
+}
+
+func (*GetProductRequest_Id) isGetProductRequest_Lookup() {}
+
+func (*GetProductRequest_Barcode) isGetProductRequest_Lookup() {}
+
+type GetProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Reception     *Reception             `protobuf:"bytes,2,opt,name=reception,proto3" json:"reception,omitempty"`
+	Pvz           *PVZ                   `protobuf:"bytes,3,opt,name=pvz,proto3" json:"pvz,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Timeline      []*ProductEvent        `protobuf:"bytes,5,rep,name=timeline,proto3" json:"timeline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductResponse) Reset() {
+	*x = GetProductResponse{}
+	mi := &file_proto_pvz_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductResponse) ProtoMessage() {}
+
+func (x *GetProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductResponse.ProtoReflect.Descriptor instead.
+func (*GetProductResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetProductResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *GetProductResponse) GetReception() *Reception {
+	if x != nil {
+		return x.Reception
+	}
+	return nil
+}
+
+func (x *GetProductResponse) GetPvz() *PVZ {
+	if x != nil {
+		return x.Pvz
+	}
+	return nil
+}
+
+func (x *GetProductResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetProductResponse) GetTimeline() []*ProductEvent {
+	if x != nil {
+		return x.Timeline
+	}
+	return nil
+}
+
+type GetIntakeReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupBy       []IntakeDimension      `protobuf:"varint,1,rep,packed,name=group_by,json=groupBy,proto3,enum=pvz.v1.IntakeDimension" json:"group_by,omitempty"`
+	StartDate     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Cities        []string               `protobuf:"bytes,4,rep,name=cities,proto3" json:"cities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIntakeReportRequest) Reset() {
+	*x = GetIntakeReportRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIntakeReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIntakeReportRequest) ProtoMessage() {}
+
+func (x *GetIntakeReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIntakeReportRequest.ProtoReflect.Descriptor instead.
+func (*GetIntakeReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetIntakeReportRequest) GetGroupBy() []IntakeDimension {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+func (x *GetIntakeReportRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *GetIntakeReportRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *GetIntakeReportRequest) GetCities() []string {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+// Only the fields of the requested dimensions are set. period is the local
+// date (YYYY-MM-DD) the day, week or month starts on in the PVZ's city.
+type IntakeReportRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	City          *string                `protobuf:"bytes,1,opt,name=city,proto3,oneof" json:"city,omitempty"`
+	PvzId         *string                `protobuf:"bytes,2,opt,name=pvz_id,json=pvzId,proto3,oneof" json:"pvz_id,omitempty"`
+	Type          *string                `protobuf:"bytes,3,opt,name=type,proto3,oneof" json:"type,omitempty"`
+	Period        *string                `protobuf:"bytes,4,opt,name=period,proto3,oneof" json:"period,omitempty"`
+	Products      int64                  `protobuf:"varint,5,opt,name=products,proto3" json:"products,omitempty"`
+	Receptions    int64                  `protobuf:"varint,6,opt,name=receptions,proto3" json:"receptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntakeReportRow) Reset() {
+	*x = IntakeReportRow{}
+	mi := &file_proto_pvz_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntakeReportRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntakeReportRow) ProtoMessage() {}
+
+func (x *IntakeReportRow) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntakeReportRow.ProtoReflect.Descriptor instead.
+func (*IntakeReportRow) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *IntakeReportRow) GetCity() string {
+	if x != nil && x.City != nil {
+		return *x.City
+	}
+	return ""
+}
+
+func (x *IntakeReportRow) GetPvzId() string {
+	if x != nil && x.PvzId != nil {
+		return *x.PvzId
+	}
+	return ""
+}
+
+func (x *IntakeReportRow) GetType() string {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ""
+}
+
+func (x *IntakeReportRow) GetPeriod() string {
+	if x != nil && x.Period != nil {
+		return *x.Period
+	}
+	return ""
+}
+
+func (x *IntakeReportRow) GetProducts() int64 {
+	if x != nil {
+		return x.Products
+	}
+	return 0
+}
+
+func (x *IntakeReportRow) GetReceptions() int64 {
+	if x != nil {
+		return x.Receptions
+	}
+	return 0
+}
+
+type GetIntakeReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          []*IntakeReportRow     `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIntakeReportResponse) Reset() {
+	*x = GetIntakeReportResponse{}
+	mi := &file_proto_pvz_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIntakeReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIntakeReportResponse) ProtoMessage() {}
+
+func (x *GetIntakeReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIntakeReportResponse.ProtoReflect.Descriptor instead.
+func (*GetIntakeReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetIntakeReportResponse) GetRows() []*IntakeReportRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+// Empty lists match everything.
+type WatchReceptionsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	PvzIds []string               `protobuf:"bytes,1,rep,name=pvz_ids,json=pvzIds,proto3" json:"pvz_ids,omitempty"`
+	Cities []string               `protobuf:"bytes,2,rep,name=cities,proto3" json:"cities,omitempty"`
+	Types  []EventType            `protobuf:"varint,3,rep,packed,name=types,proto3,enum=pvz.v1.EventType" json:"types,omitempty"`
+	// resume_token of the last event received; the events published after it
+	// are sent first.
+	ResumeToken   string `protobuf:"bytes,4,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchReceptionsRequest) Reset() {
+	*x = WatchReceptionsRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchReceptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchReceptionsRequest) ProtoMessage() {}
+
+func (x *WatchReceptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchReceptionsRequest.ProtoReflect.Descriptor instead.
+func (*WatchReceptionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *WatchReceptionsRequest) GetPvzIds() []string {
+	if x != nil {
+		return x.PvzIds
+	}
+	return nil
+}
+
+func (x *WatchReceptionsRequest) GetCities() []string {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *WatchReceptionsRequest) GetTypes() []EventType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *WatchReceptionsRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type ReceptionEvent struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Type        EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=pvz.v1.EventType" json:"type,omitempty"`
+	Time        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	PvzId       string                 `protobuf:"bytes,3,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	City        string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	ReceptionId string                 `protobuf:"bytes,5,opt,name=reception_id,json=receptionId,proto3" json:"reception_id,omitempty"`
+	ProductId   *string                `protobuf:"bytes,6,opt,name=product_id,json=productId,proto3,oneof" json:"product_id,omitempty"`
+	ProductType string                 `protobuf:"bytes,7,opt,name=product_type,json=productType,proto3" json:"product_type,omitempty"`
+	// Only sent to moderators.
+	UserId        *string `protobuf:"bytes,8,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ResumeToken   string  `protobuf:"bytes,9,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceptionEvent) Reset() {
+	*x = ReceptionEvent{}
+	mi := &file_proto_pvz_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceptionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceptionEvent) ProtoMessage() {}
+
+func (x *ReceptionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceptionEvent.ProtoReflect.Descriptor instead.
+func (*ReceptionEvent) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ReceptionEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *ReceptionEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *ReceptionEvent) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *ReceptionEvent) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *ReceptionEvent) GetReceptionId() string {
+	if x != nil {
+		return x.ReceptionId
+	}
+	return ""
+}
+
+func (x *ReceptionEvent) GetProductId() string {
+	if x != nil && x.ProductId != nil {
+		return *x.ProductId
+	}
+	return ""
+}
+
+func (x *ReceptionEvent) GetProductType() string {
+	if x != nil {
+		return x.ProductType
+	}
+	return ""
+}
+
+func (x *ReceptionEvent) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *ReceptionEvent) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+var File_proto_pvz_proto protoreflect.FileDescriptor
+
+const file_proto_pvz_proto_rawDesc = "" +
+	"\n" +
+	"\x0fproto/pvz.proto\x12\x06pvz.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x97\x01\n" +
+	"\x03PVZ\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12G\n" +
+	"\x11registration_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x10registrationDate\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12#\n" +
+	"\rexternal_code\x18\x04 \x01(\tR\fexternalCode\"\x9c\x01\n" +
+	"\tReception\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\tdate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdateTime\x12\x15\n" +
+	"\x06pvz_id\x18\x03 \x01(\tR\x05pvzId\x12/\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x17.pvz.v1.ReceptionStatusR\x06status\"\xf7\x01\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\tdate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdateTime\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12!\n" +
+	"\freception_id\x18\x04 \x01(\tR\vreceptionId\x129\n" +
+	"\n" +
+	"deleted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\"\n" +
+	"\n" +
+	"deleted_by\x18\x06 \x01(\tH\x00R\tdeletedBy\x88\x01\x01B\r\n" +
+	"\v_deleted_by\"\x95\x01\n" +
+	"\fProductEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x127\n" +
+	"\tdate_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdateTime\x12!\n" +
+	"\freception_id\x18\x03 \x01(\tR\vreceptionId\x12\x15\n" +
+	"\x06pvz_id\x18\x04 \x01(\tR\x05pvzId\"'\n" +
+	"\x11DummyLoginRequest\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\"@\n" +
+	"\fLoginRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"%\n" +
+	"\rLoginResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"K\n" +
+	"\x10CreatePVZRequest\x12\x12\n" +
+	"\x04city\x18\x01 \x01(\tR\x04city\x12#\n" +
+	"\rexternal_code\x18\x02 \x01(\tR\fexternalCode\"2\n" +
+	"\x11CreatePVZResponse\x12\x1d\n" +
+	"\x03pvz\x18\x01 \x01(\v2\v.pvz.v1.PVZR\x03pvz\"\x1f\n" +
+	"\rGetPVZRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"/\n" +
+	"\x0eGetPVZResponse\x12\x1d\n" +
+	"\x03pvz\x18\x01 \x01(\v2\v.pvz.v1.PVZR\x03pvz\"\x81\x05\n" +
+	"\x11GetPVZListRequest\x12\x16\n" +
+	"\x06cities\x18\x01 \x03(\tR\x06cities\x12G\n" +
+	"\x10reception_status\x18\x02 \x01(\x0e2\x17.pvz.v1.ReceptionStatusH\x00R\x0freceptionStatus\x88\x01\x01\x12!\n" +
+	"\fproduct_type\x18\x03 \x01(\tR\vproductType\x121\n" +
+	"\x12has_open_reception\x18\x04 \x01(\bH\x01R\x10hasOpenReception\x88\x01\x01\x129\n" +
+	"\n" +
+	"start_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12C\n" +
+	"\x0fregistered_from\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x0eregisteredFrom\x12?\n" +
+	"\rregistered_to\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\fregisteredTo\x12\x1b\n" +
+	"\tpage_size\x18\t \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\n" +
+	" \x01(\tR\tpageToken\x12,\n" +
+	"\ainclude\x18\v \x01(\x0e2\x12.pvz.v1.PVZIncludeR\ainclude\x12'\n" +
+	"\x0finclude_deleted\x18\f \x01(\bR\x0eincludeDeletedB\x13\n" +
+	"\x11_reception_statusB\x15\n" +
+	"\x13_has_open_reception\"q\n" +
+	"\n" +
+	"PVZSummary\x12\x1e\n" +
+	"\n" +
+	"receptions\x18\x01 \x01(\x05R\n" +
+	"receptions\x12'\n" +
+	"\x0fopen_receptions\x18\x02 \x01(\x05R\x0eopenReceptions\x12\x1a\n" +
+	"\bproducts\x18\x03 \x01(\x05R\bproducts\"u\n" +
+	"\x15ReceptionWithProducts\x12/\n" +
+	"\treception\x18\x01 \x01(\v2\x11.pvz.v1.ReceptionR\treception\x12+\n" +
+	"\bproducts\x18\x02 \x03(\v2\x0f.pvz.v1.ProductR\bproducts\"\x99\x01\n" +
+	"\vPVZListItem\x12\x1d\n" +
+	"\x03pvz\x18\x01 \x01(\v2\v.pvz.v1.PVZR\x03pvz\x12,\n" +
+	"\asummary\x18\x02 \x01(\v2\x12.pvz.v1.PVZSummaryR\asummary\x12=\n" +
+	"\n" +
+	"receptions\x18\x03 \x03(\v2\x1d.pvz.v1.ReceptionWithProductsR\n" +
+	"receptions\"\xa7\x01\n" +
+	"\x12GetPVZListResponse\x12\x1f\n" +
+	"\x04pvzs\x18\x01 \x03(\v2\v.pvz.v1.PVZR\x04pvzs\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\x12)\n" +
+	"\x05items\x18\x04 \x03(\v2\x13.pvz.v1.PVZListItemR\x05items\"/\n" +
+	"\x16CreateReceptionRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\"J\n" +
+	"\x17CreateReceptionResponse\x12/\n" +
+	"\treception\x18\x01 \x01(\v2\x11.pvz.v1.ReceptionR\treception\"2\n" +
+	"\x19CloseLastReceptionRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\"M\n" +
+	"\x1aCloseLastReceptionResponse\x12/\n" +
+	"\treception\x18\x01 \x01(\v2\x11.pvz.v1.ReceptionR\treception\">\n" +
+	"\x11AddProductRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\"?\n" +
+	"\x12AddProductResponse\x12)\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0f.pvz.v1.ProductR\aproduct\"1\n" +
+	"\x18DeleteLastProductRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\"\x1b\n" +
+	"\x19DeleteLastProductResponse\"K\n" +
+	"\x11GetProductRequest\x12\x10\n" +
+	"\x02id\x18\x01 \x01(\tH\x00R\x02id\x12\x1a\n" +
+	"\abarcode\x18\x02 \x01(\tH\x00R\abarcodeB\b\n" +
+	"\x06lookup\"\xd9\x01\n" +
+	"\x12GetProductResponse\x12)\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0f.pvz.v1.ProductR\aproduct\x12/\n" +
+	"\treception\x18\x02 \x01(\v2\x11.pvz.v1.ReceptionR\treception\x12\x1d\n" +
+	"\x03pvz\x18\x03 \x01(\v2\v.pvz.v1.PVZR\x03pvz\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x120\n" +
+	"\btimeline\x18\x05 \x03(\v2\x14.pvz.v1.ProductEventR\btimeline\"\xd6\x01\n" +
+	"\x16GetIntakeReportRequest\x122\n" +
+	"\bgroup_by\x18\x01 \x03(\x0e2\x17.pvz.v1.IntakeDimensionR\agroupBy\x129\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x16\n" +
+	"\x06cities\x18\x04 \x03(\tR\x06cities\"\xe0\x01\n" +
+	"\x0fIntakeReportRow\x12\x17\n" +
+	"\x04city\x18\x01 \x01(\tH\x00R\x04city\x88\x01\x01\x12\x1a\n" +
+	"\x06pvz_id\x18\x02 \x01(\tH\x01R\x05pvzId\x88\x01\x01\x12\x17\n" +
+	"\x04type\x18\x03 \x01(\tH\x02R\x04type\x88\x01\x01\x12\x1b\n" +
+	"\x06period\x18\x04 \x01(\tH\x03R\x06period\x88\x01\x01\x12\x1a\n" +
+	"\bproducts\x18\x05 \x01(\x03R\bproducts\x12\x1e\n" +
+	"\n" +
+	"receptions\x18\x06 \x01(\x03R\n" +
+	"receptionsB\a\n" +
+	"\x05_cityB\t\n" +
+	"\a_pvz_idB\a\n" +
+	"\x05_typeB\t\n" +
+	"\a_period\"F\n" +
+	"\x17GetIntakeReportResponse\x12+\n" +
+	"\x04rows\x18\x01 \x03(\v2\x17.pvz.v1.IntakeReportRowR\x04rows\"\x95\x01\n" +
+	"\x16WatchReceptionsRequest\x12\x17\n" +
+	"\apvz_ids\x18\x01 \x03(\tR\x06pvzIds\x12\x16\n" +
+	"\x06cities\x18\x02 \x03(\tR\x06cities\x12'\n" +
+	"\x05types\x18\x03 \x03(\x0e2\x11.pvz.v1.EventTypeR\x05types\x12!\n" +
+	"\fresume_token\x18\x04 \x01(\tR\vresumeToken\"\xd8\x02\n" +
+	"\x0eReceptionEvent\x12%\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x11.pvz.v1.EventTypeR\x04type\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x15\n" +
+	"\x06pvz_id\x18\x03 \x01(\tR\x05pvzId\x12\x12\n" +
+	"\x04city\x18\x04 \x01(\tR\x04city\x12!\n" +
+	"\freception_id\x18\x05 \x01(\tR\vreceptionId\x12\"\n" +
+	"\n" +
+	"product_id\x18\x06 \x01(\tH\x00R\tproductId\x88\x01\x01\x12!\n" +
+	"\fproduct_type\x18\a \x01(\tR\vproductType\x12\x1c\n" +
+	"\auser_id\x18\b \x01(\tH\x01R\x06userId\x88\x01\x01\x12!\n" +
+	"\fresume_token\x18\t \x01(\tR\vresumeTokenB\r\n" +
+	"\v_product_idB\n" +
+	"\n" +
+	"\b_user_id*P\n" +
+	"\x0fReceptionStatus\x12 \n" +
+	"\x1cRECEPTION_STATUS_IN_PROGRESS\x10\x00\x12\x1b\n" +
+	"\x17RECEPTION_STATUS_CLOSED\x10\x01*X\n" +
+	"\n" +
+	"PVZInclude\x12\x14\n" +
+	"\x10PVZ_INCLUDE_NONE\x10\x00\x12\x1a\n" +
+	"\x16PVZ_INCLUDE_RECEPTIONS\x10\x01\x12\x18\n" +
+	"\x14PVZ_INCLUDE_PRODUCTS\x10\x02*\xd4\x01\n" +
+	"\x0fIntakeDimension\x12 \n" +
+	"\x1cINTAKE_DIMENSION_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15INTAKE_DIMENSION_CITY\x10\x01\x12\x18\n" +
+	"\x14INTAKE_DIMENSION_PVZ\x10\x02\x12\x19\n" +
+	"\x15INTAKE_DIMENSION_TYPE\x10\x03\x12\x18\n" +
+	"\x14INTAKE_DIMENSION_DAY\x10\x04\x12\x19\n" +
+	"\x15INTAKE_DIMENSION_WEEK\x10\x05\x12\x1a\n" +
+	"\x16INTAKE_DIMENSION_MONTH\x10\x06*\xe5\x01\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bEVENT_TYPE_RECEPTION_OPENED\x10\x01\x12\x1f\n" +
+	"\x1bEVENT_TYPE_RECEPTION_CLOSED\x10\x02\x12\x1c\n" +
+	"\x18EVENT_TYPE_PRODUCT_ADDED\x10\x03\x12\x1e\n" +
+	"\x1aEVENT_TYPE_PRODUCT_DELETED\x10\x04\x12\x1f\n" +
+	"\x1bEVENT_TYPE_PRODUCT_RESTORED\x10\x05\x12\x1b\n" +
+	"\x17EVENT_TYPE_STREAM_RESET\x10\x062\xf8\x06\n" +
+	"\n" +
+	"PVZService\x12>\n" +
+	"\n" +
+	"DummyLogin\x12\x19.pvz.v1.DummyLoginRequest\x1a\x15.pvz.v1.LoginResponse\x124\n" +
+	"\x05Login\x12\x14.pvz.v1.LoginRequest\x1a\x15.pvz.v1.LoginResponse\x12@\n" +
+	"\tCreatePVZ\x12\x18.pvz.v1.CreatePVZRequest\x1a\x19.pvz.v1.CreatePVZResponse\x127\n" +
+	"\x06GetPVZ\x12\x15.pvz.v1.GetPVZRequest\x1a\x16.pvz.v1.GetPVZResponse\x12C\n" +
+	"\n" +
+	"GetPVZList\x12\x19.pvz.v1.GetPVZListRequest\x1a\x1a.pvz.v1.GetPVZListResponse\x12R\n" +
+	"\x0fCreateReception\x12\x1e.pvz.v1.CreateReceptionRequest\x1a\x1f.pvz.v1.CreateReceptionResponse\x12[\n" +
+	"\x12CloseLastReception\x12!.pvz.v1.CloseLastReceptionRequest\x1a\".pvz.v1.CloseLastReceptionResponse\x12C\n" +
+	"\n" +
+	"AddProduct\x12\x19.pvz.v1.AddProductRequest\x1a\x1a.pvz.v1.AddProductResponse\x12X\n" +
+	"\x11DeleteLastProduct\x12 .pvz.v1.DeleteLastProductRequest\x1a!.pvz.v1.DeleteLastProductResponse\x12C\n" +
+	"\n" +
+	"GetProduct\x12\x19.pvz.v1.GetProductRequest\x1a\x1a.pvz.v1.GetProductResponse\x12R\n" +
+	"\x0fGetIntakeReport\x12\x1e.pvz.v1.GetIntakeReportRequest\x1a\x1f.pvz.v1.GetIntakeReportResponse\x12K\n" +
+	"\x0fWatchReceptions\x12\x1e.pvz.v1.WatchReceptionsRequest\x1a\x16.pvz.v1.ReceptionEvent0\x01B\x14Z\x12internal/generatedb\x06proto3"
+
+var (
+	file_proto_pvz_proto_rawDescOnce sync.Once
+	file_proto_pvz_proto_rawDescData []byte
+)
+
+func file_proto_pvz_proto_rawDescGZIP() []byte {
+	file_proto_pvz_proto_rawDescOnce.Do(func() {
+		file_proto_pvz_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_pvz_proto_rawDesc), len(file_proto_pvz_proto_rawDesc)))
+	})
+	return file_proto_pvz_proto_rawDescData
+}
+
+var file_proto_pvz_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_pvz_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_proto_pvz_proto_goTypes = []any{
+	(ReceptionStatus)(0),               // 0: pvz.v1.ReceptionStatus
+	(PVZInclude)(0),                    // 1: pvz.v1.PVZInclude
+	(IntakeDimension)(0),               // 2: pvz.v1.IntakeDimension
+	(EventType)(0),                     // 3: pvz.v1.EventType
+	(*PVZ)(nil),                        // 4: pvz.v1.PVZ
+	(*Reception)(nil),                  // 5: pvz.v1.Reception
+	(*Product)(nil),                    // 6: pvz.v1.Product
+	(*ProductEvent)(nil),               // 7: pvz.v1.ProductEvent
+	(*DummyLoginRequest)(nil),          // 8: pvz.v1.DummyLoginRequest
+	(*LoginRequest)(nil),               // 9: pvz.v1.LoginRequest
+	(*LoginResponse)(nil),              // 10: pvz.v1.LoginResponse
+	(*CreatePVZRequest)(nil),           // 11: pvz.v1.CreatePVZRequest
+	(*CreatePVZResponse)(nil),          // 12: pvz.v1.CreatePVZResponse
+	(*GetPVZRequest)(nil),              // 13: pvz.v1.GetPVZRequest
+	(*GetPVZResponse)(nil),             // 14: pvz.v1.GetPVZResponse
+	(*GetPVZListRequest)(nil),          // 15: pvz.v1.GetPVZListRequest
+	(*PVZSummary)(nil),                 // 16: pvz.v1.PVZSummary
+	(*ReceptionWithProducts)(nil),      // 17: pvz.v1.ReceptionWithProducts
+	(*PVZListItem)(nil),                // 18: pvz.v1.PVZListItem
+	(*GetPVZListResponse)(nil),         // 19: pvz.v1.GetPVZListResponse
+	(*CreateReceptionRequest)(nil),     // 20: pvz.v1.CreateReceptionRequest
+	(*CreateReceptionResponse)(nil),    // 21: pvz.v1.CreateReceptionResponse
+	(*CloseLastReceptionRequest)(nil),  // 22: pvz.v1.CloseLastReceptionRequest
+	(*CloseLastReceptionResponse)(nil), // 23: pvz.v1.CloseLastReceptionResponse
+	(*AddProductRequest)(nil),          // 24: pvz.v1.AddProductRequest
+	(*AddProductResponse)(nil),         // 25: pvz.v1.AddProductResponse
+	(*DeleteLastProductRequest)(nil),   // 26: pvz.v1.DeleteLastProductRequest
+	(*DeleteLastProductResponse)(nil),  // 27: pvz.v1.DeleteLastProductResponse
+	(*GetProductRequest)(nil),          // 28: pvz.v1.GetProductRequest
+	(*GetProductResponse)(nil),         // 29: pvz.v1.GetProductResponse
+	(*GetIntakeReportRequest)(nil),     // 30: pvz.v1.GetIntakeReportRequest
+	(*IntakeReportRow)(nil),            // 31: pvz.v1.IntakeReportRow
+	(*GetIntakeReportResponse)(nil),    // 32: pvz.v1.GetIntakeReportResponse
+	(*WatchReceptionsRequest)(nil),     // 33: pvz.v1.WatchReceptionsRequest
+	(*ReceptionEvent)(nil),             // 34: pvz.v1.ReceptionEvent
+	(*timestamppb.Timestamp)(nil),      // 35: google.protobuf.Timestamp
+}
+var file_proto_pvz_proto_depIdxs = []int32{
+	35, // 0: pvz.v1.PVZ.registration_date:type_name -> google.protobuf.Timestamp
+	35, // 1: pvz.v1.Reception.date_time:type_name -> google.protobuf.Timestamp
+	0,  // 2: pvz.v1.Reception.status:type_name -> pvz.v1.ReceptionStatus
+	35, // 3: pvz.v1.Product.date_time:type_name -> google.protobuf.Timestamp
+	35, // 4: pvz.v1.Product.deleted_at:type_name -> google.protobuf.Timestamp
+	35, // 5: pvz.v1.ProductEvent.date_time:type_name -> google.protobuf.Timestamp
+	4,  // 6: pvz.v1.CreatePVZResponse.pvz:type_name -> pvz.v1.PVZ
+	4,  // 7: pvz.v1.GetPVZResponse.pvz:type_name -> pvz.v1.PVZ
+	0,  // 8: pvz.v1.GetPVZListRequest.reception_status:type_name -> pvz.v1.ReceptionStatus
+	35, // 9: pvz.v1.GetPVZListRequest.start_date:type_name -> google.protobuf.Timestamp
+	35, // 10: pvz.v1.GetPVZListRequest.end_date:type_name -> google.protobuf.Timestamp
+	35, // 11: pvz.v1.GetPVZListRequest.registered_from:type_name -> google.protobuf.Timestamp
+	35, // 12: pvz.v1.GetPVZListRequest.registered_to:type_name -> google.protobuf.Timestamp
+	1,  // 13: pvz.v1.GetPVZListRequest.include:type_name -> pvz.v1.PVZInclude
+	5,  // 14: pvz.v1.ReceptionWithProducts.reception:type_name -> pvz.v1.Reception
+	6,  // 15: pvz.v1.ReceptionWithProducts.products:type_name -> pvz.v1.Product
+	4,  // 16: pvz.v1.PVZListItem.pvz:type_name -> pvz.v1.PVZ
+	16, // 17: pvz.v1.PVZListItem.summary:type_name -> pvz.v1.PVZSummary
+	17, // 18: pvz.v1.PVZListItem.receptions:type_name -> pvz.v1.ReceptionWithProducts
+	4,  // 19: pvz.v1.GetPVZListResponse.pvzs:type_name -> pvz.v1.PVZ
+	18, // 20: pvz.v1.GetPVZListResponse.items:type_name -> pvz.v1.PVZListItem
+	5,  // 21: pvz.v1.CreateReceptionResponse.reception:type_name -> pvz.v1.Reception
+	5,  // 22: pvz.v1.CloseLastReceptionResponse.reception:type_name -> pvz.v1.Reception
+	6,  // 23: pvz.v1.AddProductResponse.product:type_name -> pvz.v1.Product
+	6,  // 24: pvz.v1.GetProductResponse.product:type_name -> pvz.v1.Product
+	5,  // 25: pvz.v1.GetProductResponse.reception:type_name -> pvz.v1.Reception
+	4,  // 26: pvz.v1.GetProductResponse.pvz:type_name -> pvz.v1.PVZ
+	7,  // 27: pvz.v1.GetProductResponse.timeline:type_name -> pvz.v1.ProductEvent
+	2,  // 28: pvz.v1.GetIntakeReportRequest.group_by:type_name -> pvz.v1.IntakeDimension
+	35, // 29: pvz.v1.GetIntakeReportRequest.start_date:type_name -> google.protobuf.Timestamp
+	35, // 30: pvz.v1.GetIntakeReportRequest.end_date:type_name -> google.protobuf.Timestamp
+	31, // 31: pvz.v1.GetIntakeReportResponse.rows:type_name -> pvz.v1.IntakeReportRow
+	3,  // 32: pvz.v1.WatchReceptionsRequest.types:type_name -> pvz.v1.EventType
+	3,  // 33: pvz.v1.ReceptionEvent.type:type_name -> pvz.v1.EventType
+	35, // 34: pvz.v1.ReceptionEvent.time:type_name -> google.protobuf.Timestamp
+	8,  // 35: pvz.v1.PVZService.DummyLogin:input_type -> pvz.v1.DummyLoginRequest
+	9,  // 36: pvz.v1.PVZService.Login:input_type -> pvz.v1.LoginRequest
+	11, // 37: pvz.v1.PVZService.CreatePVZ:input_type -> pvz.v1.CreatePVZRequest
+	13, // 38: pvz.v1.PVZService.GetPVZ:input_type -> pvz.v1.GetPVZRequest
+	15, // 39: pvz.v1.PVZService.GetPVZList:input_type -> pvz.v1.GetPVZListRequest
+	20, // 40: pvz.v1.PVZService.CreateReception:input_type -> pvz.v1.CreateReceptionRequest
+	22, // 41: pvz.v1.PVZService.CloseLastReception:input_type -> pvz.v1.CloseLastReceptionRequest
+	24, // 42: pvz.v1.PVZService.AddProduct:input_type -> pvz.v1.AddProductRequest
+	26, // 43: pvz.v1.PVZService.DeleteLastProduct:input_type -> pvz.v1.DeleteLastProductRequest
+	28, // 44: pvz.v1.PVZService.GetProduct:input_type -> pvz.v1.GetProductRequest
+	30, // 45: pvz.v1.PVZService.GetIntakeReport:input_type -> pvz.v1.GetIntakeReportRequest
+	33, // 46: pvz.v1.PVZService.WatchReceptions:input_type -> pvz.v1.WatchReceptionsRequest
+	10, // 47: pvz.v1.PVZService.DummyLogin:output_type -> pvz.v1.LoginResponse
+	10, // 48: pvz.v1.PVZService.Login:output_type -> pvz.v1.LoginResponse
+	12, // 49: pvz.v1.PVZService.CreatePVZ:output_type -> pvz.v1.CreatePVZResponse
+	14, // 50: pvz.v1.PVZService.GetPVZ:output_type -> pvz.v1.GetPVZResponse
+	19, // 51: pvz.v1.PVZService.GetPVZList:output_type -> pvz.v1.GetPVZListResponse
+	21, // 52: pvz.v1.PVZService.CreateReception:output_type -> pvz.v1.CreateReceptionResponse
+	23, // 53: pvz.v1.PVZService.CloseLastReception:output_type -> pvz.v1.CloseLastReceptionResponse
+	25, // 54: pvz.v1.PVZService.AddProduct:output_type -> pvz.v1.AddProductResponse
+	27, // 55: pvz.v1.PVZService.DeleteLastProduct:output_type -> pvz.v1.DeleteLastProductResponse
+	29, // 56: pvz.v1.PVZService.GetProduct:output_type -> pvz.v1.GetProductResponse
+	32, // 57: pvz.v1.PVZService.GetIntakeReport:output_type -> pvz.v1.GetIntakeReportResponse
+	34, // 58: pvz.v1.PVZService.WatchReceptions:output_type -> pvz.v1.ReceptionEvent
+	47, // [47:59] is the sub-list for method output_type
+	35, // [35:47] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
+}
+
+func init() { file_proto_pvz_proto_init() }
+func file_proto_pvz_proto_init() {
+	if File_proto_pvz_proto != nil {
+		return
+	}
+	file_proto_pvz_proto_msgTypes[2].OneofWrappers = []any{}
+	file_proto_pvz_proto_msgTypes[11].OneofWrappers = []any{}
+	file_proto_pvz_proto_msgTypes[24].OneofWrappers = []any{
+		(*GetProductRequest_Id)(nil),
+		(*GetProductRequest_Barcode)(nil),
 	}
+	file_proto_pvz_proto_msgTypes[27].OneofWrappers = []any{}
+	file_proto_pvz_proto_msgTypes[30].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_pvz_proto_rawDesc), len(file_proto_pvz_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   3,
+			NumEnums:      4,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
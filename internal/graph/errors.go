@@ -0,0 +1,9 @@
+package graph
+
+import "errors"
+
+var ErrUnauthenticated = errors.New("user not found in context")
+
+var ErrForbidden = errors.New("insufficient permissions")
+
+var ErrIncludeDeletedForbidden = errors.New("includeDeleted is available to moderators only")
@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_PvzListBatchesReceptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	schema := NewSchema(mockService)
+	user := &models.User{Id: uuid.New(), Role: models.RoleEmployee}
+
+	firstId, secondId := uuid.New(), uuid.New()
+	receptionId := uuid.New()
+	testTime := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	mockService.EXPECT().GetPvz(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *dto.GetPvzRequest) (*dto.GetPvzResponse, error) {
+			assert.Equal(t, dto.IncludeNone, request.Include)
+			assert.Equal(t, []string{"Москва", "Казань"}, request.Cities)
+			assert.Equal(t, 5, request.Limit)
+			return &dto.GetPvzResponse{
+				Items: []*dto.PVZWithReceptions{
+					{PVZ: dto.PVZResponse{Id: firstId, RegistrationDate: testTime, City: "Москва"}, Summary: dto.PVZSummary{Receptions: 1, Products: 1}},
+					{PVZ: dto.PVZResponse{Id: secondId, RegistrationDate: testTime, City: "Казань"}},
+				},
+				Total: 2,
+				Page:  1,
+				Limit: 5,
+			}, nil
+		})
+	mockService.EXPECT().GetPvzReceptions(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, pvzIds []uuid.UUID, request *dto.GetPvzRequest) (map[uuid.UUID][]dto.ReceptionWithProducts, error) {
+			assert.ElementsMatch(t, []uuid.UUID{firstId, secondId}, pvzIds)
+			assert.Equal(t, "close", request.ReceptionStatus)
+			return map[uuid.UUID][]dto.ReceptionWithProducts{
+				firstId: {{
+					Reception: dto.ReceptionResponse{Id: receptionId, DateTime: testTime, PvzId: firstId, Status: "close"},
+					Products:  []dto.ProductResponse{{Id: uuid.New(), DateTime: testTime, Type: "обувь", ReceptionId: receptionId}},
+				}},
+			}, nil
+		}).Times(1)
+
+	response := schema.Exec(context.Background(), user, Request{
+		Query: `query($filter: PVZFilter) {
+			pvzList(filter: $filter, limit: 5) {
+				total page
+				items { city summary { receptions products } receptions(status: "close") { id status products { type } } }
+			}
+		}`,
+		Variables: map[string]any{"filter": map[string]any{"cities": []any{"Москва", "Казань"}}},
+	})
+	require.Empty(t, response.Errors)
+
+	var data struct {
+		PvzList struct {
+			Total int
+			Page  int
+			Items []struct {
+				City       string
+				Summary    dto.PVZSummary
+				Receptions []struct {
+					ID       string
+					Status   string
+					Products []struct{ Type string }
+				}
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(response.Data, &data))
+	require.Len(t, data.PvzList.Items, 2)
+	assert.Equal(t, 2, data.PvzList.Total)
+	assert.Equal(t, dto.PVZSummary{Receptions: 1, Products: 1}, data.PvzList.Items[0].Summary)
+	require.Len(t, data.PvzList.Items[0].Receptions, 1)
+	assert.Equal(t, receptionId.String(), data.PvzList.Items[0].Receptions[0].ID)
+	assert.Equal(t, "обувь", data.PvzList.Items[0].Receptions[0].Products[0].Type)
+	assert.Empty(t, data.PvzList.Items[1].Receptions)
+}
+
+func TestSchema_Mutations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	schema := NewSchema(mockService)
+	employee := &models.User{Id: uuid.New(), Role: models.RoleEmployee}
+	moderator := &models.User{Id: uuid.New(), Role: models.RoleModerator}
+	pvzId := uuid.New()
+
+	t.Run("createPVZ is for moderators", func(t *testing.T) {
+		response := schema.Exec(context.Background(), employee, Request{Query: `mutation { createPVZ(city: "Москва") { id } }`})
+		require.Len(t, response.Errors, 1)
+		assert.ErrorIs(t, response.Errors[0].ResolverError, ErrForbidden)
+	})
+
+	t.Run("createPVZ", func(t *testing.T) {
+		mockService.EXPECT().CreatePVZ(gomock.Any(), &dto.PvzCreateRequest{City: "Москва"}).
+			Return(&dto.PvzCreateResponse{Id: pvzId, City: "Москва"}, nil)
+
+		response := schema.Exec(context.Background(), moderator, Request{Query: `mutation { createPVZ(city: "Москва") { id city summary { receptions } } }`})
+		require.Empty(t, response.Errors)
+		assert.JSONEq(t, `{"createPVZ":{"id":"`+pvzId.String()+`","city":"Москва","summary":{"receptions":0}}}`, string(response.Data))
+	})
+
+	t.Run("deleteLastProduct passes the current user", func(t *testing.T) {
+		mockService.EXPECT().DeleteLastProduct(gomock.Any(), pvzId, employee.Id).Return(nil)
+
+		response := schema.Exec(context.Background(), employee, Request{
+			Query:     `mutation($pvzId: ID!) { deleteLastProduct(pvzId: $pvzId) }`,
+			Variables: map[string]any{"pvzId": pvzId.String()},
+		})
+		require.Empty(t, response.Errors)
+		assert.JSONEq(t, `{"deleteLastProduct":true}`, string(response.Data))
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		response := schema.Exec(context.Background(), employee, Request{Query: `mutation { createReception(pvzId: "42") { id } }`})
+		require.Len(t, response.Errors, 1)
+		assert.Contains(t, response.Errors[0].Message, "invalid UUID")
+	})
+
+	t.Run("closed reception loads its products", func(t *testing.T) {
+		receptionId := uuid.New()
+		mockService.EXPECT().CloseReception(gomock.Any(), pvzId).
+			Return(&dto.CloseLastReceptionResponse{Id: receptionId, PvzId: pvzId, Status: "close"}, nil)
+		mockService.EXPECT().GetPvzReceptions(gomock.Any(), []uuid.UUID{pvzId}, gomock.Any()).
+			Return(map[uuid.UUID][]dto.ReceptionWithProducts{pvzId: {{
+				Reception: dto.ReceptionResponse{Id: receptionId, PvzId: pvzId, Status: "close"},
+				Products:  []dto.ProductResponse{{Type: "одежда"}},
+			}}}, nil)
+
+		response := schema.Exec(context.Background(), employee, Request{
+			Query: `mutation { closeLastReception(pvzId: "` + pvzId.String() + `") { status products { type } } }`,
+		})
+		require.Empty(t, response.Errors)
+		assert.JSONEq(t, `{"closeLastReception":{"status":"close","products":[{"type":"одежда"}]}}`, string(response.Data))
+	})
+}
+
+func TestSchema_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	schema := NewSchema(mocks.NewMockPvzService(ctrl))
+	user := &models.User{Id: uuid.New(), Role: models.RoleModerator}
+
+	response := schema.Exec(context.Background(), user, Request{
+		Query: "{ me { id } }" + strings.Repeat(" ", maxQueryLength),
+	})
+	require.Len(t, response.Errors, 1)
+	assert.Contains(t, response.Errors[0].Message, "longer than")
+
+	deep := "name"
+	for range maxQueryDepth {
+		deep = "ofType { " + deep + " }"
+	}
+	response = schema.Exec(context.Background(), user, Request{Query: "{ __schema { types { fields { type { " + deep + " } } } } }"})
+	require.NotEmpty(t, response.Errors)
+	assert.Equal(t, "MaxDepthExceeded", response.Errors[0].Rule)
+}
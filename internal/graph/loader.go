@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+// receptionFilter is the comparable part of dto.GetPvzRequest that affects
+// which receptions and products are loaded.
+type receptionFilter struct {
+	status         string
+	productType    string
+	startDate      time.Time
+	endDate        time.Time
+	includeDeleted bool
+}
+
+func (f receptionFilter) request() *dto.GetPvzRequest {
+	return &dto.GetPvzRequest{
+		ReceptionStatus: f.status,
+		ProductType:     f.productType,
+		StartDate:       f.startDate,
+		EndDate:         f.endDate,
+		IncludeDeleted:  f.includeDeleted,
+	}
+}
+
+// receptionLoader batches reception lookups of one GraphQL request. PVZ ids
+// are primed as they are resolved, and the first lookup for a filter fetches
+// receptions of every primed PVZ at once, so a page of N PVZ costs one query
+// per distinct filter instead of N.
+type receptionLoader struct {
+	srv PvzService
+
+	mu      sync.Mutex
+	primed  []uuid.UUID
+	batches map[receptionFilter]map[uuid.UUID][]dto.ReceptionWithProducts
+}
+
+func newReceptionLoader(srv PvzService) *receptionLoader {
+	return &receptionLoader{
+		srv:     srv,
+		batches: make(map[receptionFilter]map[uuid.UUID][]dto.ReceptionWithProducts),
+	}
+}
+
+func (l *receptionLoader) prime(pvzIds ...uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.primed = append(l.primed, pvzIds...)
+}
+
+// load holds the lock while fetching: concurrent resolvers of the same page
+// wait for the first one and then read its result.
+func (l *receptionLoader) load(ctx context.Context, pvzId uuid.UUID, filter receptionFilter) ([]dto.ReceptionWithProducts, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	batch, ok := l.batches[filter]
+	if !ok {
+		batch = make(map[uuid.UUID][]dto.ReceptionWithProducts)
+		l.batches[filter] = batch
+	}
+	if receptions, ok := batch[pvzId]; ok {
+		return receptions, nil
+	}
+
+	pvzIds := []uuid.UUID{pvzId}
+	for _, id := range l.primed {
+		if _, ok := batch[id]; !ok && id != pvzId {
+			pvzIds = append(pvzIds, id)
+		}
+	}
+	loaded, err := l.srv.GetPvzReceptions(ctx, pvzIds, filter.request())
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range pvzIds {
+		batch[id] = loaded[id]
+	}
+	return batch[pvzId], nil
+}
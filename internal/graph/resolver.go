@@ -0,0 +1,261 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/senorUVE/pvz_service/internal/controller"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/models"
+)
+
+type resolver struct {
+	srv PvzService
+}
+
+func parseID(field string, id graphql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, &controller.FieldError{Field: field, Err: controller.ErrInvalidUUID}
+	}
+	return parsed, nil
+}
+
+func (r *resolver) Me(ctx context.Context) (*userResolver, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &userResolver{user: user}, nil
+}
+
+type pvzFilterInput struct {
+	Cities           *[]string
+	RegisteredFrom   *graphql.Time
+	RegisteredTo     *graphql.Time
+	StartDate        *graphql.Time
+	EndDate          *graphql.Time
+	ReceptionStatus  *string
+	ProductType      *string
+	HasOpenReception *bool
+}
+
+type pvzListArgs struct {
+	Filter *pvzFilterInput
+	Page   *int32
+	Limit  *int32
+	Cursor *string
+	Sort   *string
+	Order  *string
+}
+
+func (args pvzListArgs) request() *dto.GetPvzRequest {
+	request := &dto.GetPvzRequest{Include: dto.IncludeNone}
+	if args.Page != nil {
+		request.Page = int(*args.Page)
+	}
+	if args.Limit != nil {
+		request.Limit = int(*args.Limit)
+	}
+	if args.Cursor != nil {
+		request.Cursor = *args.Cursor
+	}
+	if args.Sort != nil {
+		request.Sort = *args.Sort
+	}
+	if args.Order != nil {
+		request.Order = *args.Order
+	}
+
+	filter := args.Filter
+	if filter == nil {
+		return request
+	}
+	if filter.Cities != nil {
+		request.Cities = *filter.Cities
+	}
+	if filter.RegisteredFrom != nil {
+		request.RegisteredFrom = filter.RegisteredFrom.Time
+	}
+	if filter.RegisteredTo != nil {
+		request.RegisteredTo = filter.RegisteredTo.Time
+	}
+	if filter.StartDate != nil {
+		request.StartDate = filter.StartDate.Time
+	}
+	if filter.EndDate != nil {
+		request.EndDate = filter.EndDate.Time
+	}
+	if filter.ReceptionStatus != nil {
+		request.ReceptionStatus = *filter.ReceptionStatus
+	}
+	if filter.ProductType != nil {
+		request.ProductType = *filter.ProductType
+	}
+	request.HasOpenReception = filter.HasOpenReception
+	return request
+}
+
+// PvzList loads PVZ without receptions; nested receptions of the whole page
+// are fetched by the request loader in a single call.
+func (r *resolver) PvzList(ctx context.Context, args pvzListArgs) (*pvzConnectionResolver, error) {
+	if _, err := requireRole(ctx, models.RoleModerator, models.RoleEmployee); err != nil {
+		return nil, err
+	}
+
+	request := args.request()
+	response, err := r.srv.GetPvz(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := receptionFilter{
+		status:      request.ReceptionStatus,
+		productType: request.ProductType,
+		startDate:   request.StartDate,
+		endDate:     request.EndDate,
+	}
+	connection := &pvzConnectionResolver{response: response}
+	pvzIds := make([]uuid.UUID, 0, len(response.Items))
+	for _, item := range response.Items {
+		summary := item.Summary
+		connection.items = append(connection.items, &pvzResolver{pvz: item.PVZ, summary: &summary, filter: filter})
+		pvzIds = append(pvzIds, item.PVZ.Id)
+	}
+	loaderFrom(ctx).prime(pvzIds...)
+	return connection, nil
+}
+
+func (r *resolver) Product(ctx context.Context, args struct{ ID graphql.ID }) (*productDetailsResolver, error) {
+	if _, err := requireRole(ctx, models.RoleModerator, models.RoleEmployee); err != nil {
+		return nil, err
+	}
+	productId, err := parseID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := r.srv.GetProduct(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	return &productDetailsResolver{details: details}, nil
+}
+
+func (r *resolver) CreatePVZ(ctx context.Context, args struct{ City string }) (*pvzResolver, error) {
+	if _, err := requireRole(ctx, models.RoleModerator); err != nil {
+		return nil, err
+	}
+
+	created, err := r.srv.CreatePVZ(ctx, &dto.PvzCreateRequest{City: args.City})
+	if err != nil {
+		return nil, err
+	}
+	return &pvzResolver{
+		pvz:     dto.PVZResponse{Id: created.Id, RegistrationDate: created.RegistrationDate, City: created.City},
+		summary: &dto.PVZSummary{},
+	}, nil
+}
+
+type pvzIdArgs struct {
+	PvzID graphql.ID
+}
+
+func (r *resolver) CreateReception(ctx context.Context, args pvzIdArgs) (*receptionResolver, error) {
+	if _, err := requireRole(ctx, models.RoleEmployee); err != nil {
+		return nil, err
+	}
+	pvzId, err := parseID("pvzId", args.PvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.srv.CreateReception(ctx, &dto.CreateReceptionRequest{PvzId: pvzId})
+	if err != nil {
+		return nil, err
+	}
+	return &receptionResolver{
+		reception: dto.ReceptionResponse(*created),
+		products:  []dto.ProductResponse{},
+		loaded:    true,
+	}, nil
+}
+
+func (r *resolver) CloseLastReception(ctx context.Context, args pvzIdArgs) (*receptionResolver, error) {
+	if _, err := requireRole(ctx, models.RoleEmployee); err != nil {
+		return nil, err
+	}
+	pvzId, err := parseID("pvzId", args.PvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	closed, err := r.srv.CloseReception(ctx, pvzId)
+	if err != nil {
+		return nil, err
+	}
+	return &receptionResolver{reception: dto.ReceptionResponse{
+		Id:       closed.Id,
+		DateTime: closed.DateTime,
+		PvzId:    closed.PvzId,
+		Status:   closed.Status,
+	}}, nil
+}
+
+func (r *resolver) AddProduct(ctx context.Context, args struct {
+	PvzID graphql.ID
+	Type  string
+}) (*productResolver, error) {
+	if _, err := requireRole(ctx, models.RoleEmployee); err != nil {
+		return nil, err
+	}
+	pvzId, err := parseID("pvzId", args.PvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.srv.AddProduct(ctx, &dto.AddProductRequest{PvzId: pvzId, Type: args.Type})
+	if err != nil {
+		return nil, err
+	}
+	return &productResolver{product: dto.ProductResponse{
+		Id:          created.Id,
+		DateTime:    created.DateTime,
+		Type:        created.Type,
+		ReceptionId: created.ReceptionId,
+	}}, nil
+}
+
+func (r *resolver) DeleteLastProduct(ctx context.Context, args pvzIdArgs) (bool, error) {
+	user, err := requireRole(ctx, models.RoleEmployee)
+	if err != nil {
+		return false, err
+	}
+	pvzId, err := parseID("pvzId", args.PvzID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.srv.DeleteLastProduct(ctx, pvzId, user.Id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *resolver) RestoreLastProduct(ctx context.Context, args pvzIdArgs) (*productResolver, error) {
+	user, err := requireRole(ctx, models.RoleEmployee)
+	if err != nil {
+		return nil, err
+	}
+	pvzId, err := parseID("pvzId", args.PvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := r.srv.RestoreLastProduct(ctx, pvzId, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &productResolver{product: *restored}, nil
+}
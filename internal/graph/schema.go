@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/models"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+const (
+	// maxQueryDepth leaves room for the introspection query of GraphQL
+	// clients, which nests deeper than any query over the data does.
+	maxQueryDepth  = 15
+	maxQueryLength = 8 << 10
+)
+
+type PvzService interface {
+	CreatePVZ(ctx context.Context, request *dto.PvzCreateRequest) (*dto.PvzCreateResponse, error)
+	GetPvz(ctx context.Context, request *dto.GetPvzRequest) (*dto.GetPvzResponse, error)
+	GetPvzReceptions(ctx context.Context, pvzIds []uuid.UUID, request *dto.GetPvzRequest) (map[uuid.UUID][]dto.ReceptionWithProducts, error)
+	CloseReception(ctx context.Context, pvzID uuid.UUID) (*dto.CloseLastReceptionResponse, error)
+	DeleteLastProduct(ctx context.Context, pvzId, userId uuid.UUID) error
+	RestoreLastProduct(ctx context.Context, pvzId, userId uuid.UUID) (*dto.ProductResponse, error)
+	CreateReception(ctx context.Context, request *dto.CreateReceptionRequest) (*dto.CreateReceptionResponse, error)
+	AddProduct(ctx context.Context, request *dto.AddProductRequest) (*dto.AddProductResponse, error)
+	GetProduct(ctx context.Context, productId uuid.UUID) (*dto.ProductDetailsResponse, error)
+}
+
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type Schema struct {
+	schema *graphql.Schema
+	srv    PvzService
+}
+
+func NewSchema(srv PvzService) *Schema {
+	return &Schema{
+		schema: graphql.MustParseSchema(schemaSDL, &resolver{srv: srv}, graphql.MaxDepth(maxQueryDepth)),
+		srv:    srv,
+	}
+}
+
+// Exec runs a query on behalf of user. Every call gets its own loaders, so
+// batched data never leaks between requests. The length limit is checked
+// here because this version of graphql-go has no schema option for it.
+func (s *Schema) Exec(ctx context.Context, user *models.User, request Request) *graphql.Response {
+	if len(request.Query) > maxQueryLength {
+		return &graphql.Response{Errors: []*gqlerrors.QueryError{
+			gqlerrors.Errorf("query is longer than %d bytes", maxQueryLength),
+		}}
+	}
+	ctx = context.WithValue(ctx, userKey{}, user)
+	ctx = context.WithValue(ctx, loaderKey{}, newReceptionLoader(s.srv))
+	return s.schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+}
+
+type userKey struct{}
+
+type loaderKey struct{}
+
+func currentUser(ctx context.Context) (*models.User, error) {
+	user, ok := ctx.Value(userKey{}).(*models.User)
+	if !ok || user == nil {
+		return nil, ErrUnauthenticated
+	}
+	return user, nil
+}
+
+func requireRole(ctx context.Context, roles ...models.Role) (*models.User, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if user.Role == role {
+			return user, nil
+		}
+	}
+	return nil, ErrForbidden
+}
+
+func loaderFrom(ctx context.Context) *receptionLoader {
+	return ctx.Value(loaderKey{}).(*receptionLoader)
+}
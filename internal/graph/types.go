@@ -0,0 +1,315 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/models"
+)
+
+func toID(id uuid.UUID) graphql.ID {
+	return graphql.ID(id.String())
+}
+
+type userResolver struct {
+	user *models.User
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return toID(r.user.Id)
+}
+
+func (r *userResolver) Email() string {
+	return r.user.Email
+}
+
+func (r *userResolver) Role() string {
+	return string(r.user.Role)
+}
+
+type pvzConnectionResolver struct {
+	response *dto.GetPvzResponse
+	items    []*pvzResolver
+}
+
+func (r *pvzConnectionResolver) Items() []*pvzResolver {
+	return r.items
+}
+
+func (r *pvzConnectionResolver) Total() int32 {
+	return int32(r.response.Total)
+}
+
+func (r *pvzConnectionResolver) Page() *int32 {
+	if r.response.Page == 0 {
+		return nil
+	}
+	page := int32(r.response.Page)
+	return &page
+}
+
+func (r *pvzConnectionResolver) Limit() int32 {
+	return int32(r.response.Limit)
+}
+
+func (r *pvzConnectionResolver) NextCursor() *string {
+	return optionalString(r.response.NextCursor)
+}
+
+func (r *pvzConnectionResolver) PrevCursor() *string {
+	return optionalString(r.response.PrevCursor)
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+type pvzResolver struct {
+	pvz dto.PVZResponse
+	// summary is known for PVZ that come from pvzList; elsewhere it is
+	// computed from the loaded receptions.
+	summary *dto.PVZSummary
+	filter  receptionFilter
+}
+
+func (r *pvzResolver) ID() graphql.ID {
+	return toID(r.pvz.Id)
+}
+
+func (r *pvzResolver) RegistrationDate() graphql.Time {
+	return graphql.Time{Time: r.pvz.RegistrationDate}
+}
+
+func (r *pvzResolver) City() string {
+	return r.pvz.City
+}
+
+func (r *pvzResolver) Summary(ctx context.Context) (*summaryResolver, error) {
+	if r.summary != nil {
+		return &summaryResolver{summary: *r.summary}, nil
+	}
+
+	receptions, err := loaderFrom(ctx).load(ctx, r.pvz.Id, r.filter)
+	if err != nil {
+		return nil, err
+	}
+	var summary dto.PVZSummary
+	for _, reception := range receptions {
+		summary.Receptions++
+		if reception.Reception.Status == string(models.StatusInProgress) {
+			summary.OpenReceptions++
+		}
+		for _, product := range reception.Products {
+			if product.DeletedAt == nil {
+				summary.Products++
+			}
+		}
+	}
+	return &summaryResolver{summary: summary}, nil
+}
+
+type receptionsArgs struct {
+	Status         *string
+	ProductType    *string
+	StartDate      *graphql.Time
+	EndDate        *graphql.Time
+	IncludeDeleted *bool
+}
+
+func (r *pvzResolver) Receptions(ctx context.Context, args receptionsArgs) ([]*receptionResolver, error) {
+	filter := r.filter
+	if args.Status != nil {
+		filter.status = *args.Status
+	}
+	if args.ProductType != nil {
+		filter.productType = *args.ProductType
+	}
+	if args.StartDate != nil {
+		filter.startDate = args.StartDate.Time
+	}
+	if args.EndDate != nil {
+		filter.endDate = args.EndDate.Time
+	}
+	if args.IncludeDeleted != nil && *args.IncludeDeleted {
+		if _, err := requireRole(ctx, models.RoleModerator); err != nil {
+			return nil, ErrIncludeDeletedForbidden
+		}
+		filter.includeDeleted = true
+	}
+
+	receptions, err := loaderFrom(ctx).load(ctx, r.pvz.Id, filter)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*receptionResolver, 0, len(receptions))
+	for _, reception := range receptions {
+		resolvers = append(resolvers, &receptionResolver{
+			reception: reception.Reception,
+			products:  reception.Products,
+			loaded:    true,
+		})
+	}
+	return resolvers, nil
+}
+
+type summaryResolver struct {
+	summary dto.PVZSummary
+}
+
+func (r *summaryResolver) Receptions() int32 {
+	return int32(r.summary.Receptions)
+}
+
+func (r *summaryResolver) OpenReceptions() int32 {
+	return int32(r.summary.OpenReceptions)
+}
+
+func (r *summaryResolver) Products() int32 {
+	return int32(r.summary.Products)
+}
+
+type receptionResolver struct {
+	reception dto.ReceptionResponse
+	products  []dto.ProductResponse
+	loaded    bool
+}
+
+func (r *receptionResolver) ID() graphql.ID {
+	return toID(r.reception.Id)
+}
+
+func (r *receptionResolver) DateTime() graphql.Time {
+	return graphql.Time{Time: r.reception.DateTime}
+}
+
+func (r *receptionResolver) PvzID() graphql.ID {
+	return toID(r.reception.PvzId)
+}
+
+func (r *receptionResolver) Status() string {
+	return r.reception.Status
+}
+
+// Products of receptions that did not come from PVZ.receptions are looked up
+// among the receptions of their PVZ, which shares the request loader.
+func (r *receptionResolver) Products(ctx context.Context) ([]*productResolver, error) {
+	products := r.products
+	if !r.loaded {
+		receptions, err := loaderFrom(ctx).load(ctx, r.reception.PvzId, receptionFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for _, reception := range receptions {
+			if reception.Reception.Id == r.reception.Id {
+				products = reception.Products
+				break
+			}
+		}
+	}
+
+	resolvers := make([]*productResolver, 0, len(products))
+	for _, product := range products {
+		resolvers = append(resolvers, &productResolver{product: product})
+	}
+	return resolvers, nil
+}
+
+type productResolver struct {
+	product dto.ProductResponse
+}
+
+func (r *productResolver) ID() graphql.ID {
+	return toID(r.product.Id)
+}
+
+func (r *productResolver) DateTime() graphql.Time {
+	return graphql.Time{Time: r.product.DateTime}
+}
+
+func (r *productResolver) Type() string {
+	return r.product.Type
+}
+
+func (r *productResolver) ReceptionID() graphql.ID {
+	return toID(r.product.ReceptionId)
+}
+
+func (r *productResolver) DeletedAt() *graphql.Time {
+	if r.product.DeletedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.product.DeletedAt}
+}
+
+func (r *productResolver) DeletedBy() *graphql.ID {
+	if r.product.DeletedBy == nil {
+		return nil
+	}
+	id := toID(*r.product.DeletedBy)
+	return &id
+}
+
+type productEventResolver struct {
+	event dto.ProductEventResponse
+}
+
+func (r *productEventResolver) Type() string {
+	return r.event.Type
+}
+
+func (r *productEventResolver) DateTime() graphql.Time {
+	return graphql.Time{Time: r.event.DateTime}
+}
+
+func (r *productEventResolver) ReceptionID() graphql.ID {
+	return toID(r.event.ReceptionId)
+}
+
+func (r *productEventResolver) UserID() *graphql.ID {
+	if r.event.UserId == nil {
+		return nil
+	}
+	id := toID(*r.event.UserId)
+	return &id
+}
+
+func (r *productEventResolver) PvzID() *graphql.ID {
+	if r.event.PvzId == nil {
+		return nil
+	}
+	id := toID(*r.event.PvzId)
+	return &id
+}
+
+type productDetailsResolver struct {
+	details *dto.ProductDetailsResponse
+}
+
+func (r *productDetailsResolver) Product() *productResolver {
+	return &productResolver{product: r.details.Product}
+}
+
+func (r *productDetailsResolver) Reception() *receptionResolver {
+	return &receptionResolver{reception: r.details.Reception}
+}
+
+func (r *productDetailsResolver) Pvz() *pvzResolver {
+	return &pvzResolver{pvz: r.details.PVZ}
+}
+
+func (r *productDetailsResolver) Status() string {
+	return r.details.Status
+}
+
+func (r *productDetailsResolver) Timeline() []*productEventResolver {
+	resolvers := make([]*productEventResolver, 0, len(r.details.Timeline))
+	for _, event := range r.details.Timeline {
+		resolvers = append(resolvers, &productEventResolver{event: event})
+	}
+	return resolvers
+}
@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/auth"
+	pbv1 "github.com/senorUVE/pvz_service/internal/generated"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const authorizationKey = "authorization"
+
+var pvzServicePrefix = "/" + pbv1.PVZService_ServiceDesc.ServiceName + "/"
+
+var (
+	anyone   = []models.Role{}
+	staff    = []models.Role{models.RoleModerator, models.RoleEmployee}
+	moderate = []models.Role{models.RoleModerator}
+	employee = []models.Role{models.RoleEmployee}
+)
+
+// methodRoles lists the roles allowed to call each PVZService method, an
+// empty list makes the method public. A PVZService method missing here is
+// refused, methods of other services (reflection, health) are not checked.
+var methodRoles = map[string][]models.Role{
+	pbv1.PVZService_DummyLogin_FullMethodName:         anyone,
+	pbv1.PVZService_Login_FullMethodName:              anyone,
+	pbv1.PVZService_CreatePVZ_FullMethodName:          moderate,
+	pbv1.PVZService_GetPVZ_FullMethodName:             staff,
+	pbv1.PVZService_GetPVZList_FullMethodName:         staff,
+	pbv1.PVZService_CreateReception_FullMethodName:    employee,
+	pbv1.PVZService_CloseLastReception_FullMethodName: employee,
+	pbv1.PVZService_AddProduct_FullMethodName:         employee,
+	pbv1.PVZService_DeleteLastProduct_FullMethodName:  employee,
+	pbv1.PVZService_GetProduct_FullMethodName:         staff,
+	pbv1.PVZService_GetIntakeReport_FullMethodName:    moderate,
+	pbv1.PVZService_WatchReceptions_FullMethodName:    staff,
+}
+
+type userKey struct{}
+
+func UnaryAuthInterceptor(a auth.AuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, a, info.FullMethod)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamAuthInterceptor(a auth.AuthService) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), a, info.FullMethod)
+		if err != nil {
+			return toStatus(err)
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authStream passes the authenticated user to stream handlers.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize checks the bearer token of the call against methodRoles and
+// returns ctx carrying the caller.
+func authorize(ctx context.Context, a auth.AuthService, method string) (context.Context, error) {
+	const op = "internal.grpc.authorize"
+	if !strings.HasPrefix(method, pvzServicePrefix) {
+		return ctx, nil
+	}
+	roles, ok := methodRoles[method]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", method, ErrUnknownMethod)
+	}
+	if len(roles) == 0 {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationKey)
+	if len(values) == 0 || values[0] == "" {
+		return nil, ErrEmptyToken
+	}
+	headerSplit := strings.Split(values[0], " ")
+	if len(headerSplit) != 2 {
+		return nil, ErrInvalidAuthHeader
+	}
+
+	user, err := a.ParseToken(headerSplit[1])
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"event": op}).Debug(err)
+
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	for _, role := range roles {
+		if user.Role == role {
+			return context.WithValue(ctx, userKey{}, user), nil
+		}
+	}
+	return nil, ErrForbidden
+}
+
+func currentUserId(ctx context.Context) uuid.UUID {
+	if user, ok := ctx.Value(userKey{}).(*models.User); ok {
+		return user.Id
+	}
+	return uuid.Nil
+}
+
+func hasRole(ctx context.Context, roles ...models.Role) bool {
+	user, ok := ctx.Value(userKey{}).(*models.User)
+	if !ok {
+		return false
+	}
+	for _, role := range roles {
+		if user.Role == role {
+			return true
+		}
+	}
+	return false
+}
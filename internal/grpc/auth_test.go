@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/senorUVE/pvz_service/internal/auth"
+	pbv1 "github.com/senorUVE/pvz_service/internal/generated"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	srv, _, mockAuth := newTestService(t)
+	mockAuth.EXPECT().ParseToken("expired-token").Return(nil, auth.ErrTokenExpired).AnyTimes()
+	client := newTestClient(t, srv, mockAuth)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		call func(ctx context.Context) error
+		code codes.Code
+	}{
+		{"no token", ctx, func(ctx context.Context) error {
+			_, err := client.GetPVZList(ctx, &pbv1.GetPVZListRequest{})
+			return err
+		}, codes.Unauthenticated},
+		{"malformed metadata", metadata.AppendToOutgoingContext(ctx, authorizationKey, "employee-token"), func(ctx context.Context) error {
+			_, err := client.GetPVZList(ctx, &pbv1.GetPVZListRequest{})
+			return err
+		}, codes.Unauthenticated},
+		{"expired token", withToken(ctx, "expired-token"), func(ctx context.Context) error {
+			_, err := client.GetPVZList(ctx, &pbv1.GetPVZListRequest{})
+			return err
+		}, codes.Unauthenticated},
+		{"employee creates pvz", withToken(ctx, "employee-token"), func(ctx context.Context) error {
+			_, err := client.CreatePVZ(ctx, &pbv1.CreatePVZRequest{City: "Москва"})
+			return err
+		}, codes.PermissionDenied},
+		{"employee reads intake report", withToken(ctx, "employee-token"), func(ctx context.Context) error {
+			_, err := client.GetIntakeReport(ctx, &pbv1.GetIntakeReportRequest{})
+			return err
+		}, codes.PermissionDenied},
+		{"moderator opens reception", withToken(ctx, "moderator-token"), func(ctx context.Context) error {
+			_, err := client.CreateReception(ctx, &pbv1.CreateReceptionRequest{})
+			return err
+		}, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, status.Code(tt.call(tt.ctx)))
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	_, _, mockAuth := newTestService(t)
+
+	_, err := authorize(context.Background(), mockAuth, pvzServicePrefix+"DropAll")
+	assert.ErrorIs(t, err, ErrUnknownMethod)
+
+	ctx, err := authorize(context.Background(), mockAuth, "/grpc.health.v1.Health/Check")
+	require.NoError(t, err)
+	assert.NotNil(t, ctx)
+}
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	_, _, mockAuth := newTestService(t)
+	interceptor := StreamAuthInterceptor(mockAuth)
+	info := &grpc.StreamServerInfo{FullMethod: pbv1.PVZService_GetPVZList_FullMethodName, IsServerStream: true}
+	incoming := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationKey, "Bearer "+token))
+	}
+
+	var caller *models.User
+	err := interceptor(nil, &testStream{ctx: incoming("moderator-token")}, info, func(_ any, ss grpc.ServerStream) error {
+		caller, _ = ss.Context().Value(userKey{}).(*models.User)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testModerator, caller)
+
+	errHandler := errors.New("handler must not run")
+	err = interceptor(nil, &testStream{ctx: context.Background()}, info, func(any, grpc.ServerStream) error {
+		return errHandler
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
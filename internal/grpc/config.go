@@ -0,0 +1,50 @@
+package grpc
+
+import "time"
+
+const (
+	defaultPort            = "3000"
+	defaultHealthInterval  = 10 * time.Second
+	defaultShutdownTimeout = 10 * time.Second
+)
+
+type Config struct {
+	Port string `mapstructure:"port"`
+	// Reflection lets tools like grpcurl discover the API without the proto.
+	Reflection bool `mapstructure:"reflection"`
+	// MaxMessageSize limits received and sent messages in bytes, zero keeps
+	// the gRPC defaults.
+	MaxMessageSize int             `mapstructure:"max_message_size"`
+	Keepalive      KeepaliveConfig `mapstructure:"keepalive"`
+	// HealthInterval is how often the database is pinged for health checks.
+	HealthInterval time.Duration `mapstructure:"health_interval"`
+	// ShutdownTimeout is how long running calls may take to finish on
+	// shutdown before they are cut off.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+}
+
+// KeepaliveConfig zero values keep the gRPC defaults.
+type KeepaliveConfig struct {
+	// Time is how long a connection may stay idle before the server pings
+	// it, Timeout how long it then waits for the answer.
+	Time    time.Duration `mapstructure:"time"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MinTime is the shortest interval clients may ping at; clients that
+	// ping more often are disconnected.
+	MinTime             time.Duration `mapstructure:"min_time"`
+	PermitWithoutStream bool          `mapstructure:"permit_without_stream"`
+}
+
+// WithDefaults fills in unset fields.
+func (c Config) WithDefaults() Config {
+	if c.Port == "" {
+		c.Port = defaultPort
+	}
+	if c.HealthInterval <= 0 {
+		c.HealthInterval = defaultHealthInterval
+	}
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = defaultShutdownTimeout
+	}
+	return c
+}
@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/senorUVE/pvz_service/internal/controller"
+	"github.com/senorUVE/pvz_service/internal/label"
+	"github.com/senorUVE/pvz_service/internal/pagination"
+	"github.com/senorUVE/pvz_service/internal/repository"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrEmptyToken = errors.New("empty token")
+
+var ErrInvalidAuthHeader = errors.New("invalid authorization metadata")
+
+var ErrInvalidToken = errors.New("invalid token")
+
+var ErrForbidden = errors.New("insufficient permissions")
+
+var ErrUnknownMethod = errors.New("method has no access rule")
+
+var ErrWatcherBehind = errors.New("watcher fell behind, resume with the last resume_token")
+
+var ErrIncludeDeletedForbidden = errors.New("include_deleted is available to moderators only")
+
+var ErrShuttingDown = errors.New("server is shutting down, resume with the last resume_token")
+
+var ErrInvalidPvzId = errors.New("invalid PVZ ID")
+
+var ErrInvalidProductLookup = errors.New("invalid product id or barcode")
+
+var ErrMissingProductLookup = errors.New("id or barcode is required")
+
+// errorCodes mirrors the problem kinds of the HTTP API. It is matched in
+// order with errors.Is; a controller.FieldError not listed here is still an
+// invalid argument.
+var errorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{ErrInvalidPvzId, codes.InvalidArgument},
+	{ErrInvalidProductLookup, codes.InvalidArgument},
+	{ErrMissingProductLookup, codes.InvalidArgument},
+	{pagination.ErrInvalidCursor, codes.InvalidArgument},
+	{controller.ErrCursorSortMismatch, codes.InvalidArgument},
+	{controller.ErrInvalidInclude, codes.InvalidArgument},
+	{label.ErrInvalidFormat, codes.InvalidArgument},
+	{controller.ErrInvalidPage, codes.InvalidArgument},
+	{controller.ErrInvalidLimit, codes.InvalidArgument},
+	{controller.ErrInvalidDateRange, codes.InvalidArgument},
+	{controller.ErrFutureDate, codes.InvalidArgument},
+	{controller.ErrInvalidCity, codes.InvalidArgument},
+	{controller.ErrInvalidStatus, codes.InvalidArgument},
+	{controller.ErrInvalidProductType, codes.InvalidArgument},
+	{controller.ErrInvalidRole, codes.InvalidArgument},
+	{controller.ErrInvalidEmail, codes.InvalidArgument},
+	{controller.ErrShortPassword, codes.InvalidArgument},
+	{controller.ErrLongExternalCode, codes.InvalidArgument},
+	{controller.ErrInvalidGroupBy, codes.InvalidArgument},
+	{controller.ErrManyIntakePeriods, codes.InvalidArgument},
+	{controller.ErrInvalidUUID, codes.InvalidArgument},
+	{controller.ErrInvalidEventType, codes.InvalidArgument},
+	{controller.ErrInvalidEventId, codes.InvalidArgument},
+
+	{ErrEmptyToken, codes.Unauthenticated},
+	{ErrInvalidAuthHeader, codes.Unauthenticated},
+	{ErrInvalidToken, codes.Unauthenticated},
+	{controller.ErrInvalidPasswd, codes.Unauthenticated},
+
+	{ErrForbidden, codes.PermissionDenied},
+	{ErrUnknownMethod, codes.PermissionDenied},
+	{ErrIncludeDeletedForbidden, codes.PermissionDenied},
+
+	{repository.ErrUserNotFound, codes.NotFound},
+	{repository.ErrPVZNotFound, codes.NotFound},
+	{repository.ErrReceptionNotFound, codes.NotFound},
+	{repository.ErrProductNotFound, codes.NotFound},
+
+	{repository.ErrUserExists, codes.AlreadyExists},
+	{repository.ErrPvzExternalCodeExists, codes.AlreadyExists},
+
+	{repository.ErrNoActiveReception, codes.FailedPrecondition},
+	{repository.ErrReceptionEmpty, codes.FailedPrecondition},
+
+	{ErrWatcherBehind, codes.Unavailable},
+	{ErrShuttingDown, codes.Unavailable},
+}
+
+// toStatus turns an error of the service into a gRPC status. Unknown errors
+// are logged and hidden behind codes.Internal.
+func toStatus(err error) error {
+	const op = "internal.grpc.toStatus"
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			return status.Error(e.code, err.Error())
+		}
+	}
+	var fieldErr *controller.FieldError
+	if errors.As(err, &fieldErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	logrus.WithFields(logrus.Fields{"event": op}).Error(err)
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	pbv1 "github.com/senorUVE/pvz_service/internal/generated"
+	"github.com/sirupsen/logrus"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// watchHealth pings the database every HealthInterval until Close; the
+// service is only as healthy as its database.
+func (s *GrpcServer) watchHealth() {
+	ticker := time.NewTicker(s.cfg.HealthInterval)
+	defer ticker.Stop()
+	for {
+		s.checkHealth()
+		select {
+		case <-s.api.shutdown:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *GrpcServer) checkHealth() {
+	const op = "internal.grpc.checkHealth"
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.HealthInterval)
+	defer cancel()
+
+	servingStatus := healthpb.HealthCheckResponse_SERVING
+	if err := s.srv.Ping(ctx); err != nil {
+		logrus.WithFields(logrus.Fields{"event": op}).Warn(err)
+		servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.setServingStatus(servingStatus)
+}
+
+// setServingStatus updates both the overall status and the one of
+// PVZService, which is all the server provides.
+func (s *GrpcServer) setServingStatus(servingStatus healthpb.HealthCheckResponse_ServingStatus) {
+	s.health.SetServingStatus("", servingStatus)
+	s.health.SetServingStatus(pbv1.PVZService_ServiceDesc.ServiceName, servingStatus)
+}
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/senorUVE/pvz_service/internal/controller"
+	pbv1 "github.com/senorUVE/pvz_service/internal/generated"
+	"github.com/senorUVE/pvz_service/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcServer_Health(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockRepository(ctrl)
+	var dbDown atomic.Bool
+	dbDown.Store(true)
+	mockRepo.EXPECT().Ping(gomock.Any()).DoAndReturn(func(context.Context) error {
+		if dbDown.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}).AnyTimes()
+	srv := controller.NewPvzService(mockRepo, mocks.NewMockAuthService(ctrl), controller.ServiceConfig{})
+	client := healthpb.NewHealthClient(newTestConn(t, NewGrpcServer(srv, nil, Config{HealthInterval: 10 * time.Millisecond})))
+
+	servingStatus := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return resp.GetStatus()
+	}
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(""))
+
+	dbDown.Store(false)
+	require.Eventually(t, func() bool {
+		return servingStatus(pbv1.PVZService_ServiceDesc.ServiceName) == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, servingStatus(""))
+
+	dbDown.Store(true)
+	require.Eventually(t, func() bool {
+		return servingStatus("") == healthpb.HealthCheckResponse_NOT_SERVING
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestGrpcServer_Close(t *testing.T) {
+	srv, _, mockAuth := newTestService(t)
+	gs := NewGrpcServer(srv, mockAuth, Config{})
+	client := pbv1.NewPVZServiceClient(newTestConn(t, gs))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A token ahead of the fresh broker makes the server answer with a
+	// reset right away, so the watch is known to be running.
+	stream, err := client.WatchReceptions(withToken(ctx, "moderator-token"), &pbv1.WatchReceptionsRequest{ResumeToken: "100"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// Open watches end by themselves, so the stop is graceful.
+	require.NoError(t, gs.Close(ctx))
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
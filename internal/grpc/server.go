@@ -3,11 +3,20 @@ package grpc
 import (
 	"context"
 	"net"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/auth"
+	"github.com/senorUVE/pvz_service/internal/controller"
+	"github.com/senorUVE/pvz_service/internal/dto"
 	pbv1 "github.com/senorUVE/pvz_service/internal/generated"
-	"github.com/senorUVE/pvz_service/internal/repository"
+	"github.com/senorUVE/pvz_service/internal/label"
+	"github.com/senorUVE/pvz_service/internal/models"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -16,39 +25,414 @@ const maxLimit = 30
 
 type Server struct {
 	pbv1.UnimplementedPVZServiceServer
-	repo *repository.Repository
+	srv *controller.PvzService
+	// shutdown is closed when the server stops, to end long-lived streams.
+	shutdown chan struct{}
 }
 
-func NewServer(repo *repository.Repository) *Server {
-	return &Server{repo: repo}
+func NewServer(srv *controller.PvzService) *Server {
+	return &Server{srv: srv, shutdown: make(chan struct{})}
 }
 
-func (s *Server) GetPVZList(ctx context.Context, _ *pbv1.GetPVZListRequest) (*pbv1.GetPVZListResponse, error) {
-	pvzList, err := s.repo.GetPvz(ctx, time.Time{}, time.Time{}, 1, maxLimit)
+func (s *Server) DummyLogin(ctx context.Context, req *pbv1.DummyLoginRequest) (*pbv1.LoginResponse, error) {
+	token, err := s.srv.DummyLogin(ctx, req.GetRole())
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
+	}
+	return &pbv1.LoginResponse{Token: token}, nil
+}
+
+func (s *Server) Login(ctx context.Context, req *pbv1.LoginRequest) (*pbv1.LoginResponse, error) {
+	resp, err := s.srv.AuthUser(ctx, &dto.AuthRequest{Email: req.GetEmail(), Password: req.GetPassword()})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pbv1.LoginResponse{Token: resp.Token}, nil
+}
+
+func (s *Server) CreatePVZ(ctx context.Context, req *pbv1.CreatePVZRequest) (*pbv1.CreatePVZResponse, error) {
+	created, err := s.srv.CreatePVZ(ctx, &dto.PvzCreateRequest{City: req.GetCity(), ExternalCode: req.GetExternalCode()})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pbv1.CreatePVZResponse{Pvz: &pbv1.PVZ{
+		Id:               created.Id.String(),
+		RegistrationDate: timestamppb.New(created.RegistrationDate),
+		City:             created.City,
+		ExternalCode:     created.ExternalCode,
+	}}, nil
+}
+
+func (s *Server) GetPVZ(ctx context.Context, req *pbv1.GetPVZRequest) (*pbv1.GetPVZResponse, error) {
+	pvzId, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, toStatus(ErrInvalidPvzId)
+	}
+
+	pvz, err := s.srv.GetPvzById(ctx, pvzId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pbv1.GetPVZResponse{Pvz: toPVZPb(*pvz)}, nil
+}
+
+func (s *Server) GetPVZList(ctx context.Context, req *pbv1.GetPVZListRequest) (*pbv1.GetPVZListResponse, error) {
+	if req.GetIncludeDeleted() && !hasRole(ctx, models.RoleModerator) {
+		return nil, toStatus(ErrIncludeDeletedForbidden)
+	}
+
+	filter := toPvzFilter(req)
+	pvzList, err := s.srv.GetPvz(ctx, &filter)
+	if err != nil {
+		return nil, toStatus(err)
 	}
 
 	resp := &pbv1.GetPVZListResponse{
-		Pvzs: make([]*pbv1.PVZ, 0, len(pvzList)),
+		Pvzs:          make([]*pbv1.PVZ, 0, len(pvzList.Items)),
+		NextPageToken: pvzList.NextCursor,
+		TotalSize:     int32(pvzList.Total),
+		Items:         make([]*pbv1.PVZListItem, 0, len(pvzList.Items)),
+	}
+	for _, p := range pvzList.Items {
+		resp.Pvzs = append(resp.Pvzs, toPVZPb(p.PVZ))
+		resp.Items = append(resp.Items, toPVZListItemPb(p))
+	}
+	return resp, nil
+}
+
+func (s *Server) CreateReception(ctx context.Context, req *pbv1.CreateReceptionRequest) (*pbv1.CreateReceptionResponse, error) {
+	pvzId, err := uuid.Parse(req.GetPvzId())
+	if err != nil {
+		return nil, toStatus(ErrInvalidPvzId)
+	}
+
+	created, err := s.srv.CreateReception(ctx, &dto.CreateReceptionRequest{PvzId: pvzId})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pbv1.CreateReceptionResponse{Reception: toReceptionPb(dto.ReceptionResponse(*created))}, nil
+}
+
+func (s *Server) CloseLastReception(ctx context.Context, req *pbv1.CloseLastReceptionRequest) (*pbv1.CloseLastReceptionResponse, error) {
+	pvzId, err := uuid.Parse(req.GetPvzId())
+	if err != nil {
+		return nil, toStatus(ErrInvalidPvzId)
+	}
+
+	closed, err := s.srv.CloseReception(ctx, pvzId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pbv1.CloseLastReceptionResponse{Reception: toReceptionPb(dto.ReceptionResponse{
+		Id:       closed.Id,
+		DateTime: closed.DateTime,
+		PvzId:    closed.PvzId,
+		Status:   closed.Status,
+	})}, nil
+}
+
+func (s *Server) AddProduct(ctx context.Context, req *pbv1.AddProductRequest) (*pbv1.AddProductResponse, error) {
+	pvzId, err := uuid.Parse(req.GetPvzId())
+	if err != nil {
+		return nil, toStatus(ErrInvalidPvzId)
+	}
+
+	created, err := s.srv.AddProduct(ctx, &dto.AddProductRequest{Type: req.GetType(), PvzId: pvzId})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pbv1.AddProductResponse{Product: &pbv1.Product{
+		Id:          created.Id.String(),
+		DateTime:    timestamppb.New(created.DateTime),
+		Type:        created.Type,
+		ReceptionId: created.ReceptionId.String(),
+	}}, nil
+}
+
+func (s *Server) DeleteLastProduct(ctx context.Context, req *pbv1.DeleteLastProductRequest) (*pbv1.DeleteLastProductResponse, error) {
+	pvzId, err := uuid.Parse(req.GetPvzId())
+	if err != nil {
+		return nil, toStatus(ErrInvalidPvzId)
+	}
+
+	if err := s.srv.DeleteLastProduct(ctx, pvzId, currentUserId(ctx)); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pbv1.DeleteLastProductResponse{}, nil
+}
+
+func (s *Server) GetProduct(ctx context.Context, req *pbv1.GetProductRequest) (*pbv1.GetProductResponse, error) {
+	var (
+		productId uuid.UUID
+		err       error
+	)
+	switch lookup := req.GetLookup().(type) {
+	case *pbv1.GetProductRequest_Id:
+		productId, err = uuid.Parse(lookup.Id)
+	case *pbv1.GetProductRequest_Barcode:
+		productId, err = label.ParseBarcode(lookup.Barcode)
+	default:
+		return nil, toStatus(ErrMissingProductLookup)
+	}
+	if err != nil {
+		return nil, toStatus(ErrInvalidProductLookup)
+	}
+
+	details, err := s.srv.GetProduct(ctx, productId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pbv1.GetProductResponse{
+		Product:   toProductPb(details.Product),
+		Reception: toReceptionPb(details.Reception),
+		Pvz:       toPVZPb(details.PVZ),
+		Status:    details.Status,
+		Timeline:  make([]*pbv1.ProductEvent, 0, len(details.Timeline)),
 	}
-	for _, p := range pvzList {
-		resp.Pvzs = append(resp.Pvzs, &pbv1.PVZ{
-			Id:               p.PVZ.Id.String(),
-			RegistrationDate: timestamppb.New(p.PVZ.RegistrationDate),
-			City:             p.PVZ.City,
-		})
+	for _, event := range details.Timeline {
+		pbEvent := &pbv1.ProductEvent{
+			Type:        event.Type,
+			DateTime:    timestamppb.New(event.DateTime),
+			ReceptionId: event.ReceptionId.String(),
+		}
+		if event.PvzId != nil {
+			pbEvent.PvzId = event.PvzId.String()
+		}
+		resp.Timeline = append(resp.Timeline, pbEvent)
 	}
 	return resp, nil
 }
 
-func StartGrpcServer(repo *repository.Repository) error {
-	lis, err := net.Listen("tcp", ":3000")
+func (s *Server) GetIntakeReport(ctx context.Context, req *pbv1.GetIntakeReportRequest) (*pbv1.GetIntakeReportResponse, error) {
+	request := &dto.IntakeReportRequest{
+		StartDate: toTime(req.GetStartDate()),
+		EndDate:   toTime(req.GetEndDate()),
+		Cities:    req.GetCities(),
+	}
+	for _, dimension := range req.GetGroupBy() {
+		request.GroupBy = append(request.GroupBy, intakeDimensions[dimension])
+	}
+
+	report, err := s.srv.GetIntakeReport(ctx, request)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pbv1.GetIntakeReportResponse{Rows: make([]*pbv1.IntakeReportRow, 0, len(report.Rows))}
+	for _, row := range report.Rows {
+		resp.Rows = append(resp.Rows, toIntakeReportRowPb(row))
+	}
+	return resp, nil
+}
+
+// intakeDimensions leaves INTAKE_DIMENSION_UNSPECIFIED out, so it maps to an
+// empty name that validation rejects.
+var intakeDimensions = map[pbv1.IntakeDimension]string{
+	pbv1.IntakeDimension_INTAKE_DIMENSION_CITY:  string(models.IntakeByCity),
+	pbv1.IntakeDimension_INTAKE_DIMENSION_PVZ:   string(models.IntakeByPvz),
+	pbv1.IntakeDimension_INTAKE_DIMENSION_TYPE:  string(models.IntakeByType),
+	pbv1.IntakeDimension_INTAKE_DIMENSION_DAY:   string(models.IntakeByDay),
+	pbv1.IntakeDimension_INTAKE_DIMENSION_WEEK:  string(models.IntakeByWeek),
+	pbv1.IntakeDimension_INTAKE_DIMENSION_MONTH: string(models.IntakeByMonth),
+}
+
+var pvzIncludes = map[pbv1.PVZInclude]string{
+	pbv1.PVZInclude_PVZ_INCLUDE_NONE:       dto.IncludeNone,
+	pbv1.PVZInclude_PVZ_INCLUDE_RECEPTIONS: dto.IncludeReceptions,
+	pbv1.PVZInclude_PVZ_INCLUDE_PRODUCTS:   dto.IncludeProducts,
+}
+
+func toPvzFilter(req *pbv1.GetPVZListRequest) dto.GetPvzRequest {
+	filter := dto.GetPvzRequest{
+		Page:             1,
+		Limit:            maxLimit,
+		Cursor:           req.GetPageToken(),
+		Include:          pvzIncludes[req.GetInclude()],
+		Cities:           req.GetCities(),
+		ProductType:      req.GetProductType(),
+		HasOpenReception: req.HasOpenReception,
+		StartDate:        toTime(req.GetStartDate()),
+		EndDate:          toTime(req.GetEndDate()),
+		RegisteredFrom:   toTime(req.GetRegisteredFrom()),
+		RegisteredTo:     toTime(req.GetRegisteredTo()),
+		IncludeDeleted:   req.GetIncludeDeleted(),
+	}
+	if req.GetPageSize() != 0 {
+		filter.Limit = int(req.GetPageSize())
+	}
+	if filter.Include == "" {
+		// An enum value this server does not know fails validation.
+		filter.Include = req.GetInclude().String()
+	}
+	if req.ReceptionStatus != nil {
+		filter.ReceptionStatus = string(models.StatusInProgress)
+		if req.GetReceptionStatus() == pbv1.ReceptionStatus_RECEPTION_STATUS_CLOSED {
+			filter.ReceptionStatus = string(models.StatusClose)
+		}
+	}
+	return filter
+}
+
+func toTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func toPVZPb(p dto.PVZResponse) *pbv1.PVZ {
+	return &pbv1.PVZ{
+		Id:               p.Id.String(),
+		RegistrationDate: timestamppb.New(p.RegistrationDate),
+		City:             p.City,
+	}
+}
+
+func toReceptionPb(r dto.ReceptionResponse) *pbv1.Reception {
+	receptionStatus := pbv1.ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS
+	if r.Status == string(models.StatusClose) {
+		receptionStatus = pbv1.ReceptionStatus_RECEPTION_STATUS_CLOSED
+	}
+	return &pbv1.Reception{
+		Id:       r.Id.String(),
+		DateTime: timestamppb.New(r.DateTime),
+		PvzId:    r.PvzId.String(),
+		Status:   receptionStatus,
+	}
+}
+
+func toPVZListItemPb(p *dto.PVZWithReceptions) *pbv1.PVZListItem {
+	item := &pbv1.PVZListItem{
+		Pvz: toPVZPb(p.PVZ),
+		Summary: &pbv1.PVZSummary{
+			Receptions:     int32(p.Summary.Receptions),
+			OpenReceptions: int32(p.Summary.OpenReceptions),
+			Products:       int32(p.Summary.Products),
+		},
+		Receptions: make([]*pbv1.ReceptionWithProducts, 0, len(p.Receptions)),
+	}
+	for _, r := range p.Receptions {
+		reception := &pbv1.ReceptionWithProducts{
+			Reception: toReceptionPb(r.Reception),
+			Products:  make([]*pbv1.Product, 0, len(r.Products)),
+		}
+		for _, product := range r.Products {
+			reception.Products = append(reception.Products, toProductPb(product))
+		}
+		item.Receptions = append(item.Receptions, reception)
+	}
+	return item
+}
+
+func toProductPb(p dto.ProductResponse) *pbv1.Product {
+	pb := &pbv1.Product{
+		Id:          p.Id.String(),
+		DateTime:    timestamppb.New(p.DateTime),
+		Type:        p.Type,
+		ReceptionId: p.ReceptionId.String(),
+	}
+	if p.DeletedAt != nil {
+		pb.DeletedAt = timestamppb.New(*p.DeletedAt)
+	}
+	if p.DeletedBy != nil {
+		deletedBy := p.DeletedBy.String()
+		pb.DeletedBy = &deletedBy
+	}
+	return pb
+}
+
+func toIntakeReportRowPb(row dto.IntakeReportRow) *pbv1.IntakeReportRow {
+	pb := &pbv1.IntakeReportRow{
+		City:       row.City,
+		Type:       row.ProductType,
+		Period:     row.Period,
+		Products:   row.Products,
+		Receptions: row.Receptions,
+	}
+	if row.PvzId != nil {
+		pvzId := row.PvzId.String()
+		pb.PvzId = &pvzId
+	}
+	return pb
+}
+
+// GrpcServer serves the PVZ API behind the auth interceptors, along with
+// grpc.health.v1 and, when enabled, reflection.
+type GrpcServer struct {
+	cfg    Config
+	srv    *controller.PvzService
+	api    *Server
+	server *grpc.Server
+	health *health.Server
+	close  sync.Once
+}
+
+func NewGrpcServer(srv *controller.PvzService, a auth.AuthService, cfg Config) *GrpcServer {
+	cfg = cfg.WithDefaults()
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryAuthInterceptor(a)),
+		grpc.ChainStreamInterceptor(StreamAuthInterceptor(a)),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    cfg.Keepalive.Time,
+			Timeout: cfg.Keepalive.Timeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.Keepalive.MinTime,
+			PermitWithoutStream: cfg.Keepalive.PermitWithoutStream,
+		}),
+	}
+	if cfg.MaxMessageSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.MaxMessageSize), grpc.MaxSendMsgSize(cfg.MaxMessageSize))
+	}
+
+	s := &GrpcServer{
+		cfg:    cfg,
+		srv:    srv,
+		api:    NewServer(srv),
+		server: grpc.NewServer(opts...),
+		health: health.NewServer(),
+	}
+	pbv1.RegisterPVZServiceServer(s.server, s.api)
+	healthpb.RegisterHealthServer(s.server, s.health)
+	if cfg.Reflection {
+		reflection.Register(s.server)
+	}
+	// Not serving until the first database ping succeeds.
+	s.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return s
+}
+
+func (s *GrpcServer) Start() error {
+	lis, err := net.Listen("tcp", ":"+s.cfg.Port)
 	if err != nil {
 		return err
 	}
-	grpcServer := grpc.NewServer()
-	pbv1.RegisterPVZServiceServer(grpcServer, NewServer(repo))
-	reflection.Register(grpcServer)
-	return grpcServer.Serve(lis)
+	return s.Serve(lis)
+}
+
+// Serve blocks until Close is called.
+func (s *GrpcServer) Serve(lis net.Listener) error {
+	go s.watchHealth()
+	return s.server.Serve(lis)
+}
+
+// Close reports NOT_SERVING, ends the watch streams and waits for running
+// calls to finish. Calls still running when ctx is done are cut off.
+func (s *GrpcServer) Close(ctx context.Context) error {
+	s.health.Shutdown()
+	s.close.Do(func() { close(s.api.shutdown) })
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
 }
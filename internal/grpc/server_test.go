@@ -0,0 +1,311 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/auth"
+	"github.com/senorUVE/pvz_service/internal/controller"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	pbv1 "github.com/senorUVE/pvz_service/internal/generated"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/internal/repository"
+	"github.com/senorUVE/pvz_service/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testSalt = "test-salt"
+
+var (
+	testEmployee  = &models.User{Id: uuid.New(), Role: models.RoleEmployee}
+	testModerator = &models.User{Id: uuid.New(), Role: models.RoleModerator}
+)
+
+// newTestClient serves srv over an in-memory listener for the duration of
+// the test.
+func newTestClient(t *testing.T, srv *controller.PvzService, a auth.AuthService) pbv1.PVZServiceClient {
+	t.Helper()
+
+	return pbv1.NewPVZServiceClient(newTestConn(t, NewGrpcServer(srv, a, Config{})))
+}
+
+func newTestConn(t *testing.T, gs *GrpcServer) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	go gs.Serve(lis)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		gs.Close(ctx)
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// newTestService accepts "employee-token" and "moderator-token" and has a
+// healthy database whose transactions just run their function.
+func newTestService(t *testing.T) (*controller.PvzService, *mocks.MockRepository, *mocks.MockAuthService) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().InTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	mockAuth := mocks.NewMockAuthService(ctrl)
+	mockAuth.EXPECT().ParseToken("employee-token").Return(testEmployee, nil).AnyTimes()
+	mockAuth.EXPECT().ParseToken("moderator-token").Return(testModerator, nil).AnyTimes()
+	return controller.NewPvzService(mockRepo, mockAuth, controller.ServiceConfig{Salt: testSalt}), mockRepo, mockAuth
+}
+
+// expectEvents lets published events look up the PVZ city and enqueue
+// webhooks, which these tests do not check.
+func expectEvents(mockRepo *mocks.MockRepository) {
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), gomock.Any()).
+		Return(&dto.PVZResponse{City: "Москва"}, nil).AnyTimes()
+	mockRepo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(int64(0), nil).AnyTimes()
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, authorizationKey, "Bearer "+token)
+}
+
+func TestServer_Login(t *testing.T) {
+	srv, mockRepo, mockAuth := newTestService(t)
+	client := newTestClient(t, srv, mockAuth)
+	ctx := context.Background()
+
+	dummy := &models.User{Id: uuid.New(), Role: models.RoleModerator}
+	mockRepo.EXPECT().DummyLogin(gomock.Any(), "moderator").Return(dummy, nil)
+	mockAuth.EXPECT().GenerateToken(dummy).Return("dummy-token", nil)
+
+	resp, err := client.DummyLogin(ctx, &pbv1.DummyLoginRequest{Role: "moderator"})
+	require.NoError(t, err)
+	assert.Equal(t, "dummy-token", resp.GetToken())
+
+	_, err = client.DummyLogin(ctx, &pbv1.DummyLoginRequest{Role: "admin"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("password1"+testSalt), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &models.User{Id: uuid.New(), Email: "user@mail.ru", Password: string(hash), Role: models.RoleEmployee}
+	mockRepo.EXPECT().GetUser(gomock.Any(), user.Email).Return(user, nil).Times(2)
+	mockAuth.EXPECT().GenerateToken(user).Return("user-token", nil)
+
+	resp, err = client.Login(ctx, &pbv1.LoginRequest{Email: user.Email, Password: "password1"})
+	require.NoError(t, err)
+	assert.Equal(t, "user-token", resp.GetToken())
+
+	_, err = client.Login(ctx, &pbv1.LoginRequest{Email: user.Email, Password: "password2"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.Login(ctx, &pbv1.LoginRequest{Email: "not-an-email", Password: "password1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_CreatePVZ(t *testing.T) {
+	srv, mockRepo, mockAuth := newTestService(t)
+	client := newTestClient(t, srv, mockAuth)
+	ctx := withToken(context.Background(), "moderator-token")
+
+	mockRepo.EXPECT().CreatePvz(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, pvz models.PVZ) (*dto.PvzCreateResponse, error) {
+			return &dto.PvzCreateResponse{
+				Id:               pvz.Id,
+				RegistrationDate: pvz.RegistrationDate,
+				City:             string(pvz.City),
+				ExternalCode:     pvz.ExternalCode,
+			}, nil
+		})
+
+	resp, err := client.CreatePVZ(ctx, &pbv1.CreatePVZRequest{City: "Казань", ExternalCode: "KZN-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "Казань", resp.GetPvz().GetCity())
+	assert.Equal(t, "KZN-1", resp.GetPvz().GetExternalCode())
+	assert.NotEmpty(t, resp.GetPvz().GetId())
+
+	mockRepo.EXPECT().CreatePvz(gomock.Any(), gomock.Any()).Return(nil, repository.ErrPvzExternalCodeExists)
+	_, err = client.CreatePVZ(ctx, &pbv1.CreatePVZRequest{City: "Казань", ExternalCode: "KZN-1"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.CreatePVZ(ctx, &pbv1.CreatePVZRequest{City: "Тверь"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_GetPVZ(t *testing.T) {
+	srv, mockRepo, mockAuth := newTestService(t)
+	client := newTestClient(t, srv, mockAuth)
+	ctx := withToken(context.Background(), "employee-token")
+	pvzId := uuid.New()
+
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).
+		Return(&dto.PVZResponse{Id: pvzId, RegistrationDate: time.Now(), City: "Москва"}, nil)
+	resp, err := client.GetPVZ(ctx, &pbv1.GetPVZRequest{Id: pvzId.String()})
+	require.NoError(t, err)
+	assert.Equal(t, pvzId.String(), resp.GetPvz().GetId())
+	assert.Equal(t, "Москва", resp.GetPvz().GetCity())
+
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(nil, repository.ErrPVZNotFound)
+	_, err = client.GetPVZ(ctx, &pbv1.GetPVZRequest{Id: pvzId.String()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetPVZ(ctx, &pbv1.GetPVZRequest{Id: "42"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockRepo.EXPECT().GetPvzById(gomock.Any(), pvzId).Return(nil, errors.New("connection reset"))
+	_, err = client.GetPVZ(ctx, &pbv1.GetPVZRequest{Id: pvzId.String()})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, err.Error(), "connection reset")
+}
+
+func TestServer_Reception(t *testing.T) {
+	srv, mockRepo, mockAuth := newTestService(t)
+	expectEvents(mockRepo)
+	client := newTestClient(t, srv, mockAuth)
+	ctx := withToken(context.Background(), "employee-token")
+	pvzId := uuid.New()
+	reception := &models.Reception{Id: uuid.New(), PvzId: pvzId, DateTime: time.Now(), Status: models.StatusInProgress}
+	productId := uuid.New()
+
+	mockRepo.EXPECT().CreateReception(gomock.Any(), pvzId).Return(&dto.CreateReceptionResponse{
+		Id: reception.Id, DateTime: reception.DateTime, PvzId: pvzId, Status: "in_progress",
+	}, nil)
+	created, err := client.CreateReception(ctx, &pbv1.CreateReceptionRequest{PvzId: pvzId.String()})
+	require.NoError(t, err)
+	assert.Equal(t, reception.Id.String(), created.GetReception().GetId())
+	assert.Equal(t, pbv1.ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS, created.GetReception().GetStatus())
+
+	mockRepo.EXPECT().GetActiveReception(gomock.Any(), pvzId).Return(reception, nil).Times(2)
+	mockRepo.EXPECT().CreateProduct(gomock.Any(), "обувь", reception.Id).Return(&dto.AddProductResponse{
+		Id: productId, DateTime: time.Now(), Type: "обувь", ReceptionId: reception.Id,
+	}, nil)
+	product, err := client.AddProduct(ctx, &pbv1.AddProductRequest{PvzId: pvzId.String(), Type: "обувь"})
+	require.NoError(t, err)
+	assert.Equal(t, productId.String(), product.GetProduct().GetId())
+
+	_, err = client.AddProduct(ctx, &pbv1.AddProductRequest{PvzId: pvzId.String(), Type: "мебель"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockRepo.EXPECT().DeleteLastProduct(gomock.Any(), pvzId, testEmployee.Id).
+		Return(&dto.ProductResponse{Id: productId, Type: "обувь", ReceptionId: reception.Id}, nil)
+	_, err = client.DeleteLastProduct(ctx, &pbv1.DeleteLastProductRequest{PvzId: pvzId.String()})
+	require.NoError(t, err)
+
+	mockRepo.EXPECT().CloseReception(gomock.Any(), pvzId).Return(&dto.CloseLastReceptionResponse{
+		Id: reception.Id, DateTime: reception.DateTime, PvzId: pvzId, Status: "close",
+	}, nil)
+	closed, err := client.CloseLastReception(ctx, &pbv1.CloseLastReceptionRequest{PvzId: pvzId.String()})
+	require.NoError(t, err)
+	assert.Equal(t, pbv1.ReceptionStatus_RECEPTION_STATUS_CLOSED, closed.GetReception().GetStatus())
+
+	mockRepo.EXPECT().CloseReception(gomock.Any(), pvzId).Return(nil, repository.ErrNoActiveReception)
+	_, err = client.CloseLastReception(ctx, &pbv1.CloseLastReceptionRequest{PvzId: pvzId.String()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.CreateReception(ctx, &pbv1.CreateReceptionRequest{PvzId: ""})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_GetPVZList(t *testing.T) {
+	srv, mockRepo, mockAuth := newTestService(t)
+	client := newTestClient(t, srv, mockAuth)
+	ctx := withToken(context.Background(), "moderator-token")
+	pvzId, userId := uuid.New(), uuid.New()
+	deletedAt := time.Now()
+	page := &dto.GetPvzResponse{
+		Total:      12,
+		NextCursor: "next-page",
+		Items: []*dto.PVZWithReceptions{{
+			PVZ:     dto.PVZResponse{Id: pvzId, RegistrationDate: time.Now(), City: "Казань"},
+			Summary: dto.PVZSummary{Receptions: 1, Products: 1},
+			Receptions: []dto.ReceptionWithProducts{{
+				Reception: dto.ReceptionResponse{Id: uuid.New(), PvzId: pvzId, Status: "close"},
+				Products: []dto.ProductResponse{
+					{Id: uuid.New(), Type: "обувь"},
+					{Id: uuid.New(), Type: "одежда", DeletedAt: &deletedAt, DeletedBy: &userId},
+				},
+			}},
+		}},
+	}
+
+	mockRepo.EXPECT().GetPvz(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter dto.GetPvzRequest) (*dto.GetPvzResponse, error) {
+			assert.Equal(t, 5, filter.Limit)
+			assert.Equal(t, dto.IncludeProducts, filter.Include)
+			assert.Equal(t, []string{"Казань"}, filter.Cities)
+			assert.True(t, filter.IncludeDeleted)
+			return page, nil
+		})
+	resp, err := client.GetPVZList(ctx, &pbv1.GetPVZListRequest{
+		Cities:         []string{"Казань"},
+		PageSize:       5,
+		Include:        pbv1.PVZInclude_PVZ_INCLUDE_PRODUCTS,
+		IncludeDeleted: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "next-page", resp.GetNextPageToken())
+	assert.EqualValues(t, 12, resp.GetTotalSize())
+	require.Len(t, resp.GetItems(), 1)
+	item := resp.GetItems()[0]
+	assert.Equal(t, pvzId.String(), item.GetPvz().GetId())
+	assert.EqualValues(t, 1, item.GetSummary().GetProducts())
+	require.Len(t, item.GetReceptions(), 1)
+	assert.Equal(t, pbv1.ReceptionStatus_RECEPTION_STATUS_CLOSED, item.GetReceptions()[0].GetReception().GetStatus())
+	products := item.GetReceptions()[0].GetProducts()
+	require.Len(t, products, 2)
+	assert.Nil(t, products[0].GetDeletedAt())
+	assert.Equal(t, userId.String(), products[1].GetDeletedBy())
+	assert.Equal(t, []*pbv1.PVZ{item.GetPvz()}, resp.GetPvzs())
+
+	mockRepo.EXPECT().GetPvz(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter dto.GetPvzRequest) (*dto.GetPvzResponse, error) {
+			assert.Equal(t, maxLimit, filter.Limit)
+			assert.Equal(t, dto.IncludeNone, filter.Include)
+			return &dto.GetPvzResponse{}, nil
+		})
+	resp, err = client.GetPVZList(ctx, &pbv1.GetPVZListRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetNextPageToken())
+
+	tests := []struct {
+		name    string
+		token   string
+		request *pbv1.GetPVZListRequest
+		code    codes.Code
+	}{
+		{"page size too big", "moderator-token", &pbv1.GetPVZListRequest{PageSize: 31}, codes.InvalidArgument},
+		{"invalid page token", "moderator-token", &pbv1.GetPVZListRequest{PageToken: "garbage"}, codes.InvalidArgument},
+		{"unknown include", "moderator-token", &pbv1.GetPVZListRequest{Include: pbv1.PVZInclude(7)}, codes.InvalidArgument},
+		{"employee includes deleted", "employee-token", &pbv1.GetPVZListRequest{IncludeDeleted: true}, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetPVZList(withToken(context.Background(), tt.token), tt.request)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	pbv1 "github.com/senorUVE/pvz_service/internal/generated"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// eventTypes leaves EVENT_TYPE_UNSPECIFIED out, so it maps to an empty type
+// that validation rejects.
+var eventTypes = map[pbv1.EventType]events.Type{
+	pbv1.EventType_EVENT_TYPE_RECEPTION_OPENED: events.ReceptionOpened,
+	pbv1.EventType_EVENT_TYPE_RECEPTION_CLOSED: events.ReceptionClosed,
+	pbv1.EventType_EVENT_TYPE_PRODUCT_ADDED:    events.ProductAdded,
+	pbv1.EventType_EVENT_TYPE_PRODUCT_DELETED:  events.ProductDeleted,
+	pbv1.EventType_EVENT_TYPE_PRODUCT_RESTORED: events.ProductRestored,
+	pbv1.EventType_EVENT_TYPE_STREAM_RESET:     events.StreamReset,
+}
+
+// WatchReceptions pushes the events the service publishes on every change
+// of receptions and products. Send blocks while the client's flow control
+// window is full; meanwhile events queue up in the subscription, and a
+// client that falls further behind than the subscriber buffer is cut off
+// with Unavailable and resumes from its last resume_token, as it does when
+// the server shuts down.
+func (s *Server) WatchReceptions(req *pbv1.WatchReceptionsRequest, stream grpc.ServerStreamingServer[pbv1.ReceptionEvent]) error {
+	ctx := stream.Context()
+	request := &dto.EventStreamRequest{
+		PvzIds:      req.GetPvzIds(),
+		Cities:      req.GetCities(),
+		LastEventId: req.GetResumeToken(),
+	}
+	for _, typ := range req.GetTypes() {
+		request.Types = append(request.Types, string(eventTypes[typ]))
+	}
+
+	moderator := hasRole(ctx, models.RoleModerator)
+
+	sub, err := s.srv.SubscribeEvents(request)
+	if err != nil {
+		return toStatus(err)
+	}
+	defer sub.Close()
+
+	if sub.Reset {
+		lastId := sub.LastId
+		if len(sub.Replay) > 0 {
+			lastId = sub.Replay[0].Id - 1
+		}
+		reset := events.Event{Id: lastId, Type: events.StreamReset, Time: time.Now().UTC()}
+		if err := stream.Send(toEventPb(reset, moderator)); err != nil {
+			return err
+		}
+	}
+	for _, event := range sub.Replay {
+		if err := stream.Send(toEventPb(event, moderator)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.shutdown:
+			return toStatus(ErrShuttingDown)
+		case event, ok := <-sub.Events():
+			if !ok {
+				return toStatus(ErrWatcherBehind)
+			}
+			if err := stream.Send(toEventPb(event, moderator)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toEventPb(event events.Event, moderator bool) *pbv1.ReceptionEvent {
+	pb := &pbv1.ReceptionEvent{
+		Time:        timestamppb.New(event.Time),
+		PvzId:       event.PvzId.String(),
+		City:        event.City,
+		ReceptionId: event.ReceptionId.String(),
+		ProductType: event.ProductType,
+		ResumeToken: strconv.FormatUint(event.Id, 10),
+	}
+	for typ, name := range eventTypes {
+		if name == event.Type {
+			pb.Type = typ
+		}
+	}
+	if event.ProductId != nil {
+		productId := event.ProductId.String()
+		pb.ProductId = &productId
+	}
+	if moderator && event.UserId != nil {
+		userId := event.UserId.String()
+		pb.UserId = &userId
+	}
+	return pb
+}
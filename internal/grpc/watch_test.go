@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	pbv1 "github.com/senorUVE/pvz_service/internal/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_WatchReceptions(t *testing.T) {
+	srv, mockRepo, mockAuth := newTestService(t)
+	expectEvents(mockRepo)
+	client := newTestClient(t, srv, mockAuth)
+	pvzId, otherPvzId := uuid.New(), uuid.New()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	openReception := func(pvzId uuid.UUID) uuid.UUID {
+		receptionId := uuid.New()
+		mockRepo.EXPECT().CreateReception(gomock.Any(), pvzId).Return(&dto.CreateReceptionResponse{
+			Id: receptionId, DateTime: time.Now(), PvzId: pvzId, Status: "in_progress",
+		}, nil)
+		_, err := client.CreateReception(withToken(ctx, "employee-token"), &pbv1.CreateReceptionRequest{PvzId: pvzId.String()})
+		require.NoError(t, err)
+		return receptionId
+	}
+
+	// Published before the watch starts, so it can only arrive as a replay.
+	first := openReception(pvzId)
+
+	stream, err := client.WatchReceptions(withToken(ctx, "employee-token"), &pbv1.WatchReceptionsRequest{
+		PvzIds:      []string{pvzId.String()},
+		ResumeToken: "0",
+	})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pbv1.EventType_EVENT_TYPE_RECEPTION_OPENED, event.GetType())
+	assert.Equal(t, first.String(), event.GetReceptionId())
+	assert.Equal(t, "Москва", event.GetCity())
+	resumeToken := event.GetResumeToken()
+
+	openReception(otherPvzId)
+	second := openReception(pvzId)
+
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, second.String(), event.GetReceptionId())
+	assert.NotEqual(t, resumeToken, event.GetResumeToken())
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestServer_WatchReceptionsResume(t *testing.T) {
+	srv, mockRepo, mockAuth := newTestService(t)
+	expectEvents(mockRepo)
+	client := newTestClient(t, srv, mockAuth)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The broker starts from scratch, so a token from a previous process is
+	// ahead of it and the stream starts with a reset.
+	stream, err := client.WatchReceptions(withToken(ctx, "moderator-token"), &pbv1.WatchReceptionsRequest{ResumeToken: "100"})
+	require.NoError(t, err)
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pbv1.EventType_EVENT_TYPE_STREAM_RESET, event.GetType())
+
+	tests := []struct {
+		name    string
+		token   string
+		request *pbv1.WatchReceptionsRequest
+		code    codes.Code
+	}{
+		{"invalid resume token", "moderator-token", &pbv1.WatchReceptionsRequest{ResumeToken: "abc"}, codes.InvalidArgument},
+		{"unspecified type", "moderator-token", &pbv1.WatchReceptionsRequest{
+			Types: []pbv1.EventType{pbv1.EventType_EVENT_TYPE_UNSPECIFIED},
+		}, codes.InvalidArgument},
+		{"no token", "", &pbv1.WatchReceptionsRequest{}, codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCtx := ctx
+			if tt.token != "" {
+				callCtx = withToken(ctx, tt.token)
+			}
+			stream, err := client.WatchReceptions(callCtx, tt.request)
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package handler
+
+import "time"
+
+const defaultEventsHeartbeat = 15 * time.Second
+
+// APIConfig announces the retirement of API v1, zero values disable the
+// corresponding headers. ValidateResponses checks responses against the
+// spec and should only be enabled in development.
+type APIConfig struct {
+	V1Deprecation     time.Time     `mapstructure:"v1_deprecation"`
+	V1Sunset          time.Time     `mapstructure:"v1_sunset"`
+	V1SunsetLink      string        `mapstructure:"v1_sunset_link"`
+	ValidateResponses bool          `mapstructure:"validate_responses"`
+	EventsHeartbeat   time.Duration `mapstructure:"events_heartbeat"`
+}
+
+func (c APIConfig) eventsHeartbeat() time.Duration {
+	if c.EventsHeartbeat <= 0 {
+		return defaultEventsHeartbeat
+	}
+	return c.EventsHeartbeat
+}
@@ -9,3 +9,25 @@ var ErrInvalidAuthHeader = errors.New("invalid auth header")
 var ErrInvalidToken = errors.New("invalid token")
 
 var ErrInternalServer = errors.New("internal error")
+
+var ErrInvalidRequest = errors.New("invalid request")
+
+var ErrInvalidPvzId = errors.New("invalid PVZ ID")
+
+var ErrInvalidReceptionId = errors.New("invalid reception ID")
+
+var ErrInvalidProductId = errors.New("invalid product ID")
+
+var ErrInvalidWebhookId = errors.New("invalid webhook ID")
+
+var ErrInvalidDeliveryId = errors.New("invalid webhook delivery ID")
+
+var ErrUserNotInContext = errors.New("user not found in context")
+
+var ErrForbidden = errors.New("insufficient permissions")
+
+var ErrIncludeDeletedForbidden = errors.New("includeDeleted is available to moderators only")
+
+var ErrIdempotencyKeyTooLong = errors.New("Idempotency-Key is too long")
+
+var ErrRequestValidation = errors.New("request does not match the API specification")
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/senorUVE/pvz_service/internal/models"
+)
+
+const (
+	eventStreamContentType = "text/event-stream"
+	lastEventIdHeader      = "Last-Event-ID"
+)
+
+// StreamEvents pushes reception activity as Server-Sent Events. Employees
+// do not see who deleted a product. The stream ends when the server shuts
+// down, and the client reconnects with Last-Event-ID.
+func (h *PvzHandler) StreamEvents(c echo.Context) error {
+	var req dto.EventStreamRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrInvalidRequest
+	}
+	if id := c.Request().Header.Get(lastEventIdHeader); id != "" {
+		req.LastEventId = id
+	}
+
+	moderator := hasRole(c, models.RoleModerator)
+
+	sub, err := h.pvzService.SubscribeEvents(&req)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, eventStreamContentType)
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if sub.Reset {
+		lastId := sub.LastId
+		if len(sub.Replay) > 0 {
+			lastId = sub.Replay[0].Id - 1
+		}
+		writeServerEvent(res, events.Event{Id: lastId, Type: events.StreamReset, Time: time.Now().UTC()})
+	}
+	for _, event := range sub.Replay {
+		writeServerEvent(res, scopeEvent(event, moderator))
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(h.cfg.eventsHeartbeat())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-h.shutdown:
+			return nil
+		case <-heartbeat.C:
+			fmt.Fprint(res, ": heartbeat\n\n")
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Too slow to keep up: the client reconnects and resumes.
+				return nil
+			}
+			writeServerEvent(res, scopeEvent(event, moderator))
+		}
+		res.Flush()
+	}
+}
+
+func scopeEvent(event events.Event, moderator bool) events.Event {
+	if !moderator {
+		event.UserId = nil
+	}
+	return event
+}
+
+func writeServerEvent(w io.Writer, event events.Event) {
+	data, _ := json.Marshal(event)
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/internal/graph"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+const graphQLPath = "/graphql"
+
+// GraphQL always answers 200 with a GraphQL response; resolver errors carry
+// the same codes as problem+json responses in their extensions.
+func (h *PvzHandler) GraphQL(c echo.Context) error {
+	var req graph.Request
+	if err := c.Bind(&req); err != nil || req.Query == "" {
+		return ErrInvalidRequest
+	}
+	user, ok := c.Get("user").(*models.User)
+	if !ok {
+		return ErrUserNotInContext
+	}
+
+	response := h.graphQL.Exec(c.Request().Context(), user, req)
+	for _, queryErr := range response.Errors {
+		describeGraphQLError(queryErr)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+func describeGraphQLError(queryErr *gqlerrors.QueryError) {
+	const op = "internal.handler.GraphQL"
+	if queryErr.ResolverError == nil {
+		return
+	}
+
+	problem := toProblem(queryErr.ResolverError)
+	if problem.Status >= http.StatusInternalServerError {
+		logrus.WithFields(logrus.Fields{"event": op, "path": queryErr.Path}).Error(queryErr.ResolverError)
+		queryErr.Message = http.StatusText(problem.Status)
+	}
+	queryErr.Extensions = map[string]any{"code": problem.Code, "status": problem.Status}
+	if len(problem.InvalidParams) > 0 {
+		queryErr.Extensions["invalidParams"] = problem.InvalidParams
+	}
+}
@@ -3,12 +3,17 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/senorUVE/pvz_service/internal/auth"
 	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/senorUVE/pvz_service/internal/graph"
 	"github.com/senorUVE/pvz_service/internal/models"
 )
 
@@ -17,12 +22,33 @@ type PvzService interface {
 	CreateUser(ctx context.Context, request *dto.RegisterRequest) (*models.User, error)
 	AuthUser(ctx context.Context, request *dto.AuthRequest) (*dto.AuthResponse, error)
 	CreatePVZ(ctx context.Context, request *dto.PvzCreateRequest) (*dto.PvzCreateResponse, error)
-	GetPvz(ctx context.Context, request *dto.GetPvzRequest) ([]*dto.PVZWithReceptions, error)
+	ImportPvz(ctx context.Context, r io.Reader, request *dto.PvzImportRequest) (*dto.PvzImportResponse, error)
+	GetPvz(ctx context.Context, request *dto.GetPvzRequest) (*dto.GetPvzResponse, error)
+	GetPvzReceptions(ctx context.Context, pvzIds []uuid.UUID, request *dto.GetPvzRequest) (map[uuid.UUID][]dto.ReceptionWithProducts, error)
 	CloseReception(ctx context.Context, pvzID uuid.UUID) (*dto.CloseLastReceptionResponse, error)
-	DeleteLastProduct(ctx context.Context, pvzId uuid.UUID) error
+	DeleteLastProduct(ctx context.Context, pvzId, userId uuid.UUID) error
+	RestoreLastProduct(ctx context.Context, pvzId, userId uuid.UUID) (*dto.ProductResponse, error)
 	CreateReception(ctx context.Context, request *dto.CreateReceptionRequest) (*dto.CreateReceptionResponse, error)
 	AddProduct(ctx context.Context, request *dto.AddProductRequest) (*dto.AddProductResponse, error)
 	DummyLogin(ctx context.Context, role string) (string, error)
+	GetProductLabel(ctx context.Context, productId uuid.UUID, format string) (*dto.LabelResponse, error)
+	GetReceptionLabels(ctx context.Context, receptionId uuid.UUID, format string) (*dto.LabelResponse, error)
+	GetProduct(ctx context.Context, productId uuid.UUID) (*dto.ProductDetailsResponse, error)
+	FindProduct(ctx context.Context, request *dto.GetProductRequest) (*dto.ProductDetailsResponse, error)
+	IssueProduct(ctx context.Context, productId, userId uuid.UUID) (*dto.ProductDetailsResponse, error)
+	MoveProduct(ctx context.Context, productId uuid.UUID, request *dto.MoveProductRequest, userId uuid.UUID) (*dto.ProductDetailsResponse, error)
+	ReserveIdempotencyKey(ctx context.Context, record dto.IdempotencyRecord) (*dto.IdempotencyRecord, error)
+	SaveIdempotencyResponse(ctx context.Context, record dto.IdempotencyRecord) error
+	ReleaseIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) error
+	SubscribeEvents(request *dto.EventStreamRequest) (*events.Subscription, error)
+	CreateWebhook(ctx context.Context, request *dto.WebhookRequest, userId uuid.UUID) (*dto.WebhookResponse, error)
+	ListWebhooks(ctx context.Context) ([]dto.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error
+	GetWebhookDeliveries(ctx context.Context, webhookId uuid.UUID, request *dto.GetWebhookDeliveriesRequest) ([]dto.WebhookDeliveryResponse, error)
+	RedeliverWebhook(ctx context.Context, deliveryId uuid.UUID) (*dto.WebhookDeliveryResponse, error)
+	ExportReceptions(ctx context.Context, request *dto.ReceptionReportRequest, fn func(dto.ReceptionReportRow) error) error
+	GetIntakeReport(ctx context.Context, request *dto.IntakeReportRequest) (*dto.IntakeReportResponse, error)
+	Sync(ctx context.Context, request *dto.SyncRequest, userId uuid.UUID) (*dto.SyncResponse, error)
 }
 
 type PvzHandler struct {
@@ -30,16 +56,27 @@ type PvzHandler struct {
 	pvzService PvzService
 	auth       auth.AuthService
 	port       string
+	cfg        APIConfig
+	graphQL    *graph.Schema
+	// shutdown is closed by Close to end event streams, which would
+	// otherwise keep Shutdown waiting.
+	shutdown chan struct{}
+	close    sync.Once
 }
 
-func NewPvzHandler(srv PvzService, auth auth.AuthService, port string) *PvzHandler {
+func NewPvzHandler(srv PvzService, auth auth.AuthService, port string, cfg APIConfig) *PvzHandler {
 	e := echo.New()
-	return &PvzHandler{
+	h := &PvzHandler{
 		e:          e,
 		pvzService: srv,
 		auth:       auth,
 		port:       port,
+		cfg:        cfg,
+		graphQL:    graph.NewSchema(srv),
+		shutdown:   make(chan struct{}),
 	}
+	e.HTTPErrorHandler = h.HTTPErrorHandler
+	return h
 
 }
 
@@ -54,18 +91,19 @@ func (h *PvzHandler) Start() error {
 }
 
 func (h *PvzHandler) Close(ctx context.Context) error {
+	h.close.Do(func() { close(h.shutdown) })
 	return h.e.Shutdown(ctx)
 }
 
 func (h *PvzHandler) Register(c echo.Context) error {
 	var req dto.RegisterRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: "Invalid request"})
+		return ErrInvalidRequest
 	}
 
 	user, err := h.pvzService.CreateUser(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, user)
@@ -74,12 +112,12 @@ func (h *PvzHandler) Register(c echo.Context) error {
 func (h *PvzHandler) Login(c echo.Context) error {
 	var req dto.AuthRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: "Invalid request"})
+		return ErrInvalidRequest
 	}
 
 	response, err := h.pvzService.AuthUser(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Errors: err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -88,41 +126,80 @@ func (h *PvzHandler) Login(c echo.Context) error {
 func (h *PvzHandler) CreatePVZ(c echo.Context) error {
 	var req dto.PvzCreateRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: "Invalid request"})
+		return ErrInvalidRequest
 	}
 
 	response, err := h.pvzService.CreatePVZ(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, response)
 }
 
+func (h *PvzHandler) ImportPvz(c echo.Context) error {
+	var req dto.PvzImportRequest
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return ErrInvalidRequest
+	}
+
+	response, err := h.pvzService.ImportPvz(c.Request().Context(), c.Request().Body, &req)
+	if err != nil {
+		return err
+	}
+
+	status := http.StatusCreated
+	if req.DryRun {
+		status = http.StatusOK
+	}
+	return c.JSON(status, response)
+}
+
+// GetPvz serves v1, which still returns a bare list to clients asking for
+// the legacy media type.
 func (h *PvzHandler) GetPvz(c echo.Context) error {
+	return h.getPvz(c, true)
+}
+
+func (h *PvzHandler) GetPvzV2(c echo.Context) error {
+	return h.getPvz(c, false)
+}
+
+func (h *PvzHandler) getPvz(c echo.Context, allowLegacyList bool) error {
 	var req dto.GetPvzRequest
 
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: "Invalid request"})
+		return ErrInvalidRequest
+	}
+
+	if req.IncludeDeleted && !hasRole(c, models.RoleModerator) {
+		return ErrIncludeDeletedForbidden
 	}
 
 	response, err := h.pvzService.GetPvz(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Errors: err.Error()})
+		return err
 	}
 
+	c.Response().Header().Add(linkHeader, paginationLinks(c.Request().URL, response))
+	if allowLegacyList {
+		c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+		if acceptsLegacyList(c) {
+			return c.JSON(http.StatusOK, response.Items)
+		}
+	}
 	return c.JSON(http.StatusOK, response)
 }
 
 func (h *PvzHandler) CreateReception(c echo.Context) error {
 	var req dto.CreateReceptionRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: "Invalid request"})
+		return ErrInvalidRequest
 	}
 
 	response, err := h.pvzService.CreateReception(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, response)
@@ -131,12 +208,12 @@ func (h *PvzHandler) CreateReception(c echo.Context) error {
 func (h *PvzHandler) AddProduct(c echo.Context) error {
 	var req dto.AddProductRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: "Invalid request"})
+		return ErrInvalidRequest
 	}
 
 	response, err := h.pvzService.AddProduct(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, response)
@@ -145,12 +222,12 @@ func (h *PvzHandler) AddProduct(c echo.Context) error {
 func (h *PvzHandler) CloseReception(c echo.Context) error {
 	pvzID, err := uuid.Parse(c.Param("pvzId"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: "Invalid PVZ ID"})
+		return ErrInvalidPvzId
 	}
 
 	response, err := h.pvzService.CloseReception(c.Request().Context(), pvzID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -159,30 +236,138 @@ func (h *PvzHandler) CloseReception(c echo.Context) error {
 func (h *PvzHandler) DeleteLastProduct(c echo.Context) error {
 	pvzID, err := uuid.Parse(c.Param("pvzId"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: "Invalid PVZ ID"})
+		return ErrInvalidPvzId
 	}
 
-	if err := h.pvzService.DeleteLastProduct(c.Request().Context(), pvzID); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: err.Error()})
+	if err := h.pvzService.DeleteLastProduct(c.Request().Context(), pvzID, currentUserId(c)); err != nil {
+		return err
 	}
 
 	return c.NoContent(http.StatusOK)
 }
 
+func (h *PvzHandler) UndoDeleteLastProduct(c echo.Context) error {
+	pvzID, err := uuid.Parse(c.Param("pvzId"))
+	if err != nil {
+		return ErrInvalidPvzId
+	}
+
+	response, err := h.pvzService.RestoreLastProduct(c.Request().Context(), pvzID, currentUserId(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
 func (h *PvzHandler) DummyLogin(c echo.Context) error {
 	var req dto.DummyLoginRequest
 
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{Errors: "Invalid request"})
+		return ErrInvalidRequest
 	}
 
 	token, err := h.pvzService.DummyLogin(c.Request().Context(), req.Role)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Errors: err.Error()})
+		return err
 	}
 	return c.JSON(http.StatusOK, token)
 }
 
+func (h *PvzHandler) GetProductLabel(c echo.Context) error {
+	productID, err := uuid.Parse(c.Param("productId"))
+	if err != nil {
+		return ErrInvalidProductId
+	}
+
+	response, err := h.pvzService.GetProductLabel(c.Request().Context(), productID, c.QueryParam("format"))
+	if err != nil {
+		return err
+	}
+
+	return sendLabel(c, response)
+}
+
+func (h *PvzHandler) GetReceptionLabels(c echo.Context) error {
+	receptionID, err := uuid.Parse(c.Param("receptionId"))
+	if err != nil {
+		return ErrInvalidReceptionId
+	}
+
+	response, err := h.pvzService.GetReceptionLabels(c.Request().Context(), receptionID, c.QueryParam("format"))
+	if err != nil {
+		return err
+	}
+
+	return sendLabel(c, response)
+}
+
+func (h *PvzHandler) GetProduct(c echo.Context) error {
+	productID, err := uuid.Parse(c.Param("productId"))
+	if err != nil {
+		return ErrInvalidProductId
+	}
+
+	response, err := h.pvzService.GetProduct(c.Request().Context(), productID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *PvzHandler) FindProduct(c echo.Context) error {
+	var req dto.GetProductRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrInvalidRequest
+	}
+
+	response, err := h.pvzService.FindProduct(c.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *PvzHandler) IssueProduct(c echo.Context) error {
+	productID, err := uuid.Parse(c.Param("productId"))
+	if err != nil {
+		return ErrInvalidProductId
+	}
+
+	response, err := h.pvzService.IssueProduct(c.Request().Context(), productID, currentUserId(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *PvzHandler) MoveProduct(c echo.Context) error {
+	productID, err := uuid.Parse(c.Param("productId"))
+	if err != nil {
+		return ErrInvalidProductId
+	}
+
+	var req dto.MoveProductRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrInvalidRequest
+	}
+
+	response, err := h.pvzService.MoveProduct(c.Request().Context(), productID, &req, currentUserId(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func sendLabel(c echo.Context, label *dto.LabelResponse) error {
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", label.FileName))
+	return c.Blob(http.StatusOK, label.ContentType, label.Data)
+}
+
 func (h *PvzHandler) Ping(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "pong")
 }
@@ -1,9 +1,14 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -11,10 +16,18 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/internal/auth"
+	"github.com/senorUVE/pvz_service/internal/controller"
 	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/events"
+	"github.com/senorUVE/pvz_service/internal/label"
 	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/internal/repository"
 	"github.com/senorUVE/pvz_service/test/mocks"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestShopHandlerPing(t *testing.T) {
@@ -25,7 +38,7 @@ func TestShopHandlerPing(t *testing.T) {
 	mockAuthService := mocks.NewMockAuthService(ctrl)
 
 	e := echo.New()
-	handler := NewPvzHandler(mockShopService, mockAuthService, "8080")
+	handler := NewPvzHandler(mockShopService, mockAuthService, "8080", APIConfig{})
 
 	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
 	rec := httptest.NewRecorder()
@@ -45,7 +58,7 @@ func TestShopHandlerAuthMiddleware_InvalidAuthHeader(t *testing.T) {
 	mockAuthService := mocks.NewMockAuthService(ctrl)
 
 	e := echo.New()
-	handler := NewPvzHandler(mockShopService, mockAuthService, "8080")
+	handler := NewPvzHandler(mockShopService, mockAuthService, "8080", APIConfig{})
 
 	e.Use(handler.AuthMiddleware())
 
@@ -57,6 +70,7 @@ func TestShopHandlerAuthMiddleware_InvalidAuthHeader(t *testing.T) {
 	req.Header.Set(authorizationHeader, "InvalidHeader")
 	rec := httptest.NewRecorder()
 
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
 	e.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusUnauthorized, rec.Code)
@@ -71,7 +85,7 @@ func TestShopHandlerAuthMiddleware_EmptyToken(t *testing.T) {
 	mockAuthService := mocks.NewMockAuthService(ctrl)
 
 	e := echo.New()
-	handler := NewPvzHandler(mockShopService, mockAuthService, "8080")
+	handler := NewPvzHandler(mockShopService, mockAuthService, "8080", APIConfig{})
 
 	e.Use(handler.AuthMiddleware())
 
@@ -82,6 +96,7 @@ func TestShopHandlerAuthMiddleware_EmptyToken(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
 	rec := httptest.NewRecorder()
 
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
 	e.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusUnauthorized, rec.Code)
@@ -96,7 +111,7 @@ func TestShopHandlerAuthMiddleware_ValidToken(t *testing.T) {
 	mockAuthService := mocks.NewMockAuthService(ctrl)
 
 	e := echo.New()
-	handler := NewPvzHandler(mockShopService, mockAuthService, "8080")
+	handler := NewPvzHandler(mockShopService, mockAuthService, "8080", APIConfig{})
 
 	e.Use(handler.AuthMiddleware())
 
@@ -110,6 +125,7 @@ func TestShopHandlerAuthMiddleware_ValidToken(t *testing.T) {
 	req.Header.Set(authorizationHeader, "Bearer valid-token")
 	rec := httptest.NewRecorder()
 
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
 	e.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
@@ -122,7 +138,7 @@ func TestRegisterHandler_Success(t *testing.T) {
 
 	mockService := mocks.NewMockPvzService(ctrl)
 	mockAuth := mocks.NewMockAuthService(ctrl)
-	handler := NewPvzHandler(mockService, mockAuth, "8080")
+	handler := NewPvzHandler(mockService, mockAuth, "8080", APIConfig{})
 
 	reqBody := `{"email":"test@email.ru","password":"password123","role":"moderator"}`
 	req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(reqBody))
@@ -146,7 +162,7 @@ func TestRegisterHandler_InvalidRequest(t *testing.T) {
 
 	mockService := mocks.NewMockPvzService(ctrl)
 	authService := mocks.NewMockAuthService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 
 	req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader("invalid json"))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -155,9 +171,10 @@ func TestRegisterHandler_InvalidRequest(t *testing.T) {
 
 	err := handler.Register(c)
 
-	assert.NoError(t, err)
+	require.Error(t, err)
+	c.Error(err)
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), "Invalid request")
+	assert.Contains(t, rec.Body.String(), "invalid_request")
 }
 
 func TestCreatePVZHandler_Success(t *testing.T) {
@@ -166,7 +183,7 @@ func TestCreatePVZHandler_Success(t *testing.T) {
 
 	mockService := mocks.NewMockPvzService(ctrl)
 	authService := mocks.NewMockAuthService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 
 	reqBody := `{"city":"Москва"}`
 	req := httptest.NewRequest(http.MethodPost, "/pvz", strings.NewReader(reqBody))
@@ -190,13 +207,13 @@ func TestGetPvzHandler_WithFilters(t *testing.T) {
 
 	mockService := mocks.NewMockPvzService(ctrl)
 	authService := mocks.NewMockAuthService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 
 	req := httptest.NewRequest(http.MethodGet, "/pvz?startDate=2023-01-01T00:00:00Z&endDate=2023-01-31T23:59:59Z&page=2&limit=20", nil)
 	rec := httptest.NewRecorder()
 	c := handler.e.NewContext(req, rec)
 
-	expected := []*dto.PVZWithReceptions{{PVZ: dto.PVZResponse{City: "Москва"}}}
+	expected := &dto.GetPvzResponse{Items: []*dto.PVZWithReceptions{{PVZ: dto.PVZResponse{City: "Москва"}}}}
 	mockService.EXPECT().GetPvz(gomock.Any(), gomock.Any()).Return(expected, nil)
 
 	err := handler.GetPvz(c)
@@ -207,7 +224,7 @@ func TestGetPvzHandler_WithFilters(t *testing.T) {
 }
 
 func TestCloseReceptionHandler_InvalidUUID(t *testing.T) {
-	handler := NewPvzHandler(nil, nil, "8080")
+	handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
 
 	req := httptest.NewRequest(http.MethodPost, "/pvz/invalid_uuid/close_last_reception", nil)
 	rec := httptest.NewRecorder()
@@ -217,9 +234,10 @@ func TestCloseReceptionHandler_InvalidUUID(t *testing.T) {
 
 	err := handler.CloseReception(c)
 
-	assert.NoError(t, err)
+	require.Error(t, err)
+	c.Error(err)
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), "Invalid PVZ ID")
+	assert.Contains(t, rec.Body.String(), "invalid_pvz_id")
 }
 
 func TestDeleteLastProductHandler_ServiceError(t *testing.T) {
@@ -228,7 +246,7 @@ func TestDeleteLastProductHandler_ServiceError(t *testing.T) {
 
 	mockService := mocks.NewMockPvzService(ctrl)
 	authService := mocks.NewMockAuthService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 	pvzID := uuid.New()
 
 	req := httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/delete_last_product", nil)
@@ -238,13 +256,14 @@ func TestDeleteLastProductHandler_ServiceError(t *testing.T) {
 	c.SetParamValues(pvzID.String())
 
 	expectedErr := errors.New("database error")
-	mockService.EXPECT().DeleteLastProduct(gomock.Any(), pvzID).Return(expectedErr)
+	mockService.EXPECT().DeleteLastProduct(gomock.Any(), pvzID, uuid.Nil).Return(expectedErr)
 
 	err := handler.DeleteLastProduct(c)
 
-	assert.NoError(t, err)
-	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), expectedErr.Error())
+	require.Error(t, err)
+	c.Error(err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "internal_error")
 }
 
 func TestRoleMiddleware_Forbidden(t *testing.T) {
@@ -253,7 +272,7 @@ func TestRoleMiddleware_Forbidden(t *testing.T) {
 
 	mockAuth := mocks.NewMockAuthService(ctrl)
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, mockAuth, "8080")
+	handler := NewPvzHandler(mockService, mockAuth, "8080", APIConfig{})
 
 	e := echo.New()
 
@@ -270,17 +289,18 @@ func TestRoleMiddleware_Forbidden(t *testing.T) {
 
 	mockAuth.EXPECT().ParseToken("valid_token").Return(&models.User{Role: models.RoleModerator}, nil)
 
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
 	e.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusForbidden, rec.Code)
-	assert.Contains(t, rec.Body.String(), "Insufficient permissions")
+	assert.Contains(t, rec.Body.String(), "forbidden")
 }
 
 func TestCreateReceptionHandler_InvalidRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	handler := NewPvzHandler(nil, nil, "8080")
+	handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
 
 	// Неверный формат тела запроса
 	req := httptest.NewRequest(http.MethodPost, "/receptions", strings.NewReader("{invalid json"))
@@ -290,9 +310,10 @@ func TestCreateReceptionHandler_InvalidRequest(t *testing.T) {
 
 	err := handler.CreateReception(c)
 
-	assert.NoError(t, err)
+	require.Error(t, err)
+	c.Error(err)
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), "Invalid request")
+	assert.Contains(t, rec.Body.String(), "invalid_request")
 }
 
 func TestAddProductHandler_ServiceError(t *testing.T) {
@@ -301,7 +322,7 @@ func TestAddProductHandler_ServiceError(t *testing.T) {
 
 	authService := mocks.NewMockAuthService(ctrl)
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 
 	reqBody := `{"pvzId":"aa6c3be3-945d-43b3-b0a5-18a1d8d5a5b3","type":"электроника"}`
 	req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(reqBody))
@@ -314,13 +335,14 @@ func TestAddProductHandler_ServiceError(t *testing.T) {
 
 	err := handler.AddProduct(c)
 
-	assert.NoError(t, err)
-	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), expectedErr.Error())
+	require.Error(t, err)
+	c.Error(err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "internal_error")
 }
 
 func TestRoleMiddleware_InvalidUserContext(t *testing.T) {
-	handler := NewPvzHandler(nil, nil, "8080")
+	handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
 
 	e := echo.New()
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -339,10 +361,11 @@ func TestRoleMiddleware_InvalidUserContext(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
 	rec := httptest.NewRecorder()
 
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
 	e.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusUnauthorized, rec.Code)
-	assert.Contains(t, rec.Body.String(), "User not found in context")
+	assert.Contains(t, rec.Body.String(), "unauthenticated")
 }
 
 func TestCloseReceptionHandler_ServiceError(t *testing.T) {
@@ -351,7 +374,7 @@ func TestCloseReceptionHandler_ServiceError(t *testing.T) {
 
 	authService := mocks.NewMockAuthService(ctrl)
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 	pvzID := uuid.New()
 
 	req := httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/close_last_reception", nil)
@@ -365,9 +388,10 @@ func TestCloseReceptionHandler_ServiceError(t *testing.T) {
 
 	err := handler.CloseReception(c)
 
-	assert.NoError(t, err)
-	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), expectedErr.Error())
+	require.Error(t, err)
+	c.Error(err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "internal_error")
 }
 
 func TestGetPvzHandler_InvalidDates(t *testing.T) {
@@ -376,7 +400,7 @@ func TestGetPvzHandler_InvalidDates(t *testing.T) {
 
 	mockService := mocks.NewMockPvzService(ctrl)
 	authService := mocks.NewMockAuthService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 
 	tests := []struct {
 		name        string
@@ -386,12 +410,12 @@ func TestGetPvzHandler_InvalidDates(t *testing.T) {
 		{
 			name:        "InvalidStartDate",
 			url:         "/pvz?startDate=invalid",
-			expectError: "Invalid request",
+			expectError: "invalid_request",
 		},
 		{
 			name:        "InvalidEndDate",
 			url:         "/pvz?endDate=invalid",
-			expectError: "Invalid request",
+			expectError: "invalid_request",
 		},
 	}
 
@@ -402,7 +426,8 @@ func TestGetPvzHandler_InvalidDates(t *testing.T) {
 			c := handler.e.NewContext(req, rec)
 
 			err := handler.GetPvz(c)
-			assert.NoError(t, err)
+			require.Error(t, err)
+			c.Error(err)
 			assert.Equal(t, http.StatusBadRequest, rec.Code)
 			assert.Contains(t, rec.Body.String(), tt.expectError)
 		})
@@ -415,7 +440,7 @@ func TestCreatePVZHandler_ServiceError(t *testing.T) {
 
 	authService := mocks.NewMockAuthService(ctrl)
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 
 	reqBody := `{"city":"Москва"}`
 	req := httptest.NewRequest(http.MethodPost, "/pvz", strings.NewReader(reqBody))
@@ -427,13 +452,14 @@ func TestCreatePVZHandler_ServiceError(t *testing.T) {
 	mockService.EXPECT().CreatePVZ(gomock.Any(), gomock.Any()).Return(nil, expectedErr)
 
 	err := handler.CreatePVZ(c)
-	assert.NoError(t, err)
-	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), expectedErr.Error())
+	require.Error(t, err)
+	c.Error(err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "internal_error")
 }
 
 func TestLoginHandler_InvalidRequest(t *testing.T) {
-	handler := NewPvzHandler(nil, nil, "8080")
+	handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
 
 	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("invalid json"))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -441,9 +467,10 @@ func TestLoginHandler_InvalidRequest(t *testing.T) {
 	c := handler.e.NewContext(req, rec)
 
 	err := handler.Login(c)
-	assert.NoError(t, err)
+	require.Error(t, err)
+	c.Error(err)
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), "Invalid request")
+	assert.Contains(t, rec.Body.String(), "invalid_request")
 }
 
 func TestLoginHandler_AuthError(t *testing.T) {
@@ -452,7 +479,7 @@ func TestLoginHandler_AuthError(t *testing.T) {
 
 	authService := mocks.NewMockAuthService(ctrl)
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 
 	reqBody := `{"email":"test@test.com","password":"wrong"}`
 	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(reqBody))
@@ -460,13 +487,14 @@ func TestLoginHandler_AuthError(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := handler.e.NewContext(req, rec)
 
-	expectedErr := errors.New("invalid credentials")
-	mockService.EXPECT().AuthUser(gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+	mockService.EXPECT().AuthUser(gomock.Any(), gomock.Any()).Return(nil, controller.ErrInvalidPasswd)
 
 	err := handler.Login(c)
-	assert.NoError(t, err)
+	require.Error(t, err)
+	c.Error(err)
 	assert.Equal(t, http.StatusUnauthorized, rec.Code)
-	assert.Contains(t, rec.Body.String(), expectedErr.Error())
+	assert.Equal(t, problemContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), `"code":"invalid_credentials"`)
 }
 
 func TestCloseReceptionHandler_Success(t *testing.T) {
@@ -475,7 +503,7 @@ func TestCloseReceptionHandler_Success(t *testing.T) {
 
 	authService := mocks.NewMockAuthService(ctrl)
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 	pvzID := uuid.New()
 
 	req := httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/close_last_reception", nil)
@@ -499,7 +527,7 @@ func TestRoleMiddleware_Allowed(t *testing.T) {
 
 	mockService := mocks.NewMockPvzService(ctrl)
 	mockAuth := mocks.NewMockAuthService(ctrl)
-	handler := NewPvzHandler(mockService, mockAuth, "8080")
+	handler := NewPvzHandler(mockService, mockAuth, "8080", APIConfig{})
 
 	e := echo.New()
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -517,6 +545,7 @@ func TestRoleMiddleware_Allowed(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
 	rec := httptest.NewRecorder()
 
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
 	e.ServeHTTP(rec, req)
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
@@ -527,7 +556,7 @@ func TestRegisterHandler_ServiceError(t *testing.T) {
 
 	authService := mocks.NewMockAuthService(ctrl)
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, authService, "8080")
+	handler := NewPvzHandler(mockService, authService, "8080", APIConfig{})
 
 	reqBody := `{"email":"test@test.com","password":"password123","role":"moderator"}`
 	req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(reqBody))
@@ -535,13 +564,13 @@ func TestRegisterHandler_ServiceError(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := handler.e.NewContext(req, rec)
 
-	expectedErr := errors.New("email already exists")
-	mockService.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+	mockService.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil, repository.ErrUserExists)
 
 	err := handler.Register(c)
-	assert.NoError(t, err)
-	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), expectedErr.Error())
+	require.Error(t, err)
+	c.Error(err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"user_exists"`)
 }
 
 func TestAuthMiddleware_ParseTokenError(t *testing.T) {
@@ -550,7 +579,7 @@ func TestAuthMiddleware_ParseTokenError(t *testing.T) {
 
 	serviceMock := mocks.NewMockPvzService(ctrl)
 	mockAuth := mocks.NewMockAuthService(ctrl)
-	handler := NewPvzHandler(serviceMock, mockAuth, "8080")
+	handler := NewPvzHandler(serviceMock, mockAuth, "8080", APIConfig{})
 
 	e := echo.New()
 	e.Use(handler.AuthMiddleware())
@@ -564,13 +593,14 @@ func TestAuthMiddleware_ParseTokenError(t *testing.T) {
 	req.Header.Set(authorizationHeader, "Bearer invalid-token")
 	rec := httptest.NewRecorder()
 
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
 	e.ServeHTTP(rec, req)
-	assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	assert.Contains(t, rec.Body.String(), "internal error")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"invalid_token"`)
 }
 
 func TestRoleMiddleware_MissingUser(t *testing.T) {
-	handler := NewPvzHandler(nil, nil, "8080")
+	handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
 
 	e := echo.New()
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -588,6 +618,7 @@ func TestRoleMiddleware_MissingUser(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
 	rec := httptest.NewRecorder()
 
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
 	e.ServeHTTP(rec, req)
 	assert.Equal(t, http.StatusUnauthorized, rec.Code)
 }
@@ -597,7 +628,7 @@ func TestGetPvzHandler_ServiceError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, nil, "8080")
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
 
 	req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
 	rec := httptest.NewRecorder()
@@ -607,13 +638,14 @@ func TestGetPvzHandler_ServiceError(t *testing.T) {
 	mockService.EXPECT().GetPvz(gomock.Any(), gomock.Any()).Return(nil, expectedErr)
 
 	err := handler.GetPvz(c)
-	assert.NoError(t, err)
+	require.Error(t, err)
+	c.Error(err)
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	assert.Contains(t, rec.Body.String(), expectedErr.Error())
+	assert.Contains(t, rec.Body.String(), "internal_error")
 }
 
 func TestCreatePVZHandler_InvalidRequest(t *testing.T) {
-	handler := NewPvzHandler(nil, nil, "8080")
+	handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
 
 	req := httptest.NewRequest(http.MethodPost, "/pvz", strings.NewReader(`{"invalid": "data"`))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -621,7 +653,8 @@ func TestCreatePVZHandler_InvalidRequest(t *testing.T) {
 	c := handler.e.NewContext(req, rec)
 
 	err := handler.CreatePVZ(c)
-	assert.NoError(t, err)
+	require.Error(t, err)
+	c.Error(err)
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
@@ -630,7 +663,7 @@ func TestDeleteLastProduct_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, nil, "8080")
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
 	pvzID := uuid.New()
 
 	req := httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/delete_last_product", nil)
@@ -639,7 +672,7 @@ func TestDeleteLastProduct_Success(t *testing.T) {
 	c.SetParamNames("pvzId")
 	c.SetParamValues(pvzID.String())
 
-	mockService.EXPECT().DeleteLastProduct(gomock.Any(), pvzID).Return(nil)
+	mockService.EXPECT().DeleteLastProduct(gomock.Any(), pvzID, uuid.Nil).Return(nil)
 
 	err := handler.DeleteLastProduct(c)
 	assert.NoError(t, err)
@@ -647,7 +680,7 @@ func TestDeleteLastProduct_Success(t *testing.T) {
 }
 
 func TestCreateReception_InvalidUUID(t *testing.T) {
-	handler := NewPvzHandler(nil, nil, "8080")
+	handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
 
 	reqBody := `{"pvzId": "invalid-uuid"}`
 	req := httptest.NewRequest(http.MethodPost, "/receptions", strings.NewReader(reqBody))
@@ -656,7 +689,8 @@ func TestCreateReception_InvalidUUID(t *testing.T) {
 	c := handler.e.NewContext(req, rec)
 
 	err := handler.CreateReception(c)
-	assert.NoError(t, err)
+	require.Error(t, err)
+	c.Error(err)
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
@@ -665,13 +699,13 @@ func TestGetPvzHandler_MaxLimit(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, nil, "8080")
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
 
 	req := httptest.NewRequest(http.MethodGet, "/pvz?limit=100", nil)
 	rec := httptest.NewRecorder()
 	c := handler.e.NewContext(req, rec)
 
-	mockService.EXPECT().GetPvz(gomock.Any(), gomock.Any()).Return([]*dto.PVZWithReceptions{}, nil)
+	mockService.EXPECT().GetPvz(gomock.Any(), gomock.Any()).Return(&dto.GetPvzResponse{Items: []*dto.PVZWithReceptions{}}, nil)
 
 	err := handler.GetPvz(c)
 	assert.NoError(t, err)
@@ -686,7 +720,7 @@ func TestRoleMiddleware_AllRoles(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			handler := NewPvzHandler(nil, nil, "8080")
+			handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
 			e := echo.New()
 
 			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -703,6 +737,7 @@ func TestRoleMiddleware_AllRoles(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodGet, "/test", nil)
 			rec := httptest.NewRecorder()
+			e.HTTPErrorHandler = handler.HTTPErrorHandler
 			e.ServeHTTP(rec, req)
 
 			assert.Equal(t, http.StatusOK, rec.Code)
@@ -715,9 +750,9 @@ func TestGetPvzHandler_ValidRequest(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, nil, "8080")
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
 
-	expected := []*dto.PVZWithReceptions{{PVZ: dto.PVZResponse{City: "Москва"}}}
+	expected := &dto.GetPvzResponse{Items: []*dto.PVZWithReceptions{{PVZ: dto.PVZResponse{City: "Москва"}}}}
 	mockService.EXPECT().GetPvz(gomock.Any(), gomock.Any()).Return(expected, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/pvz?page=1&limit=10", nil)
@@ -736,7 +771,7 @@ func TestCreateReceptionHandler(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, nil, "8080")
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
 
 	pvzID := uuid.New()
 	currentTime := time.Now().UTC()
@@ -765,20 +800,20 @@ func TestCreateReceptionHandler(t *testing.T) {
 			name:        "Invalid UUID format",
 			requestBody: `{"pvzId":"invalid-uuid"}`,
 			expectCode:  http.StatusBadRequest,
-			expectBody:  "Invalid request",
+			expectBody:  "invalid_request",
 		},
 		{
 			name:        "Service error",
 			requestBody: `{"pvzId":"` + pvzID.String() + `"}`,
-			mockError:   errors.New("database connection failed"),
-			expectCode:  http.StatusBadRequest,
-			expectBody:  "database connection failed",
+			mockError:   repository.ErrPVZNotFound,
+			expectCode:  http.StatusNotFound,
+			expectBody:  `"code":"pvz_not_found"`,
 		},
 		{
 			name:        "Invalid request body",
 			requestBody: `{"invalid": "data"`,
 			expectCode:  http.StatusBadRequest,
-			expectBody:  "Invalid request",
+			expectBody:  "invalid_request",
 		},
 	}
 
@@ -793,8 +828,9 @@ func TestCreateReceptionHandler(t *testing.T) {
 				mockService.EXPECT().CreateReception(gomock.Any(), gomock.Any()).Return(tt.mockResponse, tt.mockError)
 			}
 
-			err := handler.CreateReception(c)
-			assert.NoError(t, err)
+			if err := handler.CreateReception(c); err != nil {
+				c.Error(err)
+			}
 			assert.Equal(t, tt.expectCode, rec.Code)
 			assert.Contains(t, rec.Body.String(), tt.expectBody)
 		})
@@ -806,7 +842,7 @@ func TestLoginHandler_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, nil, "8080")
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
 
 	reqBody := `{"email":"test@test.com","password":"correct"}`
 	expectedToken := "valid.token.123"
@@ -830,7 +866,7 @@ func TestAddProduct_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, nil, "8080")
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
 
 	pvzID := uuid.New()
 	reqBody := `{"pvzId":"` + pvzID.String() + `","type":"electronics"}`
@@ -850,7 +886,7 @@ func TestAddProduct_Success(t *testing.T) {
 }
 
 func TestAddProduct_InvalidJSON(t *testing.T) {
-	handler := NewPvzHandler(nil, nil, "8080")
+	handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
 
 	req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader("{invalid json"))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -858,9 +894,10 @@ func TestAddProduct_InvalidJSON(t *testing.T) {
 	c := handler.e.NewContext(req, rec)
 
 	err := handler.AddProduct(c)
-	assert.NoError(t, err)
+	require.Error(t, err)
+	c.Error(err)
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), "Invalid request")
+	assert.Contains(t, rec.Body.String(), "invalid_request")
 }
 
 func TestPvzHandler_DummyLogin(t *testing.T) {
@@ -868,7 +905,7 @@ func TestPvzHandler_DummyLogin(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := mocks.NewMockPvzService(ctrl)
-	handler := NewPvzHandler(mockService, nil, "8080")
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
 
 	t.Run("Success", func(t *testing.T) {
 		expectedToken := "test.token"
@@ -887,16 +924,17 @@ func TestPvzHandler_DummyLogin(t *testing.T) {
 		assert.Contains(t, rec.Body.String(), expectedToken)
 	})
 
-	t.Run("Invalid request", func(t *testing.T) {
+	t.Run("invalid_request", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/dummyLogin", strings.NewReader("{invalid"))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
 		c := handler.e.NewContext(req, rec)
 
 		err := handler.DummyLogin(c)
-		assert.NoError(t, err)
+		require.Error(t, err)
+		c.Error(err)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Contains(t, rec.Body.String(), "Invalid request")
+		assert.Contains(t, rec.Body.String(), "invalid_request")
 	})
 
 	t.Run("Service error", func(t *testing.T) {
@@ -911,8 +949,1008 @@ func TestPvzHandler_DummyLogin(t *testing.T) {
 		c := handler.e.NewContext(req, rec)
 
 		err := handler.DummyLogin(c)
-		assert.NoError(t, err)
+		require.Error(t, err)
+		c.Error(err)
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
-		assert.Contains(t, rec.Body.String(), expectedErr.Error())
+		assert.NotContains(t, rec.Body.String(), expectedErr.Error())
+	})
+}
+
+func TestGetProductLabelHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	productID := uuid.New()
+
+	newContext := func(id, format string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/products/"+id+"/label?format="+format, nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		c.SetParamNames("productId")
+		c.SetParamValues(id)
+		return c, rec
+	}
+
+	t.Run("success", func(t *testing.T) {
+		c, rec := newContext(productID.String(), "zpl")
+		mockService.EXPECT().GetProductLabel(gomock.Any(), productID, "zpl").Return(&dto.LabelResponse{
+			ContentType: "application/x-zpl",
+			FileName:    "label.zpl",
+			Data:        []byte("^XA^XZ"),
+		}, nil)
+
+		err := handler.GetProductLabel(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-zpl", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "label.zpl")
+		assert.Equal(t, "^XA^XZ", rec.Body.String())
+	})
+
+	t.Run("invalid uuid", func(t *testing.T) {
+		c, rec := newContext("invalid", "zpl")
+
+		err := handler.GetProductLabel(c)
+		require.Error(t, err)
+		c.Error(err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		c, rec := newContext(productID.String(), "png")
+		mockService.EXPECT().GetProductLabel(gomock.Any(), productID, "png").Return(nil, repository.ErrProductNotFound)
+
+		err := handler.GetProductLabel(c)
+		require.Error(t, err)
+		c.Error(err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestGetReceptionLabelsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	receptionID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/receptions/"+receptionID.String()+"/labels?format=png", nil)
+	rec := httptest.NewRecorder()
+	c := handler.e.NewContext(req, rec)
+	c.SetParamNames("receptionId")
+	c.SetParamValues(receptionID.String())
+
+	mockService.EXPECT().GetReceptionLabels(gomock.Any(), receptionID, "png").Return(nil, label.ErrInvalidFormat)
+
+	err := handler.GetReceptionLabels(c)
+	require.Error(t, err)
+	c.Error(err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetProductHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	productID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/products/"+productID.String(), nil)
+	rec := httptest.NewRecorder()
+	c := handler.e.NewContext(req, rec)
+	c.SetParamNames("productId")
+	c.SetParamValues(productID.String())
+
+	mockService.EXPECT().GetProduct(gomock.Any(), productID).Return(&dto.ProductDetailsResponse{
+		Product: dto.ProductResponse{Id: productID},
+		PVZ:     dto.PVZResponse{City: "Москва"},
+		Status:  "accepted",
+		Timeline: []dto.ProductEventResponse{
+			{Type: "added"},
+		},
+	}, nil)
+
+	err := handler.GetProduct(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"accepted"`)
+	assert.Contains(t, rec.Body.String(), `"type":"added"`)
+}
+
+func TestFindProductHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+
+	t.Run("not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products?barcode=87c1752999bb4815be06900c4612902a", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+
+		mockService.EXPECT().
+			FindProduct(gomock.Any(), &dto.GetProductRequest{Barcode: "87c1752999bb4815be06900c4612902a"}).
+			Return(nil, repository.ErrProductNotFound)
+
+		err := handler.FindProduct(c)
+		require.Error(t, err)
+		c.Error(err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("invalid barcode", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/products?barcode=abc", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+
+		mockService.EXPECT().FindProduct(gomock.Any(), gomock.Any()).
+			Return(nil, &controller.FieldError{Field: "barcode", Err: controller.ErrInvalidBarcode})
+
+		err := handler.FindProduct(c)
+		require.Error(t, err)
+		c.Error(err)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var problem dto.ProblemResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, "invalid_barcode", problem.Code)
+		assert.Equal(t, []dto.InvalidParam{{Name: "barcode", Code: "invalid_barcode", Reason: controller.ErrInvalidBarcode.Error()}}, problem.InvalidParams)
+	})
+}
+
+func TestMoveProductHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	productID, pvzID := uuid.New(), uuid.New()
+
+	newContext := func() (echo.Context, *httptest.ResponseRecorder) {
+		body := `{"pvzId":"` + pvzID.String() + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/move", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		c.SetParamNames("productId")
+		c.SetParamValues(productID.String())
+		return c, rec
+	}
+
+	t.Run("moved", func(t *testing.T) {
+		c, rec := newContext()
+		mockService.EXPECT().MoveProduct(gomock.Any(), productID, &dto.MoveProductRequest{PvzId: pvzID}, gomock.Any()).
+			Return(&dto.ProductDetailsResponse{
+				Product:  dto.ProductResponse{Id: productID},
+				Status:   "moved",
+				Timeline: []dto.ProductEventResponse{{Type: "added"}, {Type: "moved", PvzId: &pvzID}},
+			}, nil)
+
+		require.NoError(t, handler.MoveProduct(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"moved"`)
+		assert.Contains(t, rec.Body.String(), `"pvzId":"`+pvzID.String()+`"`)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		c, rec := newContext()
+		mockService.EXPECT().MoveProduct(gomock.Any(), productID, gomock.Any(), gomock.Any()).
+			Return(nil, controller.ErrProductNotAvailable)
+
+		err := handler.MoveProduct(c)
+		require.Error(t, err)
+		c.Error(err)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		var problem dto.ProblemResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, "product_not_available", problem.Code)
+	})
+}
+
+func TestUndoDeleteLastProductHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	pvzID := uuid.New()
+	userID := uuid.New()
+
+	newContext := func() (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID.String()+"/undo_delete_last_product", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		c.SetParamNames("pvzId")
+		c.SetParamValues(pvzID.String())
+		c.Set("user", &models.User{Id: userID, Role: models.RoleEmployee})
+		return c, rec
+	}
+
+	t.Run("success", func(t *testing.T) {
+		c, rec := newContext()
+		mockService.EXPECT().
+			RestoreLastProduct(gomock.Any(), pvzID, userID).
+			Return(&dto.ProductResponse{Type: "обувь"}, nil)
+
+		err := handler.UndoDeleteLastProduct(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"type":"обувь"`)
+	})
+
+	t.Run("nothing to restore", func(t *testing.T) {
+		c, rec := newContext()
+		mockService.EXPECT().
+			RestoreLastProduct(gomock.Any(), pvzID, userID).
+			Return(nil, repository.ErrProductNotFound)
+
+		err := handler.UndoDeleteLastProduct(c)
+		require.Error(t, err)
+		c.Error(err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestGetPvzHandler_IncludeDeletedForbidden(t *testing.T) {
+	handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/pvz?includeDeleted=true", nil)
+	rec := httptest.NewRecorder()
+	c := handler.e.NewContext(req, rec)
+	c.Set("user", &models.User{Id: uuid.New(), Role: models.RoleEmployee})
+
+	err := handler.GetPvz(c)
+	require.Error(t, err)
+	c.Error(err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestGetPvzHandler_Cursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/pvz?cursor=abc&limit=5", nil)
+	rec := httptest.NewRecorder()
+	c := handler.e.NewContext(req, rec)
+
+	expected := &dto.GetPvzResponse{
+		Items:      []*dto.PVZWithReceptions{{PVZ: dto.PVZResponse{City: "Казань"}}},
+		NextCursor: "def",
+	}
+	mockService.EXPECT().
+		GetPvz(gomock.Any(), &dto.GetPvzRequest{Cursor: "abc", Limit: 5}).
+		Return(expected, nil)
+
+	err := handler.GetPvz(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"nextCursor":"def"`)
+}
+
+func TestGetPvzHandler_BindsFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+
+	query := url.Values{}
+	query.Add("city", "Москва")
+	query.Add("city", "Казань")
+	query.Set("receptionStatus", "in_progress")
+	query.Set("productType", "обувь")
+	query.Set("hasOpenReception", "true")
+	query.Set("registeredFrom", "2025-01-01T00:00:00Z")
+	req := httptest.NewRequest(http.MethodGet, "/pvz?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+	c := handler.e.NewContext(req, rec)
+
+	mockService.EXPECT().
+		GetPvz(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *dto.GetPvzRequest) (*dto.GetPvzResponse, error) {
+			assert.Equal(t, []string{"Москва", "Казань"}, request.Cities)
+			assert.Equal(t, "in_progress", request.ReceptionStatus)
+			assert.Equal(t, "обувь", request.ProductType)
+			require.NotNil(t, request.HasOpenReception)
+			assert.True(t, *request.HasOpenReception)
+			assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), request.RegisteredFrom.UTC())
+			return &dto.GetPvzResponse{Items: []*dto.PVZWithReceptions{}}, nil
+		})
+
+	err := handler.GetPvz(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGetPvzHandler_Envelope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	response := &dto.GetPvzResponse{
+		Items: []*dto.PVZWithReceptions{{PVZ: dto.PVZResponse{City: "Москва"}}},
+		Total: 25,
+		Page:  2,
+		Limit: 10,
+	}
+
+	t.Run("page links", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/pvz?page=2&limit=10&city=Москва", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		mockService.EXPECT().GetPvz(gomock.Any(), gomock.Any()).Return(response, nil)
+
+		err := handler.GetPvz(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"total":25`)
+		assert.Contains(t, rec.Body.String(), `"page":2`)
+
+		link := rec.Header().Get("Link")
+		assert.Contains(t, link, `</pvz?city=%D0%9C%D0%BE%D1%81%D0%BA%D0%B2%D0%B0&limit=10&page=3>; rel="next"`)
+		assert.Contains(t, link, `&page=1>; rel="prev"`)
+		assert.Contains(t, link, `rel="first"`)
+	})
+
+	t.Run("cursor links", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/pvz?cursor=abc", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		mockService.EXPECT().GetPvz(gomock.Any(), gomock.Any()).Return(&dto.GetPvzResponse{
+			Items:      []*dto.PVZWithReceptions{},
+			Limit:      10,
+			Cursor:     "abc",
+			NextCursor: "next",
+			PrevCursor: "prev",
+		}, nil)
+
+		err := handler.GetPvz(c)
+		assert.NoError(t, err)
+		assert.Equal(t, `</pvz?cursor=next>; rel="next", </pvz?cursor=prev>; rel="prev", </pvz>; rel="first"`, rec.Header().Get("Link"))
+	})
+
+	t.Run("legacy shape", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/pvz?page=2", nil)
+		req.Header.Set(echo.HeaderAccept, legacyListMediaType)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		mockService.EXPECT().GetPvz(gomock.Any(), gomock.Any()).Return(response, nil)
+
+		err := handler.GetPvz(c)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(rec.Body.String(), `[{"pvz"`))
+	})
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	handler := NewPvzHandler(nil, nil, "8080", APIConfig{})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantParams []dto.InvalidParam
+	}{
+		{
+			name:       "wrapped validation error",
+			err:        fmt.Errorf("validation failed: %w", &controller.FieldError{Field: "limit", Err: controller.ErrInvalidLimit}),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_limit",
+			wantParams: []dto.InvalidParam{{Name: "limit", Code: "invalid_limit", Reason: controller.ErrInvalidLimit.Error()}},
+		},
+		{
+			name:       "no active reception",
+			err:        fmt.Errorf("no active reception: %w", repository.ErrNoActiveReception),
+			wantStatus: http.StatusConflict,
+			wantCode:   "no_active_reception",
+		},
+		{
+			name:       "expired token",
+			err:        fmt.Errorf("%w: %w", ErrInvalidToken, auth.ErrTokenExpired),
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "token_expired",
+		},
+		{
+			name:       "echo error",
+			err:        echo.ErrMethodNotAllowed,
+			wantStatus: http.StatusMethodNotAllowed,
+			wantCode:   "method_not_allowed",
+		},
+		{
+			name:       "unknown error",
+			err:        errors.New("pq: connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+			rec := httptest.NewRecorder()
+			handler.HTTPErrorHandler(tt.err, handler.e.NewContext(req, rec))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, problemContentType, rec.Header().Get(echo.HeaderContentType))
+
+			var problem dto.ProblemResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.Equal(t, tt.wantStatus, problem.Status)
+			assert.Equal(t, tt.wantCode, problem.Code)
+			assert.Equal(t, problemTypePrefix+tt.wantCode, problem.Type)
+			assert.Equal(t, "/pvz", problem.Instance)
+			assert.Equal(t, tt.wantParams, problem.InvalidParams)
+			assert.NotContains(t, rec.Body.String(), "connection refused")
+		})
+	}
+}
+
+func TestRegisterRoutes_Versions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	mockAuth := mocks.NewMockAuthService(ctrl)
+	deprecation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	handler := NewPvzHandler(mockService, mockAuth, "8080", APIConfig{
+		V1Deprecation: deprecation,
+		V1Sunset:      sunset,
+		V1SunsetLink:  "https://pvz.example/migration",
 	})
+	RegisterRoutes(handler)
+
+	mockAuth.EXPECT().ParseToken("token").Return(&models.User{Role: models.RoleEmployee}, nil).AnyTimes()
+	mockService.EXPECT().GetPvz(gomock.Any(), gomock.Any()).
+		Return(&dto.GetPvzResponse{Items: []*dto.PVZWithReceptions{{PVZ: dto.PVZResponse{City: "Москва"}}}, Limit: 10}, nil).
+		AnyTimes()
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(authorizationHeader, "Bearer token")
+		req.Header.Set(echo.HeaderAccept, legacyListMediaType)
+		rec := httptest.NewRecorder()
+		handler.GetEcho().ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, path := range []string{"/pvz", "/api/v1/pvz"} {
+		rec := serve(path)
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Equal(t, "@1767225600", rec.Header().Get(deprecationHeader), path)
+		assert.Equal(t, "Wed, 01 Jul 2026 00:00:00 GMT", rec.Header().Get(sunsetHeader), path)
+		assert.Contains(t, rec.Header().Values(linkHeader), `<https://pvz.example/migration>; rel="sunset"`, path)
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "["), path)
+	}
+
+	rec := serve("/api/v2/pvz")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(deprecationHeader))
+	assert.Empty(t, rec.Header().Get(sunsetHeader))
+	assert.Contains(t, rec.Body.String(), `"items":[`)
+
+	rec = serve("/api/v3/pvz")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, problemContentType, rec.Header().Get(echo.HeaderContentType))
+}
+
+func TestOpenAPIValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	mockAuth := mocks.NewMockAuthService(ctrl)
+	handler := NewPvzHandler(mockService, mockAuth, "8080", APIConfig{ValidateResponses: true})
+	RegisterRoutes(handler)
+	mockAuth.EXPECT().ParseToken("token").Return(&models.User{Role: models.RoleModerator}, nil).AnyTimes()
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(authorizationHeader, "Bearer token")
+		if body != "" {
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		}
+		rec := httptest.NewRecorder()
+		handler.GetEcho().ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("invalid requests", func(t *testing.T) {
+		tests := []struct {
+			method, path, body string
+			wantParams         []string
+		}{
+			{http.MethodPost, "/api/v2/products", `{"type":"мебель"}`, []string{"type", "pvzId"}},
+			{http.MethodGet, "/api/v2/pvz?limit=many", "", []string{"limit"}},
+			{http.MethodPost, "/pvz", `{"city":"Тверь"}`, []string{"city"}},
+			{http.MethodPost, "/api/v1/products", `{"pvzId":"not-a-uuid","type":"обувь"}`, []string{"pvzId"}},
+		}
+		for _, tt := range tests {
+			rec := serve(tt.method, tt.path, tt.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, tt.path)
+
+			var problem dto.ProblemResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+			assert.Equal(t, "request_validation_failed", problem.Code)
+			var names []string
+			for _, param := range problem.InvalidParams {
+				names = append(names, param.Name)
+			}
+			assert.Equal(t, tt.wantParams, names, tt.path)
+		}
+	})
+
+	t.Run("response validation", func(t *testing.T) {
+		logger, hook := logrustest.NewNullLogger()
+		logrus.StandardLogger().ReplaceHooks(logger.Hooks)
+		logrus.AddHook(hook)
+		defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+
+		mockService.EXPECT().CreatePVZ(gomock.Any(), gomock.Any()).
+			Return(&dto.PvzCreateResponse{Id: uuid.New(), RegistrationDate: time.Now(), City: "Москва"}, nil)
+		rec := serve(http.MethodPost, "/api/v2/pvz", `{"city":"Москва"}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, hook.AllEntries())
+
+		mockService.EXPECT().CreatePVZ(gomock.Any(), gomock.Any()).
+			Return(&dto.PvzCreateResponse{Id: uuid.New(), RegistrationDate: time.Now(), City: "Тверь"}, nil)
+		rec = serve(http.MethodPost, "/api/v2/pvz", `{"city":"Казань"}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	})
+
+	t.Run("streamed responses are not recorded", func(t *testing.T) {
+		for contentType, recorded := range map[string]bool{
+			echo.MIMEApplicationJSON:  true,
+			problemContentType:        true,
+			"text/csv; charset=utf-8": false,
+			eventStreamContentType:    false,
+		} {
+			rec := httptest.NewRecorder()
+			recorder := &jsonRecorder{ResponseWriter: rec}
+			recorder.Header().Set(echo.HeaderContentType, contentType)
+			recorder.WriteHeader(http.StatusOK)
+			_, err := recorder.Write([]byte("body"))
+			require.NoError(t, err)
+
+			assert.Equal(t, "body", rec.Body.String(), contentType)
+			assert.Equal(t, !recorded, recorder.skipped, contentType)
+			assert.Equal(t, recorded, recorder.body.Len() > 0, contentType)
+		}
+	})
+
+	t.Run("docs", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/api/docs", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), docsSpecPath)
+
+		rec = serve(http.MethodGet, docsSpecPath, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "openapi: 3.0.0")
+	})
+}
+
+func TestGraphQL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	mockAuth := mocks.NewMockAuthService(ctrl)
+	handler := NewPvzHandler(mockService, mockAuth, "8080", APIConfig{})
+	RegisterRoutes(handler)
+	user := &models.User{Id: uuid.New(), Email: "user@example.com", Role: models.RoleEmployee}
+	mockAuth.EXPECT().ParseToken("token").Return(user, nil).AnyTimes()
+
+	serve := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, graphQLPath, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if token != "" {
+			req.Header.Set(authorizationHeader, "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.GetEcho().ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("requires token", func(t *testing.T) {
+		rec := serve("", `{"query":"{ me { id } }"}`)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"missing_token"`)
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		rec := serve("token", `{}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("me", func(t *testing.T) {
+		rec := serve("token", `{"query":"{ me { email role } }"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data":{"me":{"email":"user@example.com","role":"employee"}}}`, rec.Body.String())
+	})
+
+	t.Run("resolver errors carry problem codes", func(t *testing.T) {
+		pvzId := uuid.New()
+		mockService.EXPECT().CloseReception(gomock.Any(), pvzId).Return(nil, repository.ErrNoActiveReception)
+		mockService.EXPECT().RestoreLastProduct(gomock.Any(), pvzId, user.Id).Return(nil, errors.New("connection reset"))
+
+		rec := serve("token", `{"query":"mutation { closeLastReception(pvzId: \"`+pvzId.String()+`\") { id } }"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"extensions":{"code":"no_active_reception","status":409}`)
+
+		rec = serve("token", `{"query":"mutation { restoreLastProduct(pvzId: \"`+pvzId.String()+`\") { id } }"}`)
+		assert.Contains(t, rec.Body.String(), `"message":"Internal Server Error"`)
+		assert.NotContains(t, rec.Body.String(), "connection reset")
+	})
+}
+
+func TestStreamEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	mockAuth := mocks.NewMockAuthService(ctrl)
+	handler := NewPvzHandler(mockService, mockAuth, "8080", APIConfig{EventsHeartbeat: 10 * time.Millisecond})
+	RegisterRoutes(handler)
+	mockAuth.EXPECT().ParseToken("employee").Return(&models.User{Role: models.RoleEmployee}, nil).AnyTimes()
+
+	broker := events.NewBroker(events.Config{BufferSize: 2})
+	pvzId := uuid.New()
+	userId := uuid.New()
+	for range 3 {
+		broker.Publish(events.Event{Type: events.ProductAdded, PvzId: pvzId})
+	}
+
+	serve := func(ctx context.Context, path, lastEventId string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx)
+		req.Header.Set(authorizationHeader, "Bearer employee")
+		if lastEventId != "" {
+			req.Header.Set(lastEventIdHeader, lastEventId)
+		}
+		rec := httptest.NewRecorder()
+		handler.GetEcho().ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("invalid type", func(t *testing.T) {
+		rec := serve(context.Background(), "/api/v2/events/stream?pvzId="+pvzId.String()+"&type=unknown", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("resumes and streams", func(t *testing.T) {
+		mockService.EXPECT().SubscribeEvents(gomock.Any()).
+			DoAndReturn(func(request *dto.EventStreamRequest) (*events.Subscription, error) {
+				assert.Equal(t, []string{pvzId.String()}, request.PvzIds)
+				assert.Equal(t, "0", request.LastEventId)
+				after := uint64(0)
+				sub := broker.Subscribe(events.Filter{PvzIds: []uuid.UUID{pvzId}}, &after)
+				broker.Publish(events.Event{Type: events.ProductDeleted, PvzId: pvzId, UserId: &userId})
+				return sub, nil
+			})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		rec := serve(ctx, "/api/v2/events/stream?pvzId="+pvzId.String(), "0")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, eventStreamContentType, rec.Header().Get(echo.HeaderContentType))
+		body := rec.Body.String()
+		assert.True(t, strings.HasPrefix(body, "id: 1\nevent: stream.reset\n"), body)
+		assert.Contains(t, body, "id: 2\nevent: product.added\n")
+		assert.Contains(t, body, "id: 3\nevent: product.added\n")
+		assert.Contains(t, body, "id: 4\nevent: product.deleted\n")
+		assert.Contains(t, body, ": heartbeat\n\n")
+		assert.NotContains(t, body, userId.String())
+	})
+
+	t.Run("ends on shutdown", func(t *testing.T) {
+		mockService.EXPECT().SubscribeEvents(gomock.Any()).
+			DoAndReturn(func(request *dto.EventStreamRequest) (*events.Subscription, error) {
+				assert.Empty(t, request.PvzIds)
+				return broker.Subscribe(events.Filter{}, nil), nil
+			})
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- serve(context.Background(), "/api/v2/events/stream", "") }()
+		time.Sleep(20 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, handler.Close(ctx))
+		select {
+		case rec := <-done:
+			assert.Equal(t, http.StatusOK, rec.Code)
+		case <-ctx.Done():
+			t.Fatal("stream did not end on shutdown")
+		}
+	})
+}
+
+func TestWebhookHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	userID := uuid.New()
+	webhookID := uuid.New()
+
+	t.Run("create does not echo the secret", func(t *testing.T) {
+		body := `{"url":"https://example.com/hook","eventTypes":["product.added"],"secret":"0123456789abcdef"}`
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		c.Set("user", &models.User{Id: userID, Role: models.RoleModerator})
+
+		mockService.EXPECT().
+			CreateWebhook(gomock.Any(), &dto.WebhookRequest{URL: "https://example.com/hook", EventTypes: []string{"product.added"}, Secret: "0123456789abcdef"}, userID).
+			Return(&dto.WebhookResponse{Id: webhookID, URL: "https://example.com/hook", EventTypes: []string{"product.added"}}, nil)
+
+		assert.NoError(t, handler.CreateWebhook(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "0123456789abcdef")
+	})
+
+	t.Run("delete missing", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/webhooks/"+webhookID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		c.SetParamNames("webhookId")
+		c.SetParamValues(webhookID.String())
+
+		mockService.EXPECT().DeleteWebhook(gomock.Any(), webhookID).Return(repository.ErrWebhookNotFound)
+
+		err := handler.DeleteWebhook(c)
+		require.Error(t, err)
+		c.Error(err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("redeliver", func(t *testing.T) {
+		deliveryID := uuid.New()
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/deliveries/"+deliveryID.String()+"/redeliver", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		c.SetParamNames("deliveryId")
+		c.SetParamValues(deliveryID.String())
+
+		mockService.EXPECT().RedeliverWebhook(gomock.Any(), deliveryID).
+			Return(&dto.WebhookDeliveryResponse{Id: deliveryID, Status: "pending"}, nil)
+
+		assert.NoError(t, handler.RedeliverWebhook(c))
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"pending"`)
+	})
+
+	t.Run("invalid delivery id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/deliveries/bad/redeliver", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		c.SetParamNames("deliveryId")
+		c.SetParamValues("bad")
+
+		assert.ErrorIs(t, handler.RedeliverWebhook(c), ErrInvalidDeliveryId)
+	})
+}
+
+func TestExportReceptionsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	productType := "одежда"
+	productID := uuid.New()
+	productTime := time.Now().UTC()
+	row := dto.ReceptionReportRow{
+		PvzId: uuid.New(), City: "Москва", ReceptionId: uuid.New(), ReceptionDateTime: productTime, ReceptionStatus: "close",
+		ProductId: &productID, ProductDateTime: &productTime, ProductType: &productType,
+	}
+	stream := func(_ context.Context, _ *dto.ReceptionReportRequest, fn func(dto.ReceptionReportRow) error) error {
+		return fn(row)
+	}
+
+	t.Run("csv with bom", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/reports/receptions.csv?bom=true&city=Москва", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+
+		mockService.EXPECT().
+			ExportReceptions(gomock.Any(), &dto.ReceptionReportRequest{Cities: []string{"Москва"}, BOM: true}, gomock.Any()).
+			DoAndReturn(stream)
+
+		assert.NoError(t, handler.ExportReceptionsCSV(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, csvContentType, rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "receptions.csv")
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "\ufeffpvz_id,city,"))
+		assert.Contains(t, rec.Body.String(), ",Москва,")
+		assert.Contains(t, rec.Body.String(), ",одежда\n")
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/reports/receptions.xlsx", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+
+		mockService.EXPECT().ExportReceptions(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(stream)
+
+		assert.NoError(t, handler.ExportReceptionsXLSX(c))
+		assert.Equal(t, xlsxContentType, rec.Header().Get(echo.HeaderContentType))
+		assert.True(t, bytes.HasPrefix(rec.Body.Bytes(), []byte("PK")))
+	})
+
+	t.Run("empty report still has a header row", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/reports/receptions.csv", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+
+		mockService.EXPECT().ExportReceptions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		assert.NoError(t, handler.ExportReceptionsCSV(c))
+		assert.Equal(t, "pvz_id,city,reception_id,reception_date_time,reception_status,product_id,product_date_time,product_type\n", rec.Body.String())
+	})
+
+	t.Run("validation error before streaming", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/reports/receptions.csv?city=Тверь", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+
+		mockService.EXPECT().ExportReceptions(gomock.Any(), gomock.Any(), gomock.Any()).Return(controller.ErrInvalidCity)
+
+		err := handler.ExportReceptionsCSV(c)
+		require.Error(t, err)
+		c.Error(err)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, problemContentType, rec.Header().Get(echo.HeaderContentType))
+	})
+
+	t.Run("failure mid-stream aborts the response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/reports/receptions.csv", nil)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+
+		mockService.EXPECT().ExportReceptions(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *dto.ReceptionReportRequest, fn func(dto.ReceptionReportRow) error) error {
+				require.NoError(t, fn(row))
+				return errors.New("connection reset")
+			})
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() { _ = handler.ExportReceptionsCSV(c) })
+	})
+}
+
+func TestImportPvzHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	file := "city\nМосква\n"
+
+	newContext := func(target string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(file))
+		req.Header.Set(echo.HeaderContentType, "text/csv")
+		rec := httptest.NewRecorder()
+		return handler.e.NewContext(req, rec), rec
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		c, rec := newContext("/pvz/import?dryRun=true")
+		mockService.EXPECT().ImportPvz(gomock.Any(), gomock.Any(), &dto.PvzImportRequest{DryRun: true}).
+			Return(&dto.PvzImportResponse{DryRun: true, Created: 1}, nil)
+
+		assert.NoError(t, handler.ImportPvz(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"created":1`)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		c, rec := newContext("/pvz/import")
+		mockService.EXPECT().ImportPvz(gomock.Any(), gomock.Any(), &dto.PvzImportRequest{}).
+			Return(&dto.PvzImportResponse{Created: 1}, nil)
+
+		assert.NoError(t, handler.ImportPvz(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("row errors", func(t *testing.T) {
+		c, rec := newContext("/pvz/import")
+		mockService.EXPECT().ImportPvz(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, &controller.ImportError{Rows: []controller.RowError{
+				{Line: 2, Err: &controller.FieldError{Field: "city", Err: controller.ErrInvalidCity}},
+			}})
+
+		err := handler.ImportPvz(c)
+		require.Error(t, err)
+		c.Error(err)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var problem dto.ProblemResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, "invalid_import_rows", problem.Code)
+		assert.Equal(t, []dto.InvalidParam{{Name: "rows[2].city", Code: "invalid_city", Reason: "invalid city"}}, problem.InvalidParams)
+	})
+}
+
+func TestSyncHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	userID := uuid.New()
+	clientID := uuid.New()
+	pvzID := uuid.New()
+	clientTime := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	newContext := func(body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/sync", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		c.Set("user", &models.User{Id: userID, Role: models.RoleEmployee})
+		return c, rec
+	}
+	body := `{"operations":[{"clientId":"` + clientID.String() + `","clientTime":"2025-03-01T09:30:00Z","type":"close_reception","pvzId":"` + pvzID.String() + `"}]}`
+
+	t.Run("per-operation results", func(t *testing.T) {
+		c, rec := newContext(body)
+		mockService.EXPECT().
+			Sync(gomock.Any(), &dto.SyncRequest{Operations: []dto.SyncOperation{
+				{ClientId: clientID, ClientTime: clientTime, Type: "close_reception", PvzId: pvzID},
+			}}, userID).
+			Return(&dto.SyncResponse{Results: []dto.SyncResult{{ClientId: clientID, Status: "skipped", Reason: "already_closed"}}}, nil)
+
+		assert.NoError(t, handler.Sync(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"reason":"already_closed"`)
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		c, rec := newContext(body)
+		mockService.EXPECT().Sync(gomock.Any(), gomock.Any(), userID).
+			Return(nil, &controller.FieldError{Field: "operations[0].type", Err: controller.ErrInvalidSyncOperation})
+
+		err := handler.Sync(c)
+		require.Error(t, err)
+		c.Error(err)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"name":"operations[0].type"`)
+	})
+}
+
+func TestGetIntakeReportHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	period := "2025-03-01"
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/intake?groupBy=city&groupBy=month&city=Казань", nil)
+	rec := httptest.NewRecorder()
+	c := handler.e.NewContext(req, rec)
+
+	mockService.EXPECT().
+		GetIntakeReport(gomock.Any(), &dto.IntakeReportRequest{GroupBy: []string{"city", "month"}, Cities: []string{"Казань"}}).
+		Return(&dto.IntakeReportResponse{GroupBy: []string{"city", "month"}, Rows: []dto.IntakeReportRow{{Period: &period, Products: 5, Receptions: 1}}}, nil)
+
+	assert.NoError(t, handler.GetIntakeReport(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"period":"2025-03-01","products":5`)
+	assert.NotContains(t, rec.Body.String(), `"pvzId"`)
 }
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyReleaseTimeout = 5 * time.Second
+)
+
+type bodyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the flusher of the real writer.
+func (r *bodyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// IdempotencyMiddleware stores the first response to a POST sent with an
+// Idempotency-Key header and replays it to retries from the same user.
+// Server errors are not stored, so such a request can be retried for real.
+// Keys are scoped to the authenticated user, so anonymous requests such as
+// logins pass through untouched and issued tokens are never stored.
+func (h *PvzHandler) IdempotencyMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			const op = "internal.handler.IdempotencyMiddleware"
+			key := c.Request().Header.Get(idempotencyKeyHeader)
+			userId := currentUserId(c)
+			if key == "" || c.Request().Method != http.MethodPost || userId == uuid.Nil {
+				return next(c)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return ErrIdempotencyKeyTooLong
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return ErrInvalidRequest
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := sha256.New()
+			hash.Write([]byte(c.Request().Method + " " + c.Request().URL.Path + "?" + c.Request().URL.RawQuery + "\n"))
+			hash.Write(body)
+			record := dto.IdempotencyRecord{
+				UserId:      userId,
+				Key:         key,
+				RequestHash: hex.EncodeToString(hash.Sum(nil)),
+			}
+
+			stored, err := h.pvzService.ReserveIdempotencyKey(c.Request().Context(), record)
+			if err != nil {
+				return err
+			}
+			if stored != nil {
+				c.Response().Header().Set(idempotentReplayedHeader, "true")
+				return c.Blob(stored.StatusCode, stored.ContentType, stored.Body)
+			}
+
+			recorder := &bodyRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			// Errors are rendered here rather than by the router so that client
+			// errors are recorded and replayed like any other response.
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			c.Response().Writer = recorder.ResponseWriter
+
+			// The request context may already be cancelled by a dropped client,
+			// which is exactly the case the stored response is for.
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request().Context()), idempotencyReleaseTimeout)
+			defer cancel()
+
+			if !c.Response().Committed || c.Response().Status >= http.StatusInternalServerError {
+				if err := h.pvzService.ReleaseIdempotencyKey(ctx, record.UserId, record.Key); err != nil {
+					logrus.WithFields(logrus.Fields{"event": op}).Error(err)
+				}
+				return nil
+			}
+
+			record.StatusCode = c.Response().Status
+			record.ContentType = c.Response().Header().Get(echo.HeaderContentType)
+			record.Body = recorder.body.Bytes()
+			if err := h.pvzService.SaveIdempotencyResponse(ctx, record); err != nil {
+				logrus.WithFields(logrus.Fields{"event": op}).Error(err)
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/internal/controller"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/internal/repository"
+	"github.com/senorUVE/pvz_service/test/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPvzService(ctrl)
+	handler := NewPvzHandler(mockService, nil, "8080", APIConfig{})
+	userId := uuid.New()
+
+	respond := func(status int) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return c.JSON(status, map[string]string{"type": "обувь"})
+		}
+	}
+	serveTarget := func(target string, user *models.User, key string, next echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(idempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		c := handler.e.NewContext(req, rec)
+		if user != nil {
+			c.Set("user", user)
+		}
+
+		if err := handler.IdempotencyMiddleware()(next)(c); err != nil {
+			c.Error(err)
+		}
+		return rec
+	}
+	employee := &models.User{Id: userId, Role: models.RoleEmployee}
+	serve := func(key string, next echo.HandlerFunc, body string) *httptest.ResponseRecorder {
+		return serveTarget("/products", employee, key, next, body)
+	}
+
+	t.Run("without key", func(t *testing.T) {
+		rec := serve("", respond(http.StatusCreated), `{}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("first request is stored", func(t *testing.T) {
+		mockService.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ any, record dto.IdempotencyRecord) (*dto.IdempotencyRecord, error) {
+				assert.Equal(t, userId, record.UserId)
+				assert.Equal(t, "key-1", record.Key)
+				assert.Len(t, record.RequestHash, 64)
+				return nil, nil
+			})
+		mockService.EXPECT().SaveIdempotencyResponse(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ any, record dto.IdempotencyRecord) error {
+				assert.Equal(t, http.StatusCreated, record.StatusCode)
+				assert.Equal(t, echo.MIMEApplicationJSON, record.ContentType)
+				assert.JSONEq(t, `{"type":"обувь"}`, string(record.Body))
+				return nil
+			})
+
+		rec := serve("key-1", respond(http.StatusCreated), `{"type":"обувь"}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("retry is replayed", func(t *testing.T) {
+		mockService.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any()).Return(&dto.IdempotencyRecord{
+			StatusCode:  http.StatusCreated,
+			ContentType: echo.MIMEApplicationJSON,
+			Body:        []byte(`{"id":"stored"}`),
+		}, nil)
+
+		rec := serve("key-1", respond(http.StatusTeapot), `{"type":"обувь"}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, `{"id":"stored"}`, rec.Body.String())
+		assert.Equal(t, "true", rec.Header().Get(idempotentReplayedHeader))
+	})
+
+	t.Run("different body is rejected", func(t *testing.T) {
+		mockService.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil, controller.ErrIdempotencyKeyReused)
+
+		rec := serve("key-1", respond(http.StatusCreated), `{"type":"одежда"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("concurrent retry", func(t *testing.T) {
+		mockService.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil, controller.ErrIdempotencyKeyInProgress)
+
+		rec := serve("key-1", respond(http.StatusCreated), `{}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("returned client error is stored", func(t *testing.T) {
+		mockService.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil, nil)
+		mockService.EXPECT().SaveIdempotencyResponse(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ any, record dto.IdempotencyRecord) error {
+				assert.Equal(t, http.StatusConflict, record.StatusCode)
+				assert.Equal(t, problemContentType, record.ContentType)
+				assert.Contains(t, string(record.Body), `"code":"no_active_reception"`)
+				return nil
+			})
+
+		rec := serve("key-3", func(echo.Context) error { return repository.ErrNoActiveReception }, `{}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("query string is part of the request", func(t *testing.T) {
+		var hashes []string
+		mockService.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ any, record dto.IdempotencyRecord) (*dto.IdempotencyRecord, error) {
+				hashes = append(hashes, record.RequestHash)
+				return nil, nil
+			}).Times(2)
+		mockService.EXPECT().SaveIdempotencyResponse(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		serveTarget("/pvz/import?dryRun=true", employee, "key-4", respond(http.StatusOK), `[]`)
+		serveTarget("/pvz/import", employee, "key-4", respond(http.StatusOK), `[]`)
+		assert.NotEqual(t, hashes[0], hashes[1])
+	})
+
+	t.Run("anonymous request is not stored", func(t *testing.T) {
+		rec := serveTarget("/login", nil, "key-5", respond(http.StatusOK), `{}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("server error releases key", func(t *testing.T) {
+		mockService.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil, nil)
+		mockService.EXPECT().ReleaseIdempotencyKey(gomock.Any(), userId, "key-2").Return(nil)
+
+		rec := serve("key-2", respond(http.StatusInternalServerError), `{}`)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/api"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	docsPath     = "/api/docs"
+	docsSpecPath = docsPath + "/swagger.yml"
+)
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="utf-8">
+  <title>PVZ service API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({url: "` + docsSpecPath + `", dom_id: "#swagger-ui"});</script>
+</body>
+</html>`
+
+// specViolation lists every place where a request differs from the spec.
+type specViolation struct {
+	params []dto.InvalidParam
+}
+
+func (e *specViolation) Error() string {
+	reasons := make([]string, 0, len(e.params))
+	for _, param := range e.params {
+		reasons = append(reasons, param.Name+": "+param.Reason)
+	}
+	return fmt.Sprintf("%s: %s", ErrRequestValidation, strings.Join(reasons, "; "))
+}
+
+func (e *specViolation) Unwrap() error {
+	return ErrRequestValidation
+}
+
+// jsonRecorder keeps a copy of JSON responses only. Reports and event
+// streams pass straight through, so validation never buffers them.
+type jsonRecorder struct {
+	http.ResponseWriter
+	body    bytes.Buffer
+	skipped bool
+	decided bool
+}
+
+func (r *jsonRecorder) WriteHeader(code int) {
+	r.decide()
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *jsonRecorder) Write(b []byte) (int, error) {
+	r.decide()
+	if !r.skipped {
+		r.body.Write(b)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the flusher of the real writer.
+func (r *jsonRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// decide runs once the headers are final. Responses without a body have no
+// content type and are still checked for their status.
+func (r *jsonRecorder) decide() {
+	if r.decided {
+		return
+	}
+	r.decided = true
+	contentType := r.Header().Get(echo.HeaderContentType)
+	if contentType == "" {
+		return
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	r.skipped = err != nil || (mediaType != echo.MIMEApplicationJSON && !strings.HasSuffix(mediaType, "+json"))
+}
+
+type openAPIValidator struct {
+	router            routers.Router
+	validateResponses bool
+}
+
+func newOpenAPIValidator(doc *openapi3.T, validateResponses bool) (*openAPIValidator, error) {
+	// Paths are matched after the version prefix is cut off, so every
+	// version is checked against the same description.
+	doc.Servers = nil
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build openapi router: %w", err)
+	}
+	return &openAPIValidator{router: router, validateResponses: validateResponses}, nil
+}
+
+// Middleware validates requests under prefix against the spec. With
+// validateResponses it also checks the JSON that handlers send back and logs
+// mismatches, which is meant for development only.
+func (v *openAPIValidator) Middleware(prefix string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			const op = "internal.handler.OpenAPIValidator"
+			req := c.Request()
+			specReq := req.Clone(req.Context())
+			specReq.URL.Path = strings.TrimPrefix(req.URL.Path, prefix)
+			specReq.URL.RawPath = ""
+
+			route, pathParams, err := v.router.FindRoute(specReq)
+			if err != nil {
+				logrus.WithFields(logrus.Fields{"event": op, "path": c.Path()}).Warn("route is not described in the spec")
+				return next(c)
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    specReq,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					MultiError:          true,
+					SkipSettingDefaults: true,
+					AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+				},
+			}
+			err = openapi3filter.ValidateRequest(req.Context(), input)
+			req.Body = specReq.Body
+			if err != nil {
+				return &specViolation{params: violationParams(err)}
+			}
+
+			if !v.validateResponses {
+				return next(c)
+			}
+
+			recorder := &jsonRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			c.Response().Writer = recorder.ResponseWriter
+			if recorder.skipped {
+				return nil
+			}
+
+			output := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 c.Response().Status,
+				Header:                 c.Response().Header(),
+				Options:                &openapi3filter.Options{MultiError: true, IncludeResponseStatus: true},
+			}
+			output.SetBodyBytes(recorder.body.Bytes())
+			if err := openapi3filter.ValidateResponse(req.Context(), output); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"event":  op,
+					"path":   c.Path(),
+					"status": c.Response().Status,
+				}).Error("response does not match the spec: ", err)
+			}
+			return nil
+		}
+	}
+}
+
+func violationParams(err error) []dto.InvalidParam {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		var params []dto.InvalidParam
+		for _, item := range multi {
+			params = append(params, violationParams(item)...)
+		}
+		return params
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return []dto.InvalidParam{bodyParam(err)}
+	}
+
+	if requestErr.Parameter != nil {
+		return []dto.InvalidParam{{
+			Name:   requestErr.Parameter.Name,
+			Code:   "invalid_parameter",
+			Reason: violationReason(requestErr),
+		}}
+	}
+
+	var schemaErrs openapi3.MultiError
+	if errors.As(requestErr.Err, &schemaErrs) {
+		params := make([]dto.InvalidParam, 0, len(schemaErrs))
+		for _, item := range schemaErrs {
+			params = append(params, bodyParam(item))
+		}
+		return params
+	}
+	return []dto.InvalidParam{bodyParam(requestErr)}
+}
+
+// bodyParam names a body violation after the JSON path of the offending
+// value, falling back to "body" when the spec error carries no path.
+func bodyParam(err error) dto.InvalidParam {
+	param := dto.InvalidParam{Name: "body", Code: "invalid_body", Reason: err.Error()}
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			param.Name = strings.Join(pointer, ".")
+		}
+		param.Reason = schemaErr.Reason
+	}
+	return param
+}
+
+func violationReason(err *openapi3filter.RequestError) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err.Err, &schemaErr) {
+		return schemaErr.Reason
+	}
+	if err.Err != nil {
+		return err.Err.Error()
+	}
+	return err.Reason
+}
+
+func (h *PvzHandler) SwaggerUI(c echo.Context) error {
+	return c.HTML(http.StatusOK, swaggerUIPage)
+}
+
+func (h *PvzHandler) OpenAPISpec(c echo.Context) error {
+	return c.Blob(http.StatusOK, "application/yaml", api.Spec)
+}
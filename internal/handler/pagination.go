@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+// legacyListMediaType lets clients written against the bare array response of
+// GET /pvz keep receiving it.
+const legacyListMediaType = "application/vnd.pvz.v1+json"
+
+func acceptsLegacyList(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), legacyListMediaType)
+}
+
+// paginationLinks builds an RFC 8288 Link header value. Page links are used in
+// page/limit mode and cursor links otherwise, keeping the other query params.
+func paginationLinks(requestURL *url.URL, response *dto.GetPvzResponse) string {
+	var links []string
+	link := func(rel string, set func(url.Values)) {
+		query := requestURL.Query()
+		query.Del("page")
+		query.Del("cursor")
+		set(query)
+		target := url.URL{Path: requestURL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel))
+	}
+	setPage := func(page int) func(url.Values) {
+		return func(query url.Values) { query.Set("page", strconv.Itoa(page)) }
+	}
+	setCursor := func(cursor string) func(url.Values) {
+		return func(query url.Values) { query.Set("cursor", cursor) }
+	}
+
+	if response.Page > 0 {
+		if response.Page*response.Limit < response.Total {
+			link("next", setPage(response.Page+1))
+		}
+		if response.Page > 1 {
+			link("prev", setPage(response.Page-1))
+		}
+		link("first", setPage(1))
+	} else {
+		if response.NextCursor != "" {
+			link("next", setCursor(response.NextCursor))
+		}
+		if response.PrevCursor != "" {
+			link("prev", setCursor(response.PrevCursor))
+		}
+		link("first", func(url.Values) {})
+	}
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/internal/auth"
+	"github.com/senorUVE/pvz_service/internal/controller"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/graph"
+	"github.com/senorUVE/pvz_service/internal/label"
+	"github.com/senorUVE/pvz_service/internal/pagination"
+	"github.com/senorUVE/pvz_service/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:pvz:problem:"
+)
+
+type problemKind struct {
+	err    error
+	status int
+	code   string
+}
+
+// problemKinds is matched in order with errors.Is, so more specific errors
+// must come before the ones they may be wrapped in.
+var problemKinds = []problemKind{
+	{ErrInvalidRequest, http.StatusBadRequest, "invalid_request"},
+	{ErrRequestValidation, http.StatusBadRequest, "request_validation_failed"},
+	{ErrInvalidPvzId, http.StatusBadRequest, "invalid_pvz_id"},
+	{ErrInvalidReceptionId, http.StatusBadRequest, "invalid_reception_id"},
+	{ErrInvalidProductId, http.StatusBadRequest, "invalid_product_id"},
+	{ErrInvalidWebhookId, http.StatusBadRequest, "invalid_webhook_id"},
+	{ErrInvalidDeliveryId, http.StatusBadRequest, "invalid_delivery_id"},
+	{ErrIdempotencyKeyTooLong, http.StatusBadRequest, "idempotency_key_too_long"},
+	{pagination.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor"},
+	{controller.ErrCursorWithPage, http.StatusBadRequest, "cursor_with_page"},
+	{controller.ErrCursorSortMismatch, http.StatusBadRequest, "cursor_sort_mismatch"},
+	{controller.ErrInvalidPage, http.StatusBadRequest, "invalid_page"},
+	{controller.ErrInvalidLimit, http.StatusBadRequest, "invalid_limit"},
+	{controller.ErrInvalidSort, http.StatusBadRequest, "invalid_sort"},
+	{controller.ErrInvalidOrder, http.StatusBadRequest, "invalid_order"},
+	{controller.ErrInvalidInclude, http.StatusBadRequest, "invalid_include"},
+	{label.ErrInvalidFormat, http.StatusBadRequest, "invalid_label_format"},
+	{controller.ErrInvalidEventType, http.StatusBadRequest, "invalid_event_type"},
+	{controller.ErrInvalidEventId, http.StatusBadRequest, "invalid_last_event_id"},
+	{controller.ErrInvalidDeliveryStatus, http.StatusBadRequest, "invalid_delivery_status"},
+	{controller.ErrInvalidImportFile, http.StatusBadRequest, "invalid_import_file"},
+	{controller.ErrInvalidGroupBy, http.StatusBadRequest, "invalid_group_by"},
+	{controller.ErrManyIntakePeriods, http.StatusBadRequest, "many_intake_periods"},
+
+	{ErrEmptyToken, http.StatusUnauthorized, "missing_token"},
+	{ErrInvalidAuthHeader, http.StatusUnauthorized, "invalid_auth_header"},
+	{auth.ErrTokenExpired, http.StatusUnauthorized, "token_expired"},
+	{ErrInvalidToken, http.StatusUnauthorized, "invalid_token"},
+	{ErrUserNotInContext, http.StatusUnauthorized, "unauthenticated"},
+	{graph.ErrUnauthenticated, http.StatusUnauthorized, "unauthenticated"},
+	{controller.ErrInvalidPasswd, http.StatusUnauthorized, "invalid_credentials"},
+
+	{ErrForbidden, http.StatusForbidden, "forbidden"},
+	{ErrIncludeDeletedForbidden, http.StatusForbidden, "include_deleted_forbidden"},
+	{graph.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{graph.ErrIncludeDeletedForbidden, http.StatusForbidden, "include_deleted_forbidden"},
+
+	{repository.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+	{repository.ErrPVZNotFound, http.StatusNotFound, "pvz_not_found"},
+	{repository.ErrReceptionNotFound, http.StatusNotFound, "reception_not_found"},
+	{repository.ErrProductNotFound, http.StatusNotFound, "product_not_found"},
+	{repository.ErrWebhookNotFound, http.StatusNotFound, "webhook_not_found"},
+	{repository.ErrWebhookDeliveryNotFound, http.StatusNotFound, "webhook_delivery_not_found"},
+
+	{repository.ErrUserExists, http.StatusConflict, "user_exists"},
+	{repository.ErrPvzExternalCodeExists, http.StatusConflict, "external_code_exists"},
+	{repository.ErrNoActiveReception, http.StatusConflict, "no_active_reception"},
+	{controller.ErrProductNotAvailable, http.StatusConflict, "product_not_available"},
+	{repository.ErrWebhookDeliveryPending, http.StatusConflict, "webhook_delivery_pending"},
+	{controller.ErrIdempotencyKeyInProgress, http.StatusConflict, "idempotency_key_in_progress"},
+
+	{controller.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused"},
+	{controller.ErrInvalidImportRows, http.StatusUnprocessableEntity, "invalid_import_rows"},
+	{controller.ErrEmptySyncOperations, http.StatusUnprocessableEntity, "empty_sync_operations"},
+	{controller.ErrTooManySyncOperations, http.StatusUnprocessableEntity, "too_many_sync_operations"},
+	{controller.ErrInvalidSyncOperation, http.StatusUnprocessableEntity, "invalid_sync_operation"},
+	{controller.ErrDuplicateClientId, http.StatusUnprocessableEntity, "duplicate_client_id"},
+	{controller.ErrTooManyImportRows, http.StatusUnprocessableEntity, "too_many_import_rows"},
+	{repository.ErrReceptionEmpty, http.StatusUnprocessableEntity, "reception_empty"},
+	{controller.ErrInvalidEmail, http.StatusUnprocessableEntity, "invalid_email"},
+	{controller.ErrShortPassword, http.StatusUnprocessableEntity, "short_password"},
+	{controller.ErrWeakPassword, http.StatusUnprocessableEntity, "weak_password"},
+	{controller.ErrInvalidRole, http.StatusUnprocessableEntity, "invalid_role"},
+	{controller.ErrInvalidCity, http.StatusUnprocessableEntity, "invalid_city"},
+	{controller.ErrInvalidStatus, http.StatusUnprocessableEntity, "invalid_status"},
+	{controller.ErrInvalidProductType, http.StatusUnprocessableEntity, "invalid_product_type"},
+	{controller.ErrInvalidUUID, http.StatusUnprocessableEntity, "invalid_uuid"},
+	{controller.ErrSamePvz, http.StatusUnprocessableEntity, "same_pvz"},
+	{controller.ErrInvalidBarcode, http.StatusUnprocessableEntity, "invalid_barcode"},
+	{controller.ErrFutureDate, http.StatusUnprocessableEntity, "future_date"},
+	{controller.ErrInvalidDateRange, http.StatusUnprocessableEntity, "invalid_date_range"},
+	{controller.ErrInvalidWebhookURL, http.StatusUnprocessableEntity, "invalid_webhook_url"},
+	{controller.ErrEmptyEventTypes, http.StatusUnprocessableEntity, "empty_event_types"},
+	{controller.ErrShortWebhookSecret, http.StatusUnprocessableEntity, "short_webhook_secret"},
+	{controller.ErrLongExternalCode, http.StatusUnprocessableEntity, "long_external_code"},
+	{controller.ErrInvalidDate, http.StatusUnprocessableEntity, "invalid_date"},
+}
+
+// HTTPErrorHandler renders every error returned by handlers and middlewares
+// as application/problem+json.
+func (h *PvzHandler) HTTPErrorHandler(err error, c echo.Context) {
+	const op = "internal.handler.HTTPErrorHandler"
+	if c.Response().Committed {
+		return
+	}
+
+	problem := toProblem(err)
+	problem.Instance = c.Request().URL.Path
+	if problem.Status >= http.StatusInternalServerError {
+		logrus.WithFields(logrus.Fields{"event": op, "path": problem.Instance}).Error(err)
+	}
+
+	var writeErr error
+	if c.Request().Method == http.MethodHead {
+		writeErr = c.NoContent(problem.Status)
+	} else {
+		body, _ := json.Marshal(problem)
+		writeErr = c.Blob(problem.Status, problemContentType, body)
+	}
+	if writeErr != nil {
+		logrus.WithFields(logrus.Fields{"event": op}).Error(writeErr)
+	}
+}
+
+func toProblem(err error) dto.ProblemResponse {
+	for _, kind := range problemKinds {
+		if !errors.Is(err, kind.err) {
+			continue
+		}
+		problem := newProblem(kind.status, kind.code, err.Error())
+		var violation *specViolation
+		if errors.As(err, &violation) {
+			problem.Detail = ErrRequestValidation.Error()
+			problem.InvalidParams = violation.params
+		}
+		var fieldErr *controller.FieldError
+		if errors.As(err, &fieldErr) {
+			problem.InvalidParams = []dto.InvalidParam{{
+				Name:   fieldErr.Field,
+				Code:   kind.code,
+				Reason: fieldErr.Error(),
+			}}
+		}
+		var importErr *controller.ImportError
+		if errors.As(err, &importErr) {
+			problem.InvalidParams = importParams(importErr)
+		}
+		return problem
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code < http.StatusInternalServerError {
+		code := strings.ReplaceAll(strings.ToLower(http.StatusText(httpErr.Code)), " ", "_")
+		return newProblem(httpErr.Code, code, http.StatusText(httpErr.Code))
+	}
+
+	return newProblem(http.StatusInternalServerError, "internal_error", "")
+}
+
+// importParams names row errors rows[<line>].<field> so that clients can
+// point at the cell.
+func importParams(err *controller.ImportError) []dto.InvalidParam {
+	params := make([]dto.InvalidParam, 0, len(err.Rows))
+	for _, row := range err.Rows {
+		name := fmt.Sprintf("rows[%d]", row.Line)
+		var fieldErr *controller.FieldError
+		if errors.As(row.Err, &fieldErr) {
+			name += "." + fieldErr.Field
+		}
+		params = append(params, dto.InvalidParam{
+			Name:   name,
+			Code:   toProblem(row.Err).Code,
+			Reason: row.Err.Error(),
+		})
+	}
+	return params
+}
+
+func newProblem(status int, code, detail string) dto.ProblemResponse {
+	return dto.ProblemResponse{
+		Type:   problemTypePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/report"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	csvContentType  = "text/csv; charset=utf-8"
+	xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+type reportFormat struct {
+	filename    string
+	contentType string
+	newWriter   func(w io.Writer, request *dto.ReceptionReportRequest) (report.Writer, error)
+}
+
+var (
+	receptionsCSV = reportFormat{
+		filename:    "receptions.csv",
+		contentType: csvContentType,
+		newWriter: func(w io.Writer, request *dto.ReceptionReportRequest) (report.Writer, error) {
+			return report.NewCSVWriter(w, request.BOM)
+		},
+	}
+	receptionsXLSX = reportFormat{
+		filename:    "receptions.xlsx",
+		contentType: xlsxContentType,
+		newWriter: func(w io.Writer, _ *dto.ReceptionReportRequest) (report.Writer, error) {
+			return report.NewXLSXWriter(w, "Receptions")
+		},
+	}
+)
+
+func (h *PvzHandler) ExportReceptionsCSV(c echo.Context) error {
+	return h.exportReceptions(c, receptionsCSV)
+}
+
+func (h *PvzHandler) ExportReceptionsXLSX(c echo.Context) error {
+	return h.exportReceptions(c, receptionsXLSX)
+}
+
+// exportReceptions streams the report as rows are read. The response starts
+// with the first row, so validation and query errors are still reported as
+// problems; a failure after that aborts the connection to make the
+// truncated file detectable.
+func (h *PvzHandler) exportReceptions(c echo.Context, format reportFormat) error {
+	const op = "internal.handler.exportReceptions"
+	var req dto.ReceptionReportRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrInvalidRequest
+	}
+
+	var out report.Writer
+	start := func() error {
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentType, format.contentType)
+		header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", format.filename))
+		c.Response().WriteHeader(http.StatusOK)
+
+		writer, err := format.newWriter(c.Response(), &req)
+		if err != nil {
+			return err
+		}
+		out = writer
+		return out.WriteRow(report.ReceptionColumns...)
+	}
+
+	err := h.pvzService.ExportReceptions(c.Request().Context(), &req, func(row dto.ReceptionReportRow) error {
+		if out == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return out.WriteRow(report.ReceptionValues(row)...)
+	})
+	if err == nil && out == nil {
+		err = start()
+	}
+	if err == nil {
+		err = out.Close()
+	}
+	if err != nil && c.Response().Committed {
+		logrus.WithFields(logrus.Fields{"event": op, "path": c.Path()}).Error(err)
+		panic(http.ErrAbortHandler)
+	}
+	return err
+}
+
+func (h *PvzHandler) GetIntakeReport(c echo.Context) error {
+	var req dto.IntakeReportRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrInvalidRequest
+	}
+
+	response, err := h.pvzService.GetIntakeReport(c.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -1,36 +1,120 @@
 package handler
 
 import (
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/api"
 	"github.com/senorUVE/pvz_service/internal/metrics"
 	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/sirupsen/logrus"
 )
 
+type apiVersion struct {
+	authPrefix string
+	prefix     string
+	getPvz     echo.HandlerFunc
+	middleware []echo.MiddlewareFunc
+}
+
 func RegisterRoutes(h *PvzHandler) {
 	h.e.Use(metrics.PrometheusMiddleware)
 
-	authRouter := h.e.Group("/api")
+	doc, err := api.Load()
+	if err != nil {
+		logrus.Fatalf("failed to load api spec: %v", err)
+	}
+	validator, err := newOpenAPIValidator(doc, h.cfg.ValidateResponses)
+	if err != nil {
+		logrus.Fatalf("failed to init api validator: %v", err)
+	}
+
+	h.e.GET(docsPath, h.SwaggerUI)
+	h.e.GET(docsSpecPath, h.OpenAPISpec)
+	h.e.POST(graphQLPath, h.GraphQL, h.AuthMiddleware())
+
+	v1 := apiVersion{
+		authPrefix: "/api/v1",
+		prefix:     "/api/v1",
+		getPvz:     h.GetPvz,
+		middleware: []echo.MiddlewareFunc{h.DeprecationMiddleware()},
+	}
+	// Unversioned paths predate /api/v1 and are served by v1 until sunset.
+	legacy := v1
+	legacy.authPrefix, legacy.prefix = "/api", ""
+
+	v2 := apiVersion{
+		authPrefix: "/api/v2",
+		prefix:     "/api/v2",
+		getPvz:     h.GetPvzV2,
+	}
+
+	for _, version := range []apiVersion{legacy, v1, v2} {
+		registerVersion(h, validator, version)
+	}
+}
+
+func registerVersion(h *PvzHandler, validator *openAPIValidator, v apiVersion) {
+	authRouter := h.e.Group(v.authPrefix, v.middleware...)
+	authRouter.Use(validator.Middleware(v.authPrefix))
 	authRouter.POST("/register", h.Register)
 	authRouter.POST("/login", h.Login)
 	authRouter.POST("/dummyLogin", h.DummyLogin)
 	authRouter.GET("/ping", h.Ping)
 
-	pvzGroup := h.e.Group("/pvz")
-	pvzGroup.Use(h.AuthMiddleware())
+	pvzGroup := h.e.Group(v.prefix+"/pvz", v.middleware...)
+	pvzGroup.Use(h.AuthMiddleware(), validator.Middleware(v.prefix), h.IdempotencyMiddleware())
 	{
 		pvzGroup.POST("", h.CreatePVZ, h.RoleMiddleware(models.RoleModerator))
-		pvzGroup.GET("", h.GetPvz, h.RoleMiddleware(models.RoleModerator, models.RoleEmployee))
+		pvzGroup.POST("/import", h.ImportPvz, h.RoleMiddleware(models.RoleModerator))
+		pvzGroup.GET("", v.getPvz, h.RoleMiddleware(models.RoleModerator, models.RoleEmployee))
 		pvzGroup.POST("/:pvzId/close_last_reception", h.CloseReception, h.RoleMiddleware(models.RoleEmployee))
 		pvzGroup.POST("/:pvzId/delete_last_product", h.DeleteLastProduct, h.RoleMiddleware(models.RoleEmployee))
+		pvzGroup.POST("/:pvzId/undo_delete_last_product", h.UndoDeleteLastProduct, h.RoleMiddleware(models.RoleEmployee))
 	}
-	receptionGroup := h.e.Group("/receptions")
-	receptionGroup.Use(h.AuthMiddleware(), h.RoleMiddleware(models.RoleEmployee))
+	receptionGroup := h.e.Group(v.prefix+"/receptions", v.middleware...)
+	receptionGroup.Use(h.AuthMiddleware(), h.RoleMiddleware(models.RoleEmployee), validator.Middleware(v.prefix), h.IdempotencyMiddleware())
 	{
 		receptionGroup.POST("", h.CreateReception)
+		receptionGroup.GET("/:receptionId/labels", h.GetReceptionLabels)
+	}
+
+	productGroup := h.e.Group(v.prefix+"/products", v.middleware...)
+	productGroup.Use(h.AuthMiddleware(), validator.Middleware(v.prefix), h.IdempotencyMiddleware())
+	{
+		productGroup.POST("", h.AddProduct, h.RoleMiddleware(models.RoleEmployee))
+		productGroup.GET("", h.FindProduct, h.RoleMiddleware(models.RoleModerator, models.RoleEmployee))
+		productGroup.GET("/:productId", h.GetProduct, h.RoleMiddleware(models.RoleModerator, models.RoleEmployee))
+		productGroup.GET("/:productId/label", h.GetProductLabel, h.RoleMiddleware(models.RoleEmployee))
+		productGroup.POST("/:productId/issue", h.IssueProduct, h.RoleMiddleware(models.RoleEmployee))
+		productGroup.POST("/:productId/move", h.MoveProduct, h.RoleMiddleware(models.RoleEmployee))
+	}
+
+	syncGroup := h.e.Group(v.prefix+"/sync", v.middleware...)
+	syncGroup.Use(h.AuthMiddleware(), h.RoleMiddleware(models.RoleEmployee), validator.Middleware(v.prefix), h.IdempotencyMiddleware())
+	{
+		syncGroup.POST("", h.Sync)
+	}
+
+	eventGroup := h.e.Group(v.prefix+"/events", v.middleware...)
+	eventGroup.Use(h.AuthMiddleware(), h.RoleMiddleware(models.RoleModerator, models.RoleEmployee), validator.Middleware(v.prefix))
+	{
+		eventGroup.GET("/stream", h.StreamEvents)
+	}
+
+	webhookGroup := h.e.Group(v.prefix+"/webhooks", v.middleware...)
+	webhookGroup.Use(h.AuthMiddleware(), h.RoleMiddleware(models.RoleModerator), validator.Middleware(v.prefix), h.IdempotencyMiddleware())
+	{
+		webhookGroup.POST("", h.CreateWebhook)
+		webhookGroup.GET("", h.ListWebhooks)
+		webhookGroup.DELETE("/:webhookId", h.DeleteWebhook)
+		webhookGroup.GET("/:webhookId/deliveries", h.GetWebhookDeliveries)
+		webhookGroup.POST("/deliveries/:deliveryId/redeliver", h.RedeliverWebhook)
 	}
 
-	productGroup := h.e.Group("/products")
-	productGroup.Use(h.AuthMiddleware(), h.RoleMiddleware(models.RoleEmployee))
+	reportGroup := h.e.Group(v.prefix+"/reports", v.middleware...)
+	reportGroup.Use(h.AuthMiddleware(), h.RoleMiddleware(models.RoleModerator), validator.Middleware(v.prefix))
 	{
-		productGroup.POST("", h.AddProduct)
+		reportGroup.GET("/receptions.csv", h.ExportReceptionsCSV)
+		reportGroup.GET("/receptions.xlsx", h.ExportReceptionsXLSX)
+		reportGroup.GET("/intake", h.GetIntakeReport)
 	}
 }
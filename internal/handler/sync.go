@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+func (h *PvzHandler) Sync(c echo.Context) error {
+	var req dto.SyncRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrInvalidRequest
+	}
+
+	response, err := h.pvzService.Sync(c.Request().Context(), &req, currentUserId(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
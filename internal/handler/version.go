@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	deprecationHeader = "Deprecation"
+	sunsetHeader      = "Sunset"
+	linkHeader        = "Link"
+)
+
+// DeprecationMiddleware adds RFC 9745 Deprecation and RFC 8594 Sunset
+// headers to v1 responses once the dates are configured.
+func (h *PvzHandler) DeprecationMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			if !h.cfg.V1Deprecation.IsZero() {
+				header.Set(deprecationHeader, fmt.Sprintf("@%d", h.cfg.V1Deprecation.Unix()))
+			}
+			if !h.cfg.V1Sunset.IsZero() {
+				header.Set(sunsetHeader, h.cfg.V1Sunset.UTC().Format(http.TimeFormat))
+			}
+			if h.cfg.V1SunsetLink != "" && (!h.cfg.V1Deprecation.IsZero() || !h.cfg.V1Sunset.IsZero()) {
+				header.Add(linkHeader, fmt.Sprintf(`<%s>; rel="sunset"`, h.cfg.V1SunsetLink))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+func (h *PvzHandler) CreateWebhook(c echo.Context) error {
+	var req dto.WebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrInvalidRequest
+	}
+
+	response, err := h.pvzService.CreateWebhook(c.Request().Context(), &req, currentUserId(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+func (h *PvzHandler) ListWebhooks(c echo.Context) error {
+	response, err := h.pvzService.ListWebhooks(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *PvzHandler) DeleteWebhook(c echo.Context) error {
+	webhookId, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		return ErrInvalidWebhookId
+	}
+
+	if err := h.pvzService.DeleteWebhook(c.Request().Context(), webhookId); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *PvzHandler) GetWebhookDeliveries(c echo.Context) error {
+	webhookId, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		return ErrInvalidWebhookId
+	}
+
+	var req dto.GetWebhookDeliveriesRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrInvalidRequest
+	}
+
+	response, err := h.pvzService.GetWebhookDeliveries(c.Request().Context(), webhookId, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *PvzHandler) RedeliverWebhook(c echo.Context) error {
+	deliveryId, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		return ErrInvalidDeliveryId
+	}
+
+	response, err := h.pvzService.RedeliverWebhook(c.Request().Context(), deliveryId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, response)
+}
@@ -0,0 +1,9 @@
+package label
+
+import "errors"
+
+var (
+	ErrEmptyBarcode    = errors.New("barcode data is empty")
+	ErrUnsupportedChar = errors.New("character is not supported by code128 set B")
+	ErrInvalidFormat   = errors.New("invalid label format, allowed: png, zpl")
+)
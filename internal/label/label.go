@@ -0,0 +1,123 @@
+package label
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	FormatPNG Format = "png"
+	FormatZPL Format = "zpl"
+)
+
+func ParseFormat(str string) (Format, error) {
+	switch str {
+	case "", string(FormatPNG):
+		return FormatPNG, nil
+	case string(FormatZPL):
+		return FormatZPL, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidFormat, str)
+}
+
+// ContentType returns the MIME type of a rendered label. Bulk PNG labels are
+// packed into a zip archive, bulk ZPL labels are simply concatenated.
+func (f Format) ContentType(bulk bool) string {
+	switch {
+	case f == FormatZPL:
+		return "application/x-zpl"
+	case bulk:
+		return "application/zip"
+	}
+	return "image/png"
+}
+
+func (f Format) Extension(bulk bool) string {
+	if f == FormatPNG && bulk {
+		return "zip"
+	}
+	return string(f)
+}
+
+type Label struct {
+	ProductId     uuid.UUID
+	ProductType   string
+	ProductDate   time.Time
+	ReceptionId   uuid.UUID
+	ReceptionDate time.Time
+	PvzId         uuid.UUID
+	City          string
+}
+
+// Barcode is the value printed as Code128 on a product label: the product
+// UUID without dashes, so it still fits a 58mm sticker.
+func Barcode(productId uuid.UUID) string {
+	return strings.ReplaceAll(productId.String(), "-", "")
+}
+
+// ParseBarcode accepts both the label barcode and the canonical UUID form.
+func ParseBarcode(barcode string) (uuid.UUID, error) {
+	return uuid.Parse(strings.TrimSpace(barcode))
+}
+
+func (l Label) qrPayload() string {
+	return fmt.Sprintf("product:%s\nreception:%s\npvz:%s", l.ProductId, l.ReceptionId, l.PvzId)
+}
+
+func Render(format Format, l Label) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatPNG:
+		err = WritePNG(&buf, l)
+	case FormatZPL:
+		err = WriteZPL(&buf, l)
+	default:
+		err = ErrInvalidFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func RenderBulk(format Format, labels []Label) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case FormatPNG:
+		if err := writePNGArchive(&buf, labels); err != nil {
+			return nil, err
+		}
+	case FormatZPL:
+		for _, l := range labels {
+			if err := WriteZPL(&buf, l); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, ErrInvalidFormat
+	}
+	return buf.Bytes(), nil
+}
+
+func writePNGArchive(w io.Writer, labels []Label) error {
+	archive := zip.NewWriter(w)
+	for _, l := range labels {
+		file, err := archive.Create(l.ProductId.String() + ".png")
+		if err != nil {
+			return fmt.Errorf("failed to add label to archive: %w", err)
+		}
+		if err := WritePNG(file, l); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
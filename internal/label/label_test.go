@@ -0,0 +1,124 @@
+package label
+
+import (
+	"archive/zip"
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLabel() Label {
+	return Label{
+		ProductId:     uuid.MustParse("87c17529-99bb-4815-be06-900c4612902a"),
+		ProductType:   "электроника",
+		ProductDate:   time.Date(2025, 4, 1, 10, 30, 0, 0, time.UTC),
+		ReceptionId:   uuid.New(),
+		ReceptionDate: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+		PvzId:         uuid.New(),
+		City:          "Москва",
+	}
+}
+
+func TestCode128Patterns(t *testing.T) {
+	seen := make(map[string]bool)
+	for i, pattern := range code128Patterns {
+		sum := 0
+		for _, width := range pattern {
+			sum += int(width - '0')
+		}
+		if i == code128Stop {
+			assert.Equal(t, 13, sum)
+			continue
+		}
+		assert.Equal(t, 11, sum, "pattern %d", i)
+		assert.False(t, seen[pattern], "duplicate pattern %d", i)
+		seen[pattern] = true
+	}
+}
+
+func TestEncodeCode128(t *testing.T) {
+	modules, err := EncodeCode128("87c1752999bb4815be06900c4612902a")
+	require.NoError(t, err)
+	assert.Len(t, modules, 11*(32+2)+13)
+	assert.True(t, modules[0])
+	assert.True(t, modules[len(modules)-1])
+
+	_, err = EncodeCode128("")
+	assert.ErrorIs(t, err, ErrEmptyBarcode)
+
+	_, err = EncodeCode128("товар")
+	assert.ErrorIs(t, err, ErrUnsupportedChar)
+}
+
+func TestBarcode(t *testing.T) {
+	l := testLabel()
+	barcode := Barcode(l.ProductId)
+	assert.Equal(t, "87c1752999bb4815be06900c4612902a", barcode)
+
+	id, err := ParseBarcode(barcode)
+	require.NoError(t, err)
+	assert.Equal(t, l.ProductId, id)
+
+	id, err = ParseBarcode(l.ProductId.String())
+	require.NoError(t, err)
+	assert.Equal(t, l.ProductId, id)
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatPNG, f)
+
+	f, err = ParseFormat("zpl")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatZPL, f)
+
+	_, err = ParseFormat("pdf")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestRenderPNG(t *testing.T) {
+	data, err := Render(FormatPNG, testLabel())
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Greater(t, img.Bounds().Dx(), 11*34*barModule)
+}
+
+func TestRenderZPL(t *testing.T) {
+	l := testLabel()
+	l.City = "Казань^XZ"
+
+	data, err := Render(FormatZPL, l)
+	require.NoError(t, err)
+
+	zpl := string(data)
+	assert.True(t, strings.HasPrefix(zpl, "^XA"))
+	assert.Equal(t, 1, strings.Count(zpl, "^XZ"))
+	assert.Contains(t, zpl, "87c1752999bb4815be06900c4612902a")
+	assert.Contains(t, zpl, `Казань\5EXZ`)
+	assert.Contains(t, zpl, "электроника")
+}
+
+func TestRenderBulk(t *testing.T) {
+	labels := []Label{testLabel(), testLabel()}
+	labels[1].ProductId = uuid.New()
+
+	data, err := RenderBulk(FormatZPL, labels)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "^XA"))
+
+	data, err = RenderBulk(FormatPNG, labels)
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Len(t, archive.File, 2)
+	assert.Equal(t, labels[1].ProductId.String()+".png", archive.File[1].Name)
+}
@@ -0,0 +1,63 @@
+package label
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	barModule = 2
+	barHeight = 120
+	qrModule  = 4
+	margin    = 20
+)
+
+// WritePNG draws the Code128 barcode on top and the QR code below it. The QR
+// payload carries the reception and PVZ, since the image has no text.
+func WritePNG(w io.Writer, l Label) error {
+	bars, err := EncodeCode128(Barcode(l.ProductId))
+	if err != nil {
+		return err
+	}
+	qr, err := qrcode.New(l.qrPayload(), qrcode.Medium)
+	if err != nil {
+		return fmt.Errorf("failed to encode qr code: %w", err)
+	}
+	bitmap := qr.Bitmap()
+
+	width := max(len(bars)*barModule, len(bitmap)*qrModule) + 2*margin
+	height := margin + barHeight + margin + len(bitmap)*qrModule + margin
+
+	img := image.NewPaletted(image.Rect(0, 0, width, height), color.Palette{color.White, color.Black})
+	black := image.NewUniform(color.Black)
+
+	for i, bar := range bars {
+		if !bar {
+			continue
+		}
+		x := margin + i*barModule
+		draw.Draw(img, image.Rect(x, margin, x+barModule, margin+barHeight), black, image.Point{}, draw.Src)
+	}
+
+	top := margin + barHeight + margin
+	for y, row := range bitmap {
+		for x, on := range row {
+			if !on {
+				continue
+			}
+			px, py := margin+x*qrModule, top+y*qrModule
+			draw.Draw(img, image.Rect(px, py, px+qrModule, py+qrModule), black, image.Point{}, draw.Src)
+		}
+	}
+
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to encode png: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package label
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const dateLayout = "02.01.2006 15:04"
+
+// 4x3" label at 203 dpi. ^CI28 switches the printer to UTF-8 so city names
+// and product types are printed in Cyrillic.
+const zplTemplate = `^XA
+^CI28
+^PW812
+^LL609
+^FO20,20^BY2^BCN,120,Y,N,N^FH\^FD%s^FS
+^FO20,190^BQN,2,5^FH\^FDMA,%s^FS
+^FO300,200^A0N,34,34^FH\^FD%s^FS
+^FO300,250^A0N,28,28^FH\^FDПВЗ: %s^FS
+^FO300,290^A0N,22,22^FH\^FD%s^FS
+^FO300,340^A0N,28,28^FH\^FDПриемка: %s^FS
+^FO300,380^A0N,22,22^FH\^FD%s^FS
+^FO300,430^A0N,22,22^FH\^FDДобавлен: %s^FS
+^XZ
+`
+
+var zplEscaper = strings.NewReplacer(`\`, `\5C`, `^`, `\5E`, `~`, `\7E`, "\n", `\0A`)
+
+func WriteZPL(w io.Writer, l Label) error {
+	_, err := fmt.Fprintf(w, zplTemplate,
+		zplEscaper.Replace(Barcode(l.ProductId)),
+		zplEscaper.Replace(l.qrPayload()),
+		zplEscaper.Replace(l.ProductType),
+		zplEscaper.Replace(l.City),
+		l.PvzId,
+		l.ReceptionDate.UTC().Format(dateLayout),
+		l.ReceptionId,
+		l.ProductDate.UTC().Format(dateLayout),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write zpl: %w", err)
+	}
+	return nil
+}
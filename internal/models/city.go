@@ -25,3 +25,14 @@ func (City) Parse(str string) (City, error) {
 func (c City) String() string {
 	return string(c)
 }
+
+var Cities = []City{CityMoscow, CitySPB, CityKazan}
+
+// TimeZone is the IANA zone the city's activity is split into days by.
+func (c City) TimeZone() string {
+	switch c {
+	case CityMoscow, CitySPB, CityKazan:
+		return "Europe/Moscow"
+	}
+	return "UTC"
+}
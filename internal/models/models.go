@@ -47,6 +47,8 @@ type PVZ struct {
 	Id               uuid.UUID `json:"id" db:"id"`
 	RegistrationDate time.Time `json:"registrationDate" db:"registration_date"`
 	City             City      `json:"city" db:"city"`
+	// ExternalCode identifies the PVZ in partner systems and imports.
+	ExternalCode string `json:"externalCode,omitempty" db:"external_code"`
 }
 
 type Reception struct {
@@ -0,0 +1,71 @@
+package models
+
+import "fmt"
+
+type ProductEventType string
+
+const (
+	ProductEventAdded    ProductEventType = "added"
+	ProductEventDeleted  ProductEventType = "deleted"
+	ProductEventRestored ProductEventType = "restored"
+	ProductEventMoved    ProductEventType = "moved"
+	ProductEventIssued   ProductEventType = "issued"
+)
+
+func (ProductEventType) Parse(str string) (ProductEventType, error) {
+	switch str {
+	case string(ProductEventAdded):
+		return ProductEventAdded, nil
+	case string(ProductEventDeleted):
+		return ProductEventDeleted, nil
+	case string(ProductEventRestored):
+		return ProductEventRestored, nil
+	case string(ProductEventMoved):
+		return ProductEventMoved, nil
+	case string(ProductEventIssued):
+		return ProductEventIssued, nil
+	}
+	return "", fmt.Errorf("invalid product event type: %s", str)
+}
+
+func (t ProductEventType) String() string {
+	return string(t)
+}
+
+type ProductStatus string
+
+const (
+	ProductStatusInReception ProductStatus = "in_reception"
+	ProductStatusAccepted    ProductStatus = "accepted"
+	ProductStatusDeleted     ProductStatus = "deleted"
+	ProductStatusMoved       ProductStatus = "moved"
+	ProductStatusIssued      ProductStatus = "issued"
+)
+
+// CurrentProductStatus derives where a product is from the last event of its
+// timeline and the status of the reception it belongs to.
+func CurrentProductStatus(receptionStatus Status, lastEvent ProductEventType) ProductStatus {
+	switch lastEvent {
+	case ProductEventDeleted:
+		return ProductStatusDeleted
+	case ProductEventMoved:
+		return ProductStatusMoved
+	case ProductEventIssued:
+		return ProductStatusIssued
+	}
+	if receptionStatus == StatusInProgress {
+		return ProductStatusInReception
+	}
+	return ProductStatusAccepted
+}
+
+// Available reports whether the product waits for its recipient at a PVZ,
+// the one it was accepted at or the one it was last moved to, so that it
+// may be issued or moved on.
+func (s ProductStatus) Available() bool {
+	return s == ProductStatusAccepted || s == ProductStatusMoved
+}
+
+func (s ProductStatus) String() string {
+	return string(s)
+}
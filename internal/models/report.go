@@ -0,0 +1,33 @@
+package models
+
+type IntakeDimension string
+
+const (
+	IntakeByCity  IntakeDimension = "city"
+	IntakeByPvz   IntakeDimension = "pvz"
+	IntakeByType  IntakeDimension = "type"
+	IntakeByDay   IntakeDimension = "day"
+	IntakeByWeek  IntakeDimension = "week"
+	IntakeByMonth IntakeDimension = "month"
+)
+
+func (d IntakeDimension) Valid() bool {
+	switch d {
+	case IntakeByCity, IntakeByPvz, IntakeByType:
+		return true
+	}
+	return d.Period()
+}
+
+// Period reports whether d buckets activity by time.
+func (d IntakeDimension) Period() bool {
+	switch d {
+	case IntakeByDay, IntakeByWeek, IntakeByMonth:
+		return true
+	}
+	return false
+}
+
+func (d IntakeDimension) String() string {
+	return string(d)
+}
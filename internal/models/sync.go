@@ -0,0 +1,36 @@
+package models
+
+type SyncOperationType string
+
+const (
+	SyncOpenReception     SyncOperationType = "open_reception"
+	SyncAddProduct        SyncOperationType = "add_product"
+	SyncDeleteLastProduct SyncOperationType = "delete_last_product"
+	SyncCloseReception    SyncOperationType = "close_reception"
+)
+
+func (t SyncOperationType) Valid() bool {
+	switch t {
+	case SyncOpenReception, SyncAddProduct, SyncDeleteLastProduct, SyncCloseReception:
+		return true
+	}
+	return false
+}
+
+func (t SyncOperationType) String() string {
+	return string(t)
+}
+
+type SyncStatus string
+
+const (
+	SyncApplied SyncStatus = "applied"
+	// SyncMerged means the operation's intent already holds on the server,
+	// e.g. a reception opened offline while another one was opened online.
+	SyncMerged   SyncStatus = "merged"
+	SyncSkipped  SyncStatus = "skipped"
+	SyncRejected SyncStatus = "rejected"
+	// SyncDuplicate is returned for a client id that was already synced,
+	// together with the outcome stored back then.
+	SyncDuplicate SyncStatus = "duplicate"
+)
@@ -0,0 +1,23 @@
+package models
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead is reached after the last retry fails; such
+	// deliveries are only sent again on explicit redelivery.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+func (s WebhookDeliveryStatus) Valid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryDead:
+		return true
+	}
+	return false
+}
+
+func (s WebhookDeliveryStatus) String() string {
+	return string(s)
+}
@@ -0,0 +1,45 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at the edge PVZ of a page: the last one for the next page,
+// or the first one with Backward set for the previous page. Value is the sort
+// key of that PVZ in its database text form, Sort and Desc pin the ordering
+// the cursor was issued for. It is handed to clients as an opaque base64
+// string, so its fields can change without breaking them.
+type Cursor struct {
+	Sort     string    `json:"s"`
+	Desc     bool      `json:"d,omitempty"`
+	Value    string    `json:"v"`
+	Id       uuid.UUID `json:"i"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+func (c Cursor) Matches(sort string, desc bool) bool {
+	return c.Sort == sort && c.Desc == desc
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(str string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Id == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
@@ -0,0 +1,29 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	cursor := Cursor{
+		Sort:  "city",
+		Desc:  true,
+		Value: "Казань",
+		Id:    uuid.New(),
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, *decoded)
+	assert.True(t, decoded.Matches("city", true))
+	assert.False(t, decoded.Matches("city", false))
+
+	for _, str := range []string{"", "not base64!", "e30"} {
+		_, err = DecodeCursor(str)
+		assert.ErrorIs(t, err, ErrInvalidCursor, str)
+	}
+}
@@ -0,0 +1,51 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const utf8BOM = "\ufeff"
+
+type CSVWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter writes UTF-8 CSV. With bom the output starts with a byte
+// order mark, which Excel needs to read Cyrillic text correctly.
+func NewCSVWriter(w io.Writer, bom bool) (*CSVWriter, error) {
+	if bom {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return nil, err
+		}
+	}
+	return &CSVWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (w *CSVWriter) WriteRow(values ...any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case int:
+			record[i] = strconv.Itoa(v)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case time.Time:
+			record[i] = formatTime(v)
+		default:
+			return fmt.Errorf("unsupported value %T", value)
+		}
+	}
+	return w.w.Write(record)
+}
+
+func (w *CSVWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
@@ -0,0 +1,36 @@
+package report
+
+import (
+	"time"
+
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+// Writer writes a table row by row. Supported values are string, int,
+// float64, time.Time and nil for an empty cell.
+type Writer interface {
+	WriteRow(values ...any) error
+	Close() error
+}
+
+var ReceptionColumns = []any{
+	"pvz_id", "city", "reception_id", "reception_date_time", "reception_status",
+	"product_id", "product_date_time", "product_type",
+}
+
+func ReceptionValues(row dto.ReceptionReportRow) []any {
+	values := []any{
+		row.PvzId.String(), row.City, row.ReceptionId.String(), row.ReceptionDateTime.UTC(), row.ReceptionStatus,
+		nil, nil, nil,
+	}
+	if row.ProductId != nil {
+		values[5] = row.ProductId.String()
+		values[6] = row.ProductDateTime.UTC()
+		values[7] = *row.ProductType
+	}
+	return values
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
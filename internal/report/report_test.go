@@ -0,0 +1,96 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRows() []dto.ReceptionReportRow {
+	productId := uuid.New()
+	productTime := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	productType := "электроника"
+	return []dto.ReceptionReportRow{
+		{
+			PvzId: uuid.New(), City: "Санкт-Петербург", ReceptionId: uuid.New(),
+			ReceptionDateTime: productTime.Add(-time.Hour), ReceptionStatus: "close",
+			ProductId: &productId, ProductDateTime: &productTime, ProductType: &productType,
+		},
+		{
+			PvzId: uuid.New(), City: "Казань", ReceptionId: uuid.New(),
+			ReceptionDateTime: productTime, ReceptionStatus: "in_progress",
+		},
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	for _, bom := range []bool{false, true} {
+		var buf bytes.Buffer
+		w, err := NewCSVWriter(&buf, bom)
+		require.NoError(t, err)
+		require.NoError(t, w.WriteRow(ReceptionColumns...))
+		for _, row := range testRows() {
+			require.NoError(t, w.WriteRow(ReceptionValues(row)...))
+		}
+		require.NoError(t, w.Close())
+
+		data := buf.String()
+		assert.Equal(t, bom, strings.HasPrefix(data, utf8BOM))
+		records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, utf8BOM))).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, "Санкт-Петербург", records[1][1])
+		assert.Equal(t, "электроника", records[1][7])
+		assert.Equal(t, "2025-03-01T10:30:00Z", records[1][6])
+		assert.Equal(t, "", records[2][5])
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewXLSXWriter(&buf, "Receptions & <Products>")
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow(ReceptionColumns...))
+	for _, row := range testRows() {
+		require.NoError(t, w.WriteRow(ReceptionValues(row)...))
+	}
+	require.NoError(t, w.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts[f.Name] = string(content)
+		assert.NoError(t, xml.Unmarshal(content, new(any)), f.Name)
+	}
+
+	require.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts["xl/workbook.xml"], `name="Receptions &amp; &lt;Products&gt;"`)
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="B2" t="inlineStr"><is><t xml:space="preserve">Санкт-Петербург</t></is></c>`)
+	// 2025-03-01 10:30 UTC as an Excel serial date.
+	assert.Contains(t, sheet, `<c r="G2" s="1"><v>45717.4375</v></c>`)
+	assert.NotContains(t, sheet, `r="F3"`)
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
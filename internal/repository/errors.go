@@ -5,11 +5,27 @@ import "errors"
 var (
 	ErrUserNotFound = errors.New("user not found")
 
+	ErrUserExists = errors.New("user with this email already exists")
+
 	ErrPVZNotFound = errors.New("pvz not found")
 
 	ErrReceptionNotFound = errors.New("reception not found")
 
 	ErrProductNotFound = errors.New("product not found")
 
+	ErrReceptionEmpty = errors.New("reception has no products")
+
 	ErrNoActiveReception = errors.New("no active reception found")
+
+	ErrPvzExternalCodeExists = errors.New("pvz with this external code already exists")
+
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+	ErrWebhookDeliveryPending = errors.New("webhook delivery is still pending")
+
+	ErrWebhookAttemptStale = errors.New("webhook delivery was changed since it was claimed")
+
+	ErrSyncOperationNotFound = errors.New("sync operation not found")
 )
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+// ReserveIdempotencyKey claims the key for a new request. It returns nil when
+// the key was free or expired, and the stored record when it is already taken.
+func (r *Repository) ReserveIdempotencyKey(ctx context.Context, record dto.IdempotencyRecord, ttl time.Duration) (*dto.IdempotencyRecord, error) {
+	var userId uuid.UUID
+	err := r.db.QueryRowxContext(ctx, reserveIdempotencyKey, record.UserId, record.Key, record.RequestHash, ttl.Seconds()).Scan(&userId)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	var (
+		statusCode  sql.NullInt64
+		contentType sql.NullString
+	)
+	stored := dto.IdempotencyRecord{UserId: record.UserId, Key: record.Key}
+	err = r.db.QueryRowxContext(ctx, getIdempotencyKey, record.UserId, record.Key).
+		Scan(&stored.RequestHash, &statusCode, &contentType, &stored.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	stored.StatusCode = int(statusCode.Int64)
+	stored.ContentType = contentType.String
+	return &stored, nil
+}
+
+func (r *Repository) SaveIdempotencyResponse(ctx context.Context, record dto.IdempotencyRecord) error {
+	_, err := r.db.ExecContext(ctx, saveIdempotencyResponse, record.UserId, record.Key, record.StatusCode, record.ContentType, record.Body)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) error {
+	if _, err := r.db.ExecContext(ctx, deleteIdempotencyKey, userId, key); err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, deleteExpiredIdempotencyKeys)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
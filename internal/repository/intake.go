@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/models"
+)
+
+// intakeDimensions are the SQL expressions the intake report groups by.
+// Periods are cut in the local time of the PVZ's city.
+var intakeDimensions = map[models.IntakeDimension]string{
+	models.IntakeByCity:  "p.city",
+	models.IntakeByPvz:   "p.id",
+	models.IntakeByType:  "pr.type",
+	models.IntakeByDay:   fmt.Sprintf(intakePeriod, "day"),
+	models.IntakeByWeek:  fmt.Sprintf(intakePeriod, "week"),
+	models.IntakeByMonth: fmt.Sprintf(intakePeriod, "month"),
+}
+
+// dailyIntakeDimensions group the same way over pvz_daily_stats, whose days
+// are already local.
+var dailyIntakeDimensions = map[models.IntakeDimension]string{
+	models.IntakeByCity:  "p.city",
+	models.IntakeByPvz:   "s.pvz_id",
+	models.IntakeByType:  "s.product_type",
+	models.IntakeByDay:   fmt.Sprintf(dailyPeriod, "day"),
+	models.IntakeByWeek:  fmt.Sprintf(dailyPeriod, "week"),
+	models.IntakeByMonth: fmt.Sprintf(dailyPeriod, "month"),
+}
+
+func (b *queryBuilder) intakeReportConditions(filter dto.IntakeReportFilter) {
+	b.where("pr.deleted_at IS NULL")
+	if !filter.StartDate.IsZero() {
+		b.where("pr.date_time >= %s", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		b.where("pr.date_time <= %s", filter.EndDate)
+	}
+	if len(filter.Cities) > 0 {
+		b.where("p.city = ANY(%s::text[])", pq.StringArray(filter.Cities))
+	}
+}
+
+// cityTimeZones returns the cities and their zones as two parallel arrays.
+func cityTimeZones() (pq.StringArray, pq.StringArray) {
+	cities := make(pq.StringArray, len(models.Cities))
+	zones := make(pq.StringArray, len(models.Cities))
+	for i, city := range models.Cities {
+		cities[i], zones[i] = city.String(), city.TimeZone()
+	}
+	return cities, zones
+}
+
+// intakeGrouping returns the leading select columns and the GROUP BY and
+// ORDER BY clause for groupBy.
+func intakeGrouping(dimensions map[models.IntakeDimension]string, groupBy []models.IntakeDimension) (string, string) {
+	if len(groupBy) == 0 {
+		return "", ""
+	}
+	expressions := make([]string, len(groupBy))
+	positions := make([]string, len(groupBy))
+	for i, dimension := range groupBy {
+		expressions[i] = dimensions[dimension]
+		positions[i] = strconv.Itoa(i + 1)
+	}
+	return strings.Join(expressions, ", ") + ", ",
+		" GROUP BY " + strings.Join(positions, ", ") + " ORDER BY " + strings.Join(positions, ", ")
+}
+
+// GetIntakeReport counts products received per group of filter.GroupBy, in
+// that order. Without dimensions it returns a single total row.
+func (r *Repository) GetIntakeReport(ctx context.Context, filter dto.IntakeReportFilter) ([]dto.IntakeReportRow, error) {
+	var b queryBuilder
+	cities, zones := cityTimeZones()
+	citiesArg, zonesArg := b.placeholder(cities), b.placeholder(zones)
+	b.intakeReportConditions(filter)
+
+	columns, groupBy := intakeGrouping(intakeDimensions, filter.GroupBy)
+	query := fmt.Sprintf(selectIntakeReport, columns, citiesArg, zonesArg, b.whereClause()) + groupBy
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intake report: %w", err)
+	}
+	defer rows.Close()
+	return scanIntakeReport(rows, filter.GroupBy)
+}
+
+// GetDailyIntakeReport answers GetIntakeReport from pvz_daily_stats for the
+// local days firstDay through lastDay; a zero day leaves that end open.
+func (r *Repository) GetDailyIntakeReport(ctx context.Context, filter dto.IntakeReportFilter, firstDay, lastDay time.Time) ([]dto.IntakeReportRow, error) {
+	var b queryBuilder
+	if !firstDay.IsZero() {
+		b.where("s.day >= %s::date", firstDay.Format(time.DateOnly))
+	}
+	if !lastDay.IsZero() {
+		b.where("s.day <= %s::date", lastDay.Format(time.DateOnly))
+	}
+	if len(filter.Cities) > 0 {
+		b.where("p.city = ANY(%s::text[])", pq.StringArray(filter.Cities))
+	}
+	// Receptions are counted per type in typed rows and across types in the
+	// untyped one, so exactly one of them is summed.
+	receptions := "s.product_type = ''"
+	if slices.Contains(filter.GroupBy, models.IntakeByType) {
+		b.where("s.product_type <> ''")
+		receptions = "TRUE"
+	}
+
+	columns, groupBy := intakeGrouping(dailyIntakeDimensions, filter.GroupBy)
+	query := fmt.Sprintf(selectDailyIntakeReport, columns, receptions, b.whereClause()) + groupBy
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily intake report: %w", err)
+	}
+	defer rows.Close()
+	return scanIntakeReport(rows, filter.GroupBy)
+}
+
+func scanIntakeReport(rows *sqlx.Rows, groupBy []models.IntakeDimension) ([]dto.IntakeReportRow, error) {
+	report := make([]dto.IntakeReportRow, 0)
+	for rows.Next() {
+		var row dto.IntakeReportRow
+		dest := make([]any, 0, len(groupBy)+2)
+		for _, dimension := range groupBy {
+			switch dimension {
+			case models.IntakeByCity:
+				row.City = new(string)
+				dest = append(dest, row.City)
+			case models.IntakeByPvz:
+				row.PvzId = new(uuid.UUID)
+				dest = append(dest, row.PvzId)
+			case models.IntakeByType:
+				row.ProductType = new(string)
+				dest = append(dest, row.ProductType)
+			default:
+				row.Period = new(string)
+				dest = append(dest, row.Period)
+			}
+		}
+		dest = append(dest, &row.Products, &row.Receptions)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan intake report: %w", err)
+		}
+		report = append(report, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read intake report: %w", err)
+	}
+	return report, nil
+}
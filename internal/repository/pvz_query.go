@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/pagination"
+)
+
+// queryBuilder collects WHERE conditions and numbers their bind parameters.
+// Every %s in a condition is replaced by the placeholder of the matching arg,
+// so user input never ends up in the SQL text.
+type queryBuilder struct {
+	conditions []string
+	args       []any
+}
+
+func (b *queryBuilder) placeholder(arg any) string {
+	b.args = append(b.args, arg)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *queryBuilder) bind(condition string, args ...any) string {
+	placeholders := make([]any, len(args))
+	for i, arg := range args {
+		placeholders[i] = b.placeholder(arg)
+	}
+	return fmt.Sprintf(condition, placeholders...)
+}
+
+func (b *queryBuilder) where(condition string, args ...any) {
+	b.conditions = append(b.conditions, b.bind(condition, args...))
+}
+
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// receptionConditions are the filters that apply to receptions. They both
+// select which PVZs are listed and which of their receptions are returned.
+func (b *queryBuilder) receptionConditions(filter dto.GetPvzRequest) []string {
+	var conditions []string
+	if !filter.StartDate.IsZero() {
+		conditions = append(conditions, b.bind("r.date_time >= %s", filter.StartDate))
+	}
+	if !filter.EndDate.IsZero() {
+		conditions = append(conditions, b.bind("r.date_time <= %s", filter.EndDate))
+	}
+	if filter.ReceptionStatus != "" {
+		conditions = append(conditions, b.bind("r.status = %s", filter.ReceptionStatus))
+	}
+	if filter.ProductType != "" {
+		conditions = append(conditions, b.bind("EXISTS (SELECT 1 FROM product fp WHERE fp.reception_id = r.id AND fp.deleted_at IS NULL AND fp.type = %s)", filter.ProductType))
+	}
+	return conditions
+}
+
+func (b *queryBuilder) pvzConditions(filter dto.GetPvzRequest) {
+	if len(filter.Cities) > 0 {
+		b.where("p.city = ANY(%s::text[])", pq.StringArray(filter.Cities))
+	}
+	if !filter.RegisteredFrom.IsZero() {
+		b.where("p.registration_date >= %s", filter.RegisteredFrom)
+	}
+	if !filter.RegisteredTo.IsZero() {
+		b.where("p.registration_date <= %s", filter.RegisteredTo)
+	}
+	if filter.HasOpenReception != nil {
+		open := "EXISTS (SELECT 1 FROM reception o WHERE o.pvz_id = p.id AND o.status = 'in_progress')"
+		if !*filter.HasOpenReception {
+			open = "NOT " + open
+		}
+		b.where(open)
+	}
+	if conditions := b.receptionConditions(filter); len(conditions) > 0 {
+		b.where("EXISTS (SELECT 1 FROM reception r WHERE r.pvz_id = p.id AND " + strings.Join(conditions, " AND ") + ")")
+	}
+}
+
+// pvzSorts whitelists the orderings of the PVZ list. The sort key is compared
+// against the cursor value, so it carries the SQL type to cast the value to.
+var pvzSorts = map[string]struct {
+	expr    string
+	sqlType string
+}{
+	dto.SortRegistrationDate: {"p.registration_date", "timestamptz"},
+	dto.SortCity:             {"p.city", "text"},
+	dto.SortLastReception:    {"COALESCE((SELECT max(lr.date_time) FROM reception lr WHERE lr.pvz_id = p.id), to_timestamp(0))", "timestamptz"},
+	dto.SortProductCount:     {"(SELECT count(*) FROM product cp JOIN reception cr ON cr.id = cp.reception_id WHERE cr.pvz_id = p.id AND cp.deleted_at IS NULL)", "bigint"},
+}
+
+// buildPVZPageQuery selects one extra row past the limit, so the caller knows
+// whether another page follows. Backward cursors scan in reverse order and the
+// caller flips the rows back. The id breaks ties, so the order is total and
+// holds across pages.
+func buildPVZPageQuery(filter dto.GetPvzRequest, cursor *pagination.Cursor, offset int) (string, []any) {
+	sort, ok := pvzSorts[filter.Sort]
+	if !ok {
+		sort = pvzSorts[dto.SortRegistrationDate]
+	}
+
+	var b queryBuilder
+	b.pvzConditions(filter)
+	query := fmt.Sprintf(selectPVZPage, sort.expr, b.whereClause())
+
+	desc := filter.Order == dto.OrderDesc
+	if cursor != nil && cursor.Backward {
+		desc = !desc
+	}
+	order, compare := "ASC", ">"
+	if desc {
+		order, compare = "DESC", "<"
+	}
+
+	b.conditions = nil
+	if cursor != nil {
+		b.where("(s.sort_key, s.id) "+compare+" (%s::"+sort.sqlType+", %s)", cursor.Value, cursor.Id)
+	}
+	query += b.whereClause() +
+		fmt.Sprintf(" ORDER BY s.sort_key %s, s.id %s LIMIT %s OFFSET %s", order, order, b.placeholder(filter.Limit+1), b.placeholder(offset))
+	return query, b.args
+}
+
+func buildPVZCountQuery(filter dto.GetPvzRequest) (string, []any) {
+	var b queryBuilder
+	b.pvzConditions(filter)
+	return countPVZ + b.whereClause(), b.args
+}
+
+// productJoin narrows the product join to what a listing shows.
+func (b *queryBuilder) productJoin(filter dto.GetPvzRequest) string {
+	var join string
+	if !filter.IncludeDeleted {
+		join += " AND pr.deleted_at IS NULL"
+	}
+	if filter.ProductType != "" {
+		join += " AND pr.type = " + b.placeholder(filter.ProductType)
+	}
+	return join
+}
+
+func buildPVZReceptionsQuery(filter dto.GetPvzRequest, pvzIds pq.StringArray, withProducts bool) (string, []any) {
+	var b queryBuilder
+	query, order := selectPVZReceptionsOnly, " ORDER BY r.date_time, r.id"
+	if withProducts {
+		query = selectPVZReceptions + b.productJoin(filter)
+		order += ", pr.date_time, pr.id"
+	}
+	b.where("r.pvz_id = ANY(%s::uuid[])", pvzIds)
+	b.conditions = append(b.conditions, b.receptionConditions(filter)...)
+
+	return query + b.whereClause() + order, b.args
+}
+
+func buildPVZSummaryQuery(filter dto.GetPvzRequest, pvzIds pq.StringArray) (string, []any) {
+	var b queryBuilder
+	query := selectPVZSummary + b.productJoin(filter)
+	b.where("r.pvz_id = ANY(%s::uuid[])", pvzIds)
+	b.conditions = append(b.conditions, b.receptionConditions(filter)...)
+
+	return query + b.whereClause() + " GROUP BY r.pvz_id", b.args
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/senorUVE/pvz_service/internal/dto"
+	"github.com/senorUVE/pvz_service/internal/pagination"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPVZPageQuery(t *testing.T) {
+	testTime := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	open := false
+
+	t.Run("no filters", func(t *testing.T) {
+		query, args := buildPVZPageQuery(dto.GetPvzRequest{Limit: 10}, nil, 20)
+		assert.Equal(t, fmt.Sprintf(selectPVZPage, "p.registration_date", "")+
+			" ORDER BY s.sort_key ASC, s.id ASC LIMIT $1 OFFSET $2", query)
+		assert.Equal(t, []any{11, 20}, args)
+	})
+
+	t.Run("all filters", func(t *testing.T) {
+		cursor := &pagination.Cursor{Sort: dto.SortCity, Value: "Казань", Id: uuid.Nil}
+		filter := dto.GetPvzRequest{
+			Limit:            5,
+			Sort:             dto.SortCity,
+			Cities:           []string{"Москва", "Казань'; DROP TABLE pvz; --"},
+			RegisteredFrom:   testTime,
+			HasOpenReception: &open,
+			StartDate:        testTime,
+			ReceptionStatus:  "close",
+			ProductType:      "обувь",
+		}
+		conditions := " WHERE p.city = ANY($1::text[])" +
+			" AND p.registration_date >= $2" +
+			" AND NOT EXISTS (SELECT 1 FROM reception o WHERE o.pvz_id = p.id AND o.status = 'in_progress')" +
+			" AND EXISTS (SELECT 1 FROM reception r WHERE r.pvz_id = p.id AND r.date_time >= $3 AND r.status = $4" +
+			" AND EXISTS (SELECT 1 FROM product fp WHERE fp.reception_id = r.id AND fp.deleted_at IS NULL AND fp.type = $5))"
+
+		query, args := buildPVZPageQuery(filter, cursor, 0)
+		assert.Equal(t, fmt.Sprintf(selectPVZPage, "p.city", conditions)+
+			" WHERE (s.sort_key, s.id) > ($6::text, $7)"+
+			" ORDER BY s.sort_key ASC, s.id ASC LIMIT $8 OFFSET $9", query)
+		assert.Equal(t, []any{
+			pq.StringArray(filter.Cities), testTime, testTime, "close", "обувь",
+			"Казань", uuid.Nil, 6, 0,
+		}, args)
+
+		query, args = buildPVZCountQuery(filter)
+		assert.Equal(t, countPVZ+conditions, query)
+		assert.Len(t, args, 5)
+	})
+
+	t.Run("backward cursor on descending sort", func(t *testing.T) {
+		cursor := &pagination.Cursor{Sort: dto.SortProductCount, Desc: true, Value: "7", Id: uuid.Nil, Backward: true}
+		filter := dto.GetPvzRequest{Limit: 10, Sort: dto.SortProductCount, Order: dto.OrderDesc}
+
+		query, args := buildPVZPageQuery(filter, cursor, 0)
+		assert.Equal(t, fmt.Sprintf(selectPVZPage, pvzSorts[dto.SortProductCount].expr, "")+
+			" WHERE (s.sort_key, s.id) > ($1::bigint, $2)"+
+			" ORDER BY s.sort_key ASC, s.id ASC LIMIT $3 OFFSET $4", query)
+		assert.Equal(t, []any{"7", uuid.Nil, 11, 0}, args)
+	})
+
+	t.Run("descending sort", func(t *testing.T) {
+		query, _ := buildPVZPageQuery(dto.GetPvzRequest{Limit: 10, Sort: dto.SortLastReception, Order: dto.OrderDesc}, nil, 0)
+		assert.Contains(t, query, "max(lr.date_time)")
+		assert.Contains(t, query, " ORDER BY s.sort_key DESC, s.id DESC ")
+	})
+}
+
+func TestBuildPVZReceptionsQuery(t *testing.T) {
+	ids := pq.StringArray{uuid.Nil.String()}
+
+	query, args := buildPVZReceptionsQuery(dto.GetPvzRequest{ProductType: "обувь", ReceptionStatus: "in_progress"}, ids, true)
+	assert.Equal(t, selectPVZReceptions+" AND pr.deleted_at IS NULL AND pr.type = $1"+
+		" WHERE r.pvz_id = ANY($2::uuid[]) AND r.status = $3"+
+		" AND EXISTS (SELECT 1 FROM product fp WHERE fp.reception_id = r.id AND fp.deleted_at IS NULL AND fp.type = $4)"+
+		" ORDER BY r.date_time, r.id, pr.date_time, pr.id", query)
+	assert.Equal(t, []any{"обувь", ids, "in_progress", "обувь"}, args)
+
+	query, args = buildPVZReceptionsQuery(dto.GetPvzRequest{IncludeDeleted: true}, ids, true)
+	assert.Equal(t, selectPVZReceptions+" WHERE r.pvz_id = ANY($1::uuid[]) ORDER BY r.date_time, r.id, pr.date_time, pr.id", query)
+	assert.Equal(t, []any{ids}, args)
+
+	query, args = buildPVZReceptionsQuery(dto.GetPvzRequest{ProductType: "обувь"}, ids, false)
+	assert.Equal(t, selectPVZReceptionsOnly+" WHERE r.pvz_id = ANY($1::uuid[])"+
+		" AND EXISTS (SELECT 1 FROM product fp WHERE fp.reception_id = r.id AND fp.deleted_at IS NULL AND fp.type = $2)"+
+		" ORDER BY r.date_time, r.id", query)
+	assert.Equal(t, []any{ids, "обувь"}, args)
+}
+
+func TestBuildPVZSummaryQuery(t *testing.T) {
+	ids := pq.StringArray{uuid.Nil.String()}
+
+	query, args := buildPVZSummaryQuery(dto.GetPvzRequest{ReceptionStatus: "close"}, ids)
+	assert.Equal(t, selectPVZSummary+" AND pr.deleted_at IS NULL"+
+		" WHERE r.pvz_id = ANY($1::uuid[]) AND r.status = $2 GROUP BY r.pvz_id", query)
+	assert.Equal(t, []any{ids, "close"}, args)
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+const reportFetchSize = 500
+
+func (b *queryBuilder) receptionReportConditions(filter dto.ReceptionReportFilter) {
+	if !filter.StartDate.IsZero() {
+		b.where("r.date_time >= %s", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		b.where("r.date_time <= %s", filter.EndDate)
+	}
+	if len(filter.Cities) > 0 {
+		b.where("p.city = ANY(%s::text[])", pq.StringArray(filter.Cities))
+	}
+	if len(filter.PvzIds) > 0 {
+		ids := make(pq.StringArray, len(filter.PvzIds))
+		for i, id := range filter.PvzIds {
+			ids[i] = id.String()
+		}
+		b.where("p.id = ANY(%s::uuid[])", ids)
+	}
+}
+
+// StreamReceptionReport calls fn for every report row in order. Rows are
+// read through a server-side cursor in batches, so the size of the report
+// does not affect memory use. An error from fn stops the stream.
+func (r *Repository) StreamReceptionReport(ctx context.Context, filter dto.ReceptionReportFilter, fn func(dto.ReceptionReportRow) error) error {
+	var b queryBuilder
+	b.receptionReportConditions(filter)
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(declareReceptionReport, b.whereClause()), b.args...); err != nil {
+		return fmt.Errorf("failed to declare report cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf(fetchReceptionReportRows, reportFetchSize)
+	for {
+		fetched, err := fetchReceptionReport(ctx, tx, fetch, fn)
+		if err != nil {
+			return err
+		}
+		if fetched < reportFetchSize {
+			break
+		}
+	}
+	return tx.Commit()
+}
+
+func fetchReceptionReport(ctx context.Context, tx *sqlx.Tx, fetch string, fn func(dto.ReceptionReportRow) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch report rows: %w", err)
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		var (
+			row         dto.ReceptionReportRow
+			productId   uuid.NullUUID
+			productTime sql.NullTime
+			productType sql.NullString
+		)
+		if err := rows.Scan(&row.PvzId, &row.City, &row.ReceptionId, &row.ReceptionDateTime, &row.ReceptionStatus,
+			&productId, &productTime, &productType); err != nil {
+			return fetched, fmt.Errorf("failed to scan report row: %w", err)
+		}
+		if productId.Valid {
+			row.ProductId = &productId.UUID
+			row.ProductDateTime = &productTime.Time
+			row.ProductType = &productType.String
+		}
+		fetched++
+		if err := fn(row); err != nil {
+			return fetched, err
+		}
+	}
+	return fetched, rows.Err()
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,9 +13,9 @@ import (
 
 	"github.com/senorUVE/pvz_service/internal/dto"
 	"github.com/senorUVE/pvz_service/internal/models"
-	"github.com/sirupsen/logrus"
+	"github.com/senorUVE/pvz_service/internal/pagination"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type Repository struct {
@@ -49,13 +50,27 @@ func NewRepository(config DBConfig) (*Repository, error) {
 	}, nil
 }
 
+const (
+	foreignKeyViolation pq.ErrorCode = "23503"
+	uniqueViolation     pq.ErrorCode = "23505"
+)
+
+func isViolation(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
+
 func (r *Repository) Close() error {
 	return r.db.Close()
 }
 
+func (r *Repository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
 func (r *Repository) GetUser(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.QueryRowxContext(ctx, getUserByEmail, email).StructScan(&user)
+	err := r.conn(ctx).QueryRowxContext(ctx, getUserByEmail, email).StructScan(&user)
 
 	if err != nil && errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrUserNotFound
@@ -67,7 +82,10 @@ func (r *Repository) GetUser(ctx context.Context, email string) (*models.User, e
 
 func (r *Repository) CreateUser(ctx context.Context, email, password, role string) (uuid.UUID, error) {
 	newUUID := uuid.New()
-	err := r.db.QueryRowxContext(ctx, createUser, newUUID, email, password, role).Scan(&newUUID)
+	err := r.conn(ctx).QueryRowxContext(ctx, createUser, newUUID, email, password, role).Scan(&newUUID)
+	if isViolation(err, uniqueViolation) {
+		return uuid.Nil, ErrUserExists
+	}
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -76,17 +94,64 @@ func (r *Repository) CreateUser(ctx context.Context, email, password, role strin
 
 func (r *Repository) CreatePvz(ctx context.Context, pvz models.PVZ) (*dto.PvzCreateResponse, error) {
 	newUUID := uuid.New()
-	err := r.db.QueryRowxContext(ctx, createPVZ, newUUID, pvz.RegistrationDate, pvz.City).Scan(&newUUID)
+	err := r.conn(ctx).QueryRowxContext(ctx, createPVZ, newUUID, pvz.RegistrationDate, pvz.City, pvz.ExternalCode).Scan(&newUUID)
 	if err != nil {
+		if isViolation(err, uniqueViolation) {
+			return nil, fmt.Errorf("external code %q: %w", pvz.ExternalCode, ErrPvzExternalCodeExists)
+		}
 		return nil, fmt.Errorf("failed to create pvz: %w", err)
 	}
 	return &dto.PvzCreateResponse{
 		Id:               newUUID,
 		RegistrationDate: pvz.RegistrationDate,
 		City:             string(pvz.City),
+		ExternalCode:     pvz.ExternalCode,
 	}, nil
 }
 
+// ImportPvz inserts pvzs in one transaction and reports which of them were
+// created; PVZs whose external code is already taken are skipped. With
+// dryRun the transaction is rolled back, so the result is what a real
+// import would do at this moment.
+func (r *Repository) ImportPvz(ctx context.Context, pvzs []models.PVZ, dryRun bool) ([]bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	created := make([]bool, len(pvzs))
+	for i, pvz := range pvzs {
+		var id uuid.UUID
+		err := tx.QueryRowxContext(ctx, importPVZ, pvz.Id, pvz.RegistrationDate, pvz.City, pvz.ExternalCode).Scan(&id)
+		switch {
+		case err == nil:
+			created[i] = true
+		case !errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("failed to import pvz: %w", err)
+		}
+	}
+
+	if dryRun {
+		return created, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return created, nil
+}
+
+func (r *Repository) GetPvzById(ctx context.Context, pvzId uuid.UUID) (*dto.PVZResponse, error) {
+	var pvz dto.PVZResponse
+	if err := r.conn(ctx).GetContext(ctx, &pvz, getPvzById, pvzId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("pvz %s: %w", pvzId, ErrPVZNotFound)
+		}
+		return nil, fmt.Errorf("failed to get pvz: %w", err)
+	}
+	return &pvz, nil
+}
+
 func (r *Repository) CreateReception(ctx context.Context, pvzId uuid.UUID) (*dto.CreateReceptionResponse, error) {
 	newUUID := uuid.New()
 	currentTime := time.Now().UTC().Truncate(time.Second)
@@ -95,7 +160,10 @@ func (r *Repository) CreateReception(ctx context.Context, pvzId uuid.UUID) (*dto
 		DateTime time.Time
 		Status   string
 	}
-	err := r.db.QueryRowxContext(ctx, createReception, newUUID, currentTime, pvzId).Scan(&createdReception.ID, &createdReception.DateTime, &createdReception.Status)
+	err := r.conn(ctx).QueryRowxContext(ctx, createReception, newUUID, currentTime, pvzId).Scan(&createdReception.ID, &createdReception.DateTime, &createdReception.Status)
+	if isViolation(err, foreignKeyViolation) {
+		return nil, fmt.Errorf("pvz %s: %w", pvzId, ErrPVZNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create reception: %w", err)
 	}
@@ -110,7 +178,7 @@ func (r *Repository) CreateReception(ctx context.Context, pvzId uuid.UUID) (*dto
 func (r *Repository) CreateProduct(ctx context.Context, typeOf string, receptionId uuid.UUID) (*dto.AddProductResponse, error) {
 	newUUID := uuid.New()
 	currentTime := time.Now().UTC().Truncate(time.Second)
-	err := r.db.QueryRowxContext(ctx, createProduct, newUUID, currentTime, typeOf, receptionId).Scan(&newUUID)
+	err := r.conn(ctx).QueryRowxContext(ctx, createProduct, newUUID, currentTime, typeOf, receptionId).Scan(&newUUID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
@@ -124,7 +192,7 @@ func (r *Repository) CreateProduct(ctx context.Context, typeOf string, reception
 
 func (r *Repository) GetActiveReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
 	var reception models.Reception
-	err := r.db.GetContext(ctx, &reception, getActiveReception, pvzID)
+	err := r.conn(ctx).GetContext(ctx, &reception, getActiveReception, pvzID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("no active reception for pvz %s: %w", pvzID, ErrNoActiveReception)
@@ -136,133 +204,390 @@ func (r *Repository) GetActiveReception(ctx context.Context, pvzID uuid.UUID) (*
 }
 
 func (r *Repository) CloseReception(ctx context.Context, pvzId uuid.UUID) (*dto.CloseLastReceptionResponse, error) {
-	//newUUID := uuid.New()
-	//currentTime := time.Now().UTC()
-	var closedReception struct {
-		Id       uuid.UUID
-		DateTime time.Time `db:"date_time"`
-		PvzId    uuid.UUID `db:"pvz_id"`
-		Status   string
+	var closedReception dto.CloseLastReceptionResponse
+	err := r.conn(ctx).QueryRowxContext(ctx, closeLastReception, pvzId).StructScan(&closedReception)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("pvz %s has no receptions: %w", pvzId, ErrReceptionNotFound)
 	}
-	err := r.db.QueryRowxContext(ctx, closeLastReception, pvzId).StructScan(&closedReception)
 	if err != nil {
 		return nil, fmt.Errorf("failed to close reception: %w", err)
 	}
-	return &dto.CloseLastReceptionResponse{
-		Id:       closedReception.Id,
-		DateTime: closedReception.DateTime,
-		PvzId:    closedReception.PvzId,
-		Status:   closedReception.Status,
-	}, nil
+	return &closedReception, nil
 }
 
-func (r *Repository) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error {
-	const ab = "internal.repository.DeleteLastProduct"
-	tx, err := r.db.BeginTxx(ctx, nil)
-	defer func() {
-		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
-			logrus.WithFields(logrus.Fields{"event": ab}).Error(err)
+func (r *Repository) DeleteLastProduct(ctx context.Context, pvzID, userId uuid.UUID) (*dto.ProductResponse, error) {
+	var product dto.ProductResponse
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		q := r.conn(ctx)
+
+		var receptionId uuid.UUID
+		err := q.QueryRowxContext(ctx, getProductFromReception, pvzID).Scan(&receptionId)
+		if err != nil {
+			return fmt.Errorf("failed to find active reception: %w", err)
 		}
-	}()
 
+		err = q.QueryRowxContext(ctx, deleteProduct, receptionId, nullableUUID(userId)).
+			Scan(&product.Id, &product.DateTime, &product.Type, &product.ReceptionId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("nothing to delete in reception %s: %w", receptionId, ErrProductNotFound)
+			}
+			return fmt.Errorf("failed to delete product: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return &product, nil
+}
 
-	var receptionId uuid.UUID
-	err = tx.QueryRowxContext(ctx, getProductFromReception, pvzID).Scan(&receptionId)
+func (r *Repository) RestoreLastProduct(ctx context.Context, pvzID, userId uuid.UUID) (*dto.ProductResponse, error) {
+	var product dto.ProductResponse
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		q := r.conn(ctx)
+
+		var receptionId uuid.UUID
+		err := q.QueryRowxContext(ctx, getProductFromReception, pvzID).Scan(&receptionId)
+		if err != nil {
+			return fmt.Errorf("failed to find active reception: %w", err)
+		}
+
+		err = q.QueryRowxContext(ctx, restoreProduct, receptionId, nullableUUID(userId)).
+			Scan(&product.Id, &product.DateTime, &product.Type, &product.ReceptionId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("nothing to restore in reception %s: %w", receptionId, ErrProductNotFound)
+			}
+			return fmt.Errorf("failed to restore product: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to find active reception: %w", err)
+		return nil, err
 	}
-	_, err = tx.ExecContext(ctx, deleteProduct, receptionId)
+	return &product, nil
+}
+
+func nullableUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
 
+func (r *Repository) GetPvz(ctx context.Context, filter dto.GetPvzRequest) (*dto.GetPvzResponse, error) {
+	var (
+		cursor *pagination.Cursor
+		offset int
+		err    error
+	)
+	response := &dto.GetPvzResponse{
+		Items:  []*dto.PVZWithReceptions{},
+		Limit:  filter.Limit,
+		Cursor: filter.Cursor,
+	}
+	if filter.Cursor != "" {
+		cursor, err = pagination.DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		response.Page = filter.Page
+		offset = (filter.Page - 1) * filter.Limit
+	}
+
+	query, args := buildPVZCountQuery(filter)
+	if err := r.conn(ctx).QueryRowxContext(ctx, query, args...).Scan(&response.Total); err != nil {
+		return nil, fmt.Errorf("failed to count pvz: %w", err)
+	}
+
+	query, args = buildPVZPageQuery(filter, cursor, offset)
+	rows, err := r.conn(ctx).QueryxContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete product: %w", err)
+		return nil, fmt.Errorf("failed to query pvz list: %w", err)
 	}
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	defer rows.Close()
+
+	var sortKeys []string
+	for rows.Next() {
+		var sortKey string
+		pvz := &dto.PVZWithReceptions{}
+		if filter.Include != dto.IncludeNone {
+			pvz.Receptions = []dto.ReceptionWithProducts{}
+		}
+		if err := rows.Scan(&pvz.PVZ.Id, &pvz.PVZ.RegistrationDate, &pvz.PVZ.City, &sortKey); err != nil {
+			return nil, err
+		}
+		response.Items = append(response.Items, pvz)
+		sortKeys = append(sortKeys, sortKey)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pvz list: %w", err)
 	}
 
-	return nil
+	hasMore := len(response.Items) > filter.Limit
+	if hasMore {
+		response.Items = response.Items[:filter.Limit]
+		sortKeys = sortKeys[:filter.Limit]
+	}
+	hasNext, hasPrev := hasMore, cursor != nil
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(response.Items)
+		slices.Reverse(sortKeys)
+		hasNext, hasPrev = true, hasMore
+	}
+	if len(response.Items) == 0 {
+		return response, nil
+	}
+
+	edge := pagination.Cursor{Sort: filter.Sort, Desc: filter.Order == dto.OrderDesc}
+	if hasNext {
+		next := edge
+		next.Value, next.Id = sortKeys[len(sortKeys)-1], response.Items[len(response.Items)-1].PVZ.Id
+		response.NextCursor = next.Encode()
+	}
+	if hasPrev {
+		prev := edge
+		prev.Value, prev.Id, prev.Backward = sortKeys[0], response.Items[0].PVZ.Id, true
+		response.PrevCursor = prev.Encode()
+	}
+
+	pvzMap := make(map[uuid.UUID]*dto.PVZWithReceptions, len(response.Items))
+	pvzIds := make(pq.StringArray, 0, len(response.Items))
+	for _, pvz := range response.Items {
+		pvzMap[pvz.PVZ.Id] = pvz
+		pvzIds = append(pvzIds, pvz.PVZ.Id.String())
+	}
+
+	if err := r.loadSummaries(ctx, pvzIds, pvzMap, filter); err != nil {
+		return nil, err
+	}
+	if filter.Include != dto.IncludeNone {
+		if err := r.loadReceptions(ctx, pvzIds, pvzMap, filter); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// GetPvzReceptions loads receptions of several PVZ in one query, applying the
+// reception and product filters of the list request.
+func (r *Repository) GetPvzReceptions(ctx context.Context, pvzIds []uuid.UUID, filter dto.GetPvzRequest) (map[uuid.UUID][]dto.ReceptionWithProducts, error) {
+	pvzMap := make(map[uuid.UUID]*dto.PVZWithReceptions, len(pvzIds))
+	ids := make(pq.StringArray, 0, len(pvzIds))
+	for _, id := range pvzIds {
+		if _, ok := pvzMap[id]; ok {
+			continue
+		}
+		pvzMap[id] = &dto.PVZWithReceptions{Receptions: []dto.ReceptionWithProducts{}}
+		ids = append(ids, id.String())
+	}
+
+	result := make(map[uuid.UUID][]dto.ReceptionWithProducts, len(pvzMap))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	if err := r.loadReceptions(ctx, ids, pvzMap, filter); err != nil {
+		return nil, err
+	}
+	for id, pvz := range pvzMap {
+		result[id] = pvz.Receptions
+	}
+	return result, nil
 }
 
-func (r *Repository) GetPvz(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]*dto.PVZWithReceptions, error) {
-	offset := (page - 1) * limit
-	rows, err := r.db.QueryxContext(ctx, getPVZWithReceptions, startDate, endDate, limit, offset)
+func (r *Repository) loadSummaries(ctx context.Context, pvzIds pq.StringArray, pvzMap map[uuid.UUID]*dto.PVZWithReceptions, filter dto.GetPvzRequest) error {
+	query, args := buildPVZSummaryQuery(filter, pvzIds)
+	rows, err := r.conn(ctx).QueryxContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query pvz list: %w", err)
+		return fmt.Errorf("failed to query pvz summaries: %w", err)
 	}
 	defer rows.Close()
-	pvzMap := make(map[uuid.UUID]*dto.PVZWithReceptions)
+
 	for rows.Next() {
 		var (
-			pvzId            uuid.UUID
-			registrationDate time.Time
-			city             string
+			pvzId   uuid.UUID
+			summary dto.PVZSummary
+		)
+		if err := rows.Scan(&pvzId, &summary.Receptions, &summary.OpenReceptions, &summary.Products); err != nil {
+			return err
+		}
+		if pvz, ok := pvzMap[pvzId]; ok {
+			pvz.Summary = summary
+		}
+	}
+	return rows.Err()
+}
+
+func (r *Repository) loadReceptions(ctx context.Context, pvzIds pq.StringArray, pvzMap map[uuid.UUID]*dto.PVZWithReceptions, filter dto.GetPvzRequest) error {
+	withProducts := filter.Include != dto.IncludeReceptions
+	query, args := buildPVZReceptionsQuery(filter, pvzIds, withProducts)
+	rows, err := r.conn(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query receptions: %w", err)
+	}
+	defer rows.Close()
 
-			recId       uuid.NullUUID
-			recDateTime sql.NullTime
-			recStatus   sql.NullString
+	for rows.Next() {
+		var (
+			reception dto.ReceptionResponse
 
-			prodId       uuid.NullUUID
-			prodDateTime sql.NullTime
-			prodType     sql.NullString
+			prodId        uuid.NullUUID
+			prodDateTime  sql.NullTime
+			prodType      sql.NullString
+			prodDeletedAt *time.Time
+			prodDeletedBy *uuid.UUID
 		)
-		err = rows.Scan(&pvzId, &registrationDate, &city, &recId, &recDateTime, &recStatus, &prodId, &prodDateTime, &prodType)
+		dest := []any{&reception.Id, &reception.DateTime, &reception.PvzId, &reception.Status}
+		if withProducts {
+			dest = append(dest, &prodId, &prodDateTime, &prodType, &prodDeletedAt, &prodDeletedBy)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		pvz, ok := pvzMap[reception.PvzId]
+		if !ok {
+			continue
+		}
+		last := len(pvz.Receptions) - 1
+		if last < 0 || pvz.Receptions[last].Reception.Id != reception.Id {
+			withReception := dto.ReceptionWithProducts{Reception: reception}
+			if withProducts {
+				withReception.Products = []dto.ProductResponse{}
+			}
+			pvz.Receptions = append(pvz.Receptions, withReception)
+			last++
+		}
+		if prodId.Valid {
+			pvz.Receptions[last].Products = append(pvz.Receptions[last].Products, dto.ProductResponse{
+				Id:          prodId.UUID,
+				DateTime:    prodDateTime.Time,
+				Type:        prodType.String,
+				ReceptionId: reception.Id,
+				DeletedAt:   prodDeletedAt,
+				DeletedBy:   prodDeletedBy,
+			})
+		}
+	}
+	return rows.Err()
+}
+
+func scanProductWithReception(row sqlx.ColScanner) (*dto.ProductLabel, error) {
+	var label dto.ProductLabel
+	err := row.Scan(
+		&label.Product.Id, &label.Product.DateTime, &label.Product.Type, &label.Product.DeletedAt, &label.Product.DeletedBy,
+		&label.Reception.Id, &label.Reception.DateTime, &label.Reception.Status,
+		&label.PVZ.Id, &label.PVZ.RegistrationDate, &label.PVZ.City,
+	)
+	if err != nil {
+		return nil, err
+	}
+	label.Product.ReceptionId = label.Reception.Id
+	label.Reception.PvzId = label.PVZ.Id
+	return &label, nil
+}
+
+func (r *Repository) GetProductLabel(ctx context.Context, productId uuid.UUID) (*dto.ProductLabel, error) {
+	label, err := scanProductWithReception(r.conn(ctx).QueryRowxContext(ctx, getProductWithReception, productId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product %s: %w", productId, ErrProductNotFound)
+		}
+		return nil, fmt.Errorf("failed to get product label: %w", err)
+	}
+	// Deleted products are left out of reception labels as well.
+	if label.Product.DeletedAt != nil {
+		return nil, fmt.Errorf("product %s is deleted: %w", productId, ErrProductNotFound)
+	}
+	return label, nil
+}
+
+func (r *Repository) GetReceptionLabels(ctx context.Context, receptionId uuid.UUID) ([]*dto.ProductLabel, error) {
+	rows, err := r.conn(ctx).QueryxContext(ctx, getReceptionLabels, receptionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reception labels: %w", err)
+	}
+	defer rows.Close()
+
+	var labels []*dto.ProductLabel
+	for rows.Next() {
+		label, err := scanProductWithReception(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		pvzRec, exists := pvzMap[pvzId]
+		labels = append(labels, label)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(labels) == 0 {
+		var exists bool
+		if err := r.conn(ctx).QueryRowxContext(ctx, receptionExists, receptionId).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to check reception: %w", err)
+		}
 		if !exists {
-			pvzRec = &dto.PVZWithReceptions{
-				PVZ: dto.PVZResponse{
-					Id:               pvzId,
-					RegistrationDate: registrationDate,
-					City:             city,
-				},
-				Receptions: []dto.ReceptionWithProducts{},
-			}
-			pvzMap[pvzId] = pvzRec
+			return nil, fmt.Errorf("reception %s: %w", receptionId, ErrReceptionNotFound)
 		}
-		if recId.Valid {
-			var recPtr *dto.ReceptionWithProducts
-			for i := range pvzRec.Receptions {
-				if pvzRec.Receptions[i].Reception.Id == recId.UUID {
-					recPtr = &pvzRec.Receptions[i]
-					break
-				}
-			}
+		return nil, fmt.Errorf("reception %s: %w", receptionId, ErrReceptionEmpty)
+	}
+	return labels, nil
+}
 
-			if recPtr == nil {
-				newRec := dto.ReceptionWithProducts{
-					Reception: dto.ReceptionResponse{
-						Id:       recId.UUID,
-						DateTime: recDateTime.Time,
-						PvzId:    pvzId,
-						Status:   recStatus.String,
-					},
-					Products: []dto.ProductResponse{},
-				}
-				pvzRec.Receptions = append(pvzRec.Receptions, newRec)
-				recPtr = &pvzRec.Receptions[len(pvzRec.Receptions)-1]
-			}
-			if prodId.Valid {
-				product := dto.ProductResponse{
-					Id:          prodId.UUID,
-					DateTime:    prodDateTime.Time,
-					Type:        prodType.String,
-					ReceptionId: recId.UUID,
-				}
-				recPtr.Products = append(recPtr.Products, product)
-			}
+func (r *Repository) GetProductDetails(ctx context.Context, productId uuid.UUID) (*dto.ProductDetailsResponse, error) {
+	product, err := scanProductWithReception(r.conn(ctx).QueryRowxContext(ctx, getProductWithReception, productId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product %s: %w", productId, ErrProductNotFound)
 		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
-	var result []*dto.PVZWithReceptions
-	for _, pvz := range pvzMap {
-		result = append(result, pvz)
+
+	timeline := []dto.ProductEventResponse{}
+	if err := r.conn(ctx).SelectContext(ctx, &timeline, getProductEvents, productId); err != nil {
+		return nil, fmt.Errorf("failed to get product events: %w", err)
 	}
-	return result, nil
+
+	var lastEvent models.ProductEventType
+	if len(timeline) > 0 {
+		lastEvent = models.ProductEventType(timeline[len(timeline)-1].Type)
+	}
+
+	return &dto.ProductDetailsResponse{
+		Product:   product.Product,
+		Reception: product.Reception,
+		PVZ:       product.PVZ,
+		Status:    models.CurrentProductStatus(models.Status(product.Reception.Status), lastEvent).String(),
+		Timeline:  timeline,
+	}, nil
+}
+
+// LockProduct locks a product until the transaction in ctx ends, so that a
+// change decided on its timeline cannot race another one.
+func (r *Repository) LockProduct(ctx context.Context, productId uuid.UUID) error {
+	var id uuid.UUID
+	if err := r.conn(ctx).GetContext(ctx, &id, lockProduct, productId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("product %s: %w", productId, ErrProductNotFound)
+		}
+		return fmt.Errorf("failed to lock product: %w", err)
+	}
+	return nil
+}
+
+// AddProductEvent appends an event to the timeline of a product. pvzId is
+// only set for moved events.
+func (r *Repository) AddProductEvent(ctx context.Context, productId uuid.UUID, eventType models.ProductEventType, userId uuid.UUID, pvzId *uuid.UUID) error {
+	result, err := r.conn(ctx).ExecContext(ctx, addProductEvent, productId, eventType.String(), userId, pvzId)
+	if err != nil {
+		return fmt.Errorf("failed to add product event: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to add product event: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("product %s: %w", productId, ErrProductNotFound)
+	}
+	return nil
 }
 
 func (r *Repository) DummyLogin(ctx context.Context, role string) (*models.User, error) {
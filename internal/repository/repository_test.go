@@ -3,15 +3,19 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/senorUVE/pvz_service/internal/dto"
 	"github.com/senorUVE/pvz_service/internal/models"
+	"github.com/senorUVE/pvz_service/internal/pagination"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -101,6 +105,21 @@ func TestRepository_CreateUser(t *testing.T) {
 				assert.Equal(t, expectedId, id)
 			},
 		},
+		{
+			name:     "duplicate email",
+			email:    "testemail@mail.ru",
+			password: "testpassword",
+			role:     "moderator",
+			mockExpect: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(createUser)).
+					WithArgs(sqlmock.AnyArg(), "testemail@mail.ru", "testpassword", "moderator").
+					WillReturnError(&pq.Error{Code: uniqueViolation})
+			},
+			expectedResp: func(t *testing.T, id uuid.UUID, err error) {
+				assert.ErrorIs(t, err, ErrUserExists)
+				assert.Equal(t, uuid.Nil, id)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -136,7 +155,7 @@ func TestRepository_CreatePvz(t *testing.T) {
 			},
 			mockExpect: func() {
 				mock.ExpectQuery(regexp.QuoteMeta(createPVZ)).
-					WithArgs(sqlmock.AnyArg(), testTime, "Москва").
+					WithArgs(sqlmock.AnyArg(), testTime, "Москва", "").
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedId))
 			},
 			expectedResp: func(t *testing.T, resp *dto.PvzCreateResponse, err error) {
@@ -157,6 +176,30 @@ func TestRepository_CreatePvz(t *testing.T) {
 	}
 }
 
+func TestRepository_GetPvzById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	pvzId := uuid.New()
+	testTime := time.Now().UTC().Truncate(time.Second)
+
+	mock.ExpectQuery(regexp.QuoteMeta(getPvzById)).
+		WithArgs(pvzId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).AddRow(pvzId, testTime, "Казань"))
+	pvz, err := repo.GetPvzById(context.Background(), pvzId)
+	require.NoError(t, err)
+	assert.Equal(t, dto.PVZResponse{Id: pvzId, RegistrationDate: testTime, City: "Казань"}, *pvz)
+
+	mock.ExpectQuery(regexp.QuoteMeta(getPvzById)).
+		WithArgs(pvzId).
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetPvzById(context.Background(), pvzId)
+	assert.ErrorIs(t, err, ErrPVZNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRepository_CreateReception(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -190,6 +233,19 @@ func TestRepository_CreateReception(t *testing.T) {
 				assert.Equal(t, "in_progress", resp.Status)
 			},
 		},
+		{
+			name:  "unknown pvz",
+			pvzId: pvzId,
+			mockExpect: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(createReception)).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), pvzId).
+					WillReturnError(&pq.Error{Code: foreignKeyViolation})
+			},
+			expectedResp: func(t *testing.T, resp *dto.CreateReceptionResponse, err error) {
+				assert.ErrorIs(t, err, ErrPVZNotFound)
+				assert.Nil(t, resp)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -263,8 +319,8 @@ func TestRepository_CloseReception(t *testing.T) {
 			name:  "success CloseReception",
 			pvzId: pvzId,
 			mockExpect: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
-					AddRow(pvzId, testTime, pvzId, "close")
+				rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "closed"}).
+					AddRow(pvzId, testTime, pvzId, "close", true)
 				mock.ExpectQuery(regexp.QuoteMeta(closeLastReception)).
 					WithArgs(pvzId).
 					WillReturnRows(rows)
@@ -273,6 +329,23 @@ func TestRepository_CloseReception(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, "close", resp.Status)
 				assert.Equal(t, pvzId, resp.PvzId)
+				assert.True(t, resp.Closed)
+			},
+		},
+		{
+			name:  "already closed",
+			pvzId: pvzId,
+			mockExpect: func() {
+				rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "closed"}).
+					AddRow(pvzId, testTime, pvzId, "close", false)
+				mock.ExpectQuery(regexp.QuoteMeta(closeLastReception)).
+					WithArgs(pvzId).
+					WillReturnRows(rows)
+			},
+			expectedResp: func(t *testing.T, resp *dto.CloseLastReceptionResponse, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "close", resp.Status)
+				assert.False(t, resp.Closed)
 			},
 		},
 	}
@@ -294,12 +367,14 @@ func TestRepository_DeleteLastProduct(t *testing.T) {
 	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
 	pvzId := uuid.MustParse("87c17529-99bb-4815-be06-900c4612902a")
 	receptionId := uuid.MustParse("97c17529-99bb-4815-be06-900c4612902a")
+	userId := uuid.MustParse("b7c17529-99bb-4815-be06-900c4612902a")
+	productId := uuid.MustParse("c7c17529-99bb-4815-be06-900c4612902a")
 
 	tests := []struct {
 		name         string
 		pvzId        uuid.UUID
 		mockExpect   func()
-		expectedResp func(*testing.T, error)
+		expectedResp func(*testing.T, *dto.ProductResponse, error)
 	}{
 		{
 			name:  "success DeleteLastProduct",
@@ -309,13 +384,33 @@ func TestRepository_DeleteLastProduct(t *testing.T) {
 				mock.ExpectQuery(regexp.QuoteMeta(getProductFromReception)).
 					WithArgs(pvzId).
 					WillReturnRows(sqlmock.NewRows([]string{"reception_id"}).AddRow(receptionId))
-				mock.ExpectExec(regexp.QuoteMeta(deleteProduct)).
-					WithArgs(receptionId).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(deleteProduct)).
+					WithArgs(receptionId, uuid.NullUUID{UUID: userId, Valid: true}).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id"}).
+						AddRow(productId, time.Now(), "обувь", receptionId))
 				mock.ExpectCommit()
 			},
-			expectedResp: func(t *testing.T, err error) {
-				assert.NoError(t, err)
+			expectedResp: func(t *testing.T, product *dto.ProductResponse, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, productId, product.Id)
+				assert.Equal(t, receptionId, product.ReceptionId)
+			},
+		},
+		{
+			name:  "nothing to delete",
+			pvzId: pvzId,
+			mockExpect: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(getProductFromReception)).
+					WithArgs(pvzId).
+					WillReturnRows(sqlmock.NewRows([]string{"reception_id"}).AddRow(receptionId))
+				mock.ExpectQuery(regexp.QuoteMeta(deleteProduct)).
+					WithArgs(receptionId, uuid.NullUUID{UUID: userId, Valid: true}).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			expectedResp: func(t *testing.T, _ *dto.ProductResponse, err error) {
+				assert.ErrorIs(t, err, ErrProductNotFound)
 			},
 		},
 		{
@@ -328,7 +423,7 @@ func TestRepository_DeleteLastProduct(t *testing.T) {
 					WillReturnError(sql.ErrNoRows)
 				mock.ExpectRollback()
 			},
-			expectedResp: func(t *testing.T, err error) {
+			expectedResp: func(t *testing.T, _ *dto.ProductResponse, err error) {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), "failed to find active reception")
 			},
@@ -338,8 +433,8 @@ func TestRepository_DeleteLastProduct(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockExpect()
-			err := repo.DeleteLastProduct(context.Background(), tt.pvzId)
-			tt.expectedResp(t, err)
+			product, err := repo.DeleteLastProduct(context.Background(), tt.pvzId, userId)
+			tt.expectedResp(t, product, err)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
@@ -407,37 +502,158 @@ func TestRepository_GetPvz(t *testing.T) {
 	defer db.Close()
 
 	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
-	pvzId := uuid.MustParse("87c17529-99bb-4815-be06-900c4612902a")
+	firstId := uuid.MustParse("87c17529-99bb-4815-be06-900c4612902a")
+	secondId := uuid.MustParse("97c17529-99bb-4815-be06-900c4612902a")
+	receptionId := uuid.MustParse("a7c17529-99bb-4815-be06-900c4612902a")
 	testTime := time.Now().UTC().Truncate(time.Second)
+	sortKey := "2025-04-01 10:00:00+00"
+	cursor := pagination.Cursor{Sort: dto.SortRegistrationDate, Value: sortKey, Id: firstId}
+	pageQuery := regexp.QuoteMeta(selectPVZPage[:strings.Index(selectPVZPage, "%s")])
+
+	pvzColumns := []string{"id", "registration_date", "city", "sort_key"}
+	summaryColumns := []string{"pvz_id", "receptions", "open_receptions", "products"}
+	receptionColumns := []string{
+		"id", "date_time", "pvz_id", "status",
+		"id", "date_time", "type", "deleted_at", "deleted_by",
+	}
 
 	tests := []struct {
 		name         string
+		filter       dto.GetPvzRequest
 		mockExpect   func()
-		expectedResp func(*testing.T, []*dto.PVZWithReceptions, error)
+		expectedResp func(*testing.T, *dto.GetPvzResponse, error)
 	}{
 		{
-			name: "success GetPvz with receptions",
+			name:   "page mode returns whole pvz and next cursor",
+			filter: dto.GetPvzRequest{StartDate: testTime, EndDate: testTime, Page: 1, Limit: 1, Sort: dto.SortRegistrationDate},
 			mockExpect: func() {
-				rows := sqlmock.NewRows([]string{
-					"pvz_id", "registration_date", "city",
-					"reception_id", "reception_date", "status",
-					"product_id", "product_date", "type",
-				}).
-					AddRow(
-						pvzId, testTime, "Москва",
-						pvzId, testTime, "closed",
-						pvzId, testTime, "электроника",
-					)
-
-				mock.ExpectQuery(regexp.QuoteMeta(getPVZWithReceptions)).
-					WithArgs(testTime, testTime, 10, 0).
-					WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(countPVZ)).
+					WithArgs(testTime, testTime).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery(pageQuery).
+					WithArgs(testTime, testTime, 2, 0).
+					WillReturnRows(sqlmock.NewRows(pvzColumns).
+						AddRow(firstId, testTime, "Москва", sortKey).
+						AddRow(secondId, testTime, "Казань", sortKey))
+				mock.ExpectQuery(regexp.QuoteMeta(selectPVZSummary)).
+					WithArgs(pq.StringArray{firstId.String()}, testTime, testTime).
+					WillReturnRows(sqlmock.NewRows(summaryColumns).AddRow(firstId, 1, 0, 2))
+				mock.ExpectQuery(regexp.QuoteMeta(selectPVZReceptions)).
+					WithArgs(pq.StringArray{firstId.String()}, testTime, testTime).
+					WillReturnRows(sqlmock.NewRows(receptionColumns).
+						AddRow(receptionId, testTime, firstId, "close", uuid.New(), testTime, "электроника", nil, nil).
+						AddRow(receptionId, testTime, firstId, "close", uuid.New(), testTime, "обувь", nil, nil))
 			},
-			expectedResp: func(t *testing.T, resp []*dto.PVZWithReceptions, err error) {
-				assert.NoError(t, err)
-				assert.Len(t, resp, 1)
-				assert.Len(t, resp[0].Receptions, 1)
-				assert.Len(t, resp[0].Receptions[0].Products, 1)
+			expectedResp: func(t *testing.T, resp *dto.GetPvzResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, resp.Items, 1)
+				assert.Equal(t, firstId, resp.Items[0].PVZ.Id)
+				require.Len(t, resp.Items[0].Receptions, 1)
+				assert.Len(t, resp.Items[0].Receptions[0].Products, 2)
+				assert.Equal(t, dto.PVZSummary{Receptions: 1, Products: 2}, resp.Items[0].Summary)
+				assert.Equal(t, cursor.Encode(), resp.NextCursor)
+				assert.Empty(t, resp.PrevCursor)
+				assert.Equal(t, 2, resp.Total)
+				assert.Equal(t, 1, resp.Page)
+			},
+		},
+		{
+			name:   "cursor mode ignores page",
+			filter: dto.GetPvzRequest{Page: 1, Limit: 10, Sort: dto.SortRegistrationDate, Cursor: cursor.Encode()},
+			mockExpect: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(countPVZ)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery(pageQuery).
+					WithArgs(sortKey, firstId, 11, 0).
+					WillReturnRows(sqlmock.NewRows(pvzColumns).AddRow(secondId, testTime, "Казань", sortKey))
+				mock.ExpectQuery(regexp.QuoteMeta(selectPVZSummary)).
+					WithArgs(pq.StringArray{secondId.String()}).
+					WillReturnRows(sqlmock.NewRows(summaryColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(selectPVZReceptions)).
+					WithArgs(pq.StringArray{secondId.String()}).
+					WillReturnRows(sqlmock.NewRows(receptionColumns))
+			},
+			expectedResp: func(t *testing.T, resp *dto.GetPvzResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, resp.Items, 1)
+				assert.Empty(t, resp.Items[0].Receptions)
+				assert.Empty(t, resp.NextCursor)
+				assert.Equal(t, pagination.Cursor{Sort: dto.SortRegistrationDate, Value: sortKey, Id: secondId, Backward: true}.Encode(), resp.PrevCursor)
+				assert.Zero(t, resp.Page)
+			},
+		},
+		{
+			name:   "backward cursor restores order",
+			filter: dto.GetPvzRequest{Page: 1, Limit: 1, Sort: dto.SortRegistrationDate, Cursor: pagination.Cursor{Sort: dto.SortRegistrationDate, Value: sortKey, Id: secondId, Backward: true}.Encode()},
+			mockExpect: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(countPVZ)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery(pageQuery).
+					WithArgs(sortKey, secondId, 2, 0).
+					WillReturnRows(sqlmock.NewRows(pvzColumns).AddRow(firstId, testTime, "Москва", sortKey))
+				mock.ExpectQuery(regexp.QuoteMeta(selectPVZSummary)).
+					WithArgs(pq.StringArray{firstId.String()}).
+					WillReturnRows(sqlmock.NewRows(summaryColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(selectPVZReceptions)).
+					WithArgs(pq.StringArray{firstId.String()}).
+					WillReturnRows(sqlmock.NewRows(receptionColumns))
+			},
+			expectedResp: func(t *testing.T, resp *dto.GetPvzResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, resp.Items, 1)
+				assert.Equal(t, cursor.Encode(), resp.NextCursor)
+				assert.Empty(t, resp.PrevCursor)
+			},
+		},
+		{
+			name:   "include none skips receptions",
+			filter: dto.GetPvzRequest{Page: 1, Limit: 10, Include: dto.IncludeNone},
+			mockExpect: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(countPVZ)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(pageQuery).
+					WithArgs(11, 0).
+					WillReturnRows(sqlmock.NewRows(pvzColumns).AddRow(firstId, testTime, "Москва", sortKey))
+				mock.ExpectQuery(regexp.QuoteMeta(selectPVZSummary)).
+					WithArgs(pq.StringArray{firstId.String()}).
+					WillReturnRows(sqlmock.NewRows(summaryColumns).AddRow(firstId, 3, 1, 10))
+			},
+			expectedResp: func(t *testing.T, resp *dto.GetPvzResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, resp.Items, 1)
+				assert.Nil(t, resp.Items[0].Receptions)
+				assert.Equal(t, dto.PVZSummary{Receptions: 3, OpenReceptions: 1, Products: 10}, resp.Items[0].Summary)
+			},
+		},
+		{
+			name:   "include receptions skips products",
+			filter: dto.GetPvzRequest{Page: 1, Limit: 10, Include: dto.IncludeReceptions},
+			mockExpect: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(countPVZ)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(pageQuery).
+					WithArgs(11, 0).
+					WillReturnRows(sqlmock.NewRows(pvzColumns).AddRow(firstId, testTime, "Москва", sortKey))
+				mock.ExpectQuery(regexp.QuoteMeta(selectPVZSummary)).
+					WithArgs(pq.StringArray{firstId.String()}).
+					WillReturnRows(sqlmock.NewRows(summaryColumns).AddRow(firstId, 1, 1, 4))
+				mock.ExpectQuery(regexp.QuoteMeta(selectPVZReceptionsOnly)).
+					WithArgs(pq.StringArray{firstId.String()}).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
+						AddRow(receptionId, testTime, firstId, "in_progress"))
+			},
+			expectedResp: func(t *testing.T, resp *dto.GetPvzResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, resp.Items[0].Receptions, 1)
+				assert.Nil(t, resp.Items[0].Receptions[0].Products)
+			},
+		},
+		{
+			name:       "invalid cursor",
+			filter:     dto.GetPvzRequest{Limit: 10, Cursor: "garbage"},
+			mockExpect: func() {},
+			expectedResp: func(t *testing.T, resp *dto.GetPvzResponse, err error) {
+				assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
 			},
 		},
 	}
@@ -445,7 +661,7 @@ func TestRepository_GetPvz(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockExpect()
-			resp, err := repo.GetPvz(context.Background(), testTime, testTime, 1, 10)
+			resp, err := repo.GetPvz(context.Background(), tt.filter)
 			tt.expectedResp(t, resp, err)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
@@ -477,3 +693,721 @@ func TestRepository_DummyLogin(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_GetProductLabel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	productId := uuid.MustParse("87c17529-99bb-4815-be06-900c4612902a")
+	receptionId := uuid.MustParse("97c17529-99bb-4815-be06-900c4612902a")
+	pvzId := uuid.MustParse("a7c17529-99bb-4815-be06-900c4612902a")
+	testTime := time.Now().UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name         string
+		mockExpect   func()
+		expectedResp func(*testing.T, *dto.ProductLabel, error)
+	}{
+		{
+			name: "success GetProductLabel",
+			mockExpect: func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "date_time", "type", "deleted_at", "deleted_by",
+					"id", "date_time", "status",
+					"id", "registration_date", "city",
+				}).AddRow(productId, testTime, "обувь", nil, nil, receptionId, testTime, "in_progress", pvzId, testTime, "Москва")
+				mock.ExpectQuery(regexp.QuoteMeta(getProductWithReception)).
+					WithArgs(productId).
+					WillReturnRows(rows)
+			},
+			expectedResp: func(t *testing.T, label *dto.ProductLabel, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, productId, label.Product.Id)
+				assert.Equal(t, receptionId, label.Product.ReceptionId)
+				assert.Equal(t, pvzId, label.Reception.PvzId)
+				assert.Equal(t, "Москва", label.PVZ.City)
+			},
+		},
+		{
+			name: "deleted product",
+			mockExpect: func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "date_time", "type", "deleted_at", "deleted_by",
+					"id", "date_time", "status",
+					"id", "registration_date", "city",
+				}).AddRow(productId, testTime, "обувь", testTime, uuid.New(), receptionId, testTime, "in_progress", pvzId, testTime, "Москва")
+				mock.ExpectQuery(regexp.QuoteMeta(getProductWithReception)).
+					WithArgs(productId).
+					WillReturnRows(rows)
+			},
+			expectedResp: func(t *testing.T, label *dto.ProductLabel, err error) {
+				assert.ErrorIs(t, err, ErrProductNotFound)
+				assert.Nil(t, label)
+			},
+		},
+		{
+			name: "product not found",
+			mockExpect: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(getProductWithReception)).
+					WithArgs(productId).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedResp: func(t *testing.T, label *dto.ProductLabel, err error) {
+				assert.ErrorIs(t, err, ErrProductNotFound)
+				assert.Nil(t, label)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockExpect()
+			label, err := repo.GetProductLabel(context.Background(), productId)
+			tt.expectedResp(t, label, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_GetReceptionLabels(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	receptionId := uuid.MustParse("97c17529-99bb-4815-be06-900c4612902a")
+	pvzId := uuid.MustParse("a7c17529-99bb-4815-be06-900c4612902a")
+	testTime := time.Now().UTC().Truncate(time.Second)
+	columns := []string{
+		"id", "date_time", "type", "deleted_at", "deleted_by",
+		"id", "date_time", "status",
+		"id", "registration_date", "city",
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(getReceptionLabels)).
+		WithArgs(receptionId).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(uuid.New(), testTime, "обувь", nil, nil, receptionId, testTime, "close", pvzId, testTime, "Казань").
+			AddRow(uuid.New(), testTime, "одежда", nil, nil, receptionId, testTime, "close", pvzId, testTime, "Казань"))
+
+	labels, err := repo.GetReceptionLabels(context.Background(), receptionId)
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+
+	for _, exists := range []bool{true, false} {
+		mock.ExpectQuery(regexp.QuoteMeta(getReceptionLabels)).
+			WithArgs(receptionId).
+			WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectQuery(regexp.QuoteMeta(receptionExists)).
+			WithArgs(receptionId).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+
+		_, err = repo.GetReceptionLabels(context.Background(), receptionId)
+		if exists {
+			assert.ErrorIs(t, err, ErrReceptionEmpty)
+		} else {
+			assert.ErrorIs(t, err, ErrReceptionNotFound)
+		}
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetProductDetails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	productId := uuid.MustParse("87c17529-99bb-4815-be06-900c4612902a")
+	receptionId := uuid.MustParse("97c17529-99bb-4815-be06-900c4612902a")
+	pvzId := uuid.MustParse("a7c17529-99bb-4815-be06-900c4612902a")
+	testTime := time.Now().UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name            string
+		receptionStatus string
+		events          []string
+		expectedStatus  string
+	}{
+		{"open reception", "in_progress", []string{"added"}, "in_reception"},
+		{"closed reception", "close", []string{"added"}, "accepted"},
+		{"deleted", "in_progress", []string{"added", "deleted"}, "deleted"},
+		{"restored", "in_progress", []string{"added", "deleted", "restored"}, "in_reception"},
+		{"moved", "close", []string{"added", "moved"}, "moved"},
+		{"issued", "close", []string{"added", "moved", "issued"}, "issued"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(getProductWithReception)).
+				WithArgs(productId).
+				WillReturnRows(sqlmock.NewRows([]string{
+					"id", "date_time", "type", "deleted_at", "deleted_by",
+					"id", "date_time", "status",
+					"id", "registration_date", "city",
+				}).AddRow(productId, testTime, "обувь", nil, nil, receptionId, testTime, tt.receptionStatus, pvzId, testTime, "Москва"))
+
+			events := sqlmock.NewRows([]string{"type", "date_time", "reception_id", "user_id", "pvz_id"})
+			for _, event := range tt.events {
+				events.AddRow(event, testTime, receptionId, nil, nil)
+			}
+			mock.ExpectQuery(regexp.QuoteMeta(getProductEvents)).
+				WithArgs(productId).
+				WillReturnRows(events)
+
+			details, err := repo.GetProductDetails(context.Background(), productId)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, details.Status)
+			assert.Len(t, details.Timeline, len(tt.events))
+			assert.Equal(t, pvzId, details.PVZ.Id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(getProductWithReception)).
+			WithArgs(productId).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetProductDetails(context.Background(), productId)
+		assert.ErrorIs(t, err, ErrProductNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_AddProductEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	ctx := context.Background()
+	productId, userId, pvzId := uuid.New(), uuid.New(), uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(lockProduct)).
+		WithArgs(productId).
+		WillReturnError(sql.ErrNoRows)
+	assert.ErrorIs(t, repo.LockProduct(ctx, productId), ErrProductNotFound)
+
+	mock.ExpectExec(regexp.QuoteMeta(addProductEvent)).
+		WithArgs(productId, "moved", userId, &pvzId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.AddProductEvent(ctx, productId, models.ProductEventMoved, userId, &pvzId))
+
+	mock.ExpectExec(regexp.QuoteMeta(addProductEvent)).
+		WithArgs(productId, "issued", userId, nil).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.AddProductEvent(ctx, productId, models.ProductEventIssued, userId, nil), ErrProductNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_RestoreLastProduct(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	pvzId := uuid.MustParse("87c17529-99bb-4815-be06-900c4612902a")
+	receptionId := uuid.MustParse("97c17529-99bb-4815-be06-900c4612902a")
+	productId := uuid.MustParse("a7c17529-99bb-4815-be06-900c4612902a")
+	userId := uuid.MustParse("b7c17529-99bb-4815-be06-900c4612902a")
+	testTime := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(getProductFromReception)).
+			WithArgs(pvzId).
+			WillReturnRows(sqlmock.NewRows([]string{"reception_id"}).AddRow(receptionId))
+		mock.ExpectQuery(regexp.QuoteMeta(restoreProduct)).
+			WithArgs(receptionId, uuid.NullUUID{UUID: userId, Valid: true}).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id"}).
+				AddRow(productId, testTime, "обувь", receptionId))
+		mock.ExpectCommit()
+
+		product, err := repo.RestoreLastProduct(context.Background(), pvzId, userId)
+		require.NoError(t, err)
+		assert.Equal(t, productId, product.Id)
+		assert.Equal(t, receptionId, product.ReceptionId)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nothing to restore", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(getProductFromReception)).
+			WithArgs(pvzId).
+			WillReturnRows(sqlmock.NewRows([]string{"reception_id"}).AddRow(receptionId))
+		mock.ExpectQuery(regexp.QuoteMeta(restoreProduct)).
+			WithArgs(receptionId, uuid.NullUUID{UUID: userId, Valid: true}).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.RestoreLastProduct(context.Background(), pvzId, userId)
+		assert.ErrorIs(t, err, ErrProductNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_ReserveIdempotencyKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	record := dto.IdempotencyRecord{UserId: uuid.New(), Key: "key", RequestHash: "hash"}
+
+	t.Run("free key", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(reserveIdempotencyKey)).
+			WithArgs(record.UserId, record.Key, record.RequestHash, float64(3600)).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(record.UserId))
+
+		stored, err := repo.ReserveIdempotencyKey(context.Background(), record, time.Hour)
+		assert.NoError(t, err)
+		assert.Nil(t, stored)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("taken key", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(reserveIdempotencyKey)).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(regexp.QuoteMeta(getIdempotencyKey)).
+			WithArgs(record.UserId, record.Key).
+			WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "content_type", "response_body"}).
+				AddRow("hash", 201, "application/json", []byte(`{}`)))
+
+		stored, err := repo.ReserveIdempotencyKey(context.Background(), record, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, 201, stored.StatusCode)
+		assert.Equal(t, "application/json", stored.ContentType)
+		assert.Equal(t, []byte(`{}`), stored.Body)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pending key", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(reserveIdempotencyKey)).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(regexp.QuoteMeta(getIdempotencyKey)).
+			WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "content_type", "response_body"}).
+				AddRow("hash", nil, nil, nil))
+
+		stored, err := repo.ReserveIdempotencyKey(context.Background(), record, time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, stored.StatusCode)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_GetPvzReceptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	firstId := uuid.New()
+	secondId := uuid.New()
+	receptionId := uuid.New()
+	testTime := time.Now().UTC().Truncate(time.Second)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectPVZReceptions)).
+		WithArgs(pq.StringArray{firstId.String(), secondId.String()}, "close").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "date_time", "pvz_id", "status",
+			"id", "date_time", "type", "deleted_at", "deleted_by",
+		}).AddRow(receptionId, testTime, firstId, "close", uuid.New(), testTime, "обувь", nil, nil))
+
+	result, err := repo.GetPvzReceptions(context.Background(), []uuid.UUID{firstId, secondId, firstId}, dto.GetPvzRequest{ReceptionStatus: "close"})
+	require.NoError(t, err)
+	require.Len(t, result[firstId], 1)
+	assert.Len(t, result[firstId][0].Products, 1)
+	assert.NotNil(t, result[secondId])
+	assert.Empty(t, result[secondId])
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	result, err = repo.GetPvzReceptions(context.Background(), nil, dto.GetPvzRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestRepository_Webhooks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	ctx := context.Background()
+	webhookId := uuid.New()
+	deliveryId := uuid.New()
+	testTime := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("list", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(listWebhooks)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url", "event_types", "created_by", "created_at"}).
+				AddRow(webhookId, "https://example.com", "{product.added,reception.closed}", nil, testTime))
+
+		webhooks, err := repo.ListWebhooks(ctx)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		assert.Equal(t, []string{"product.added", "reception.closed"}, webhooks[0].EventTypes)
+		assert.Nil(t, webhooks[0].CreatedBy)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete missing", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(deleteWebhook)).
+			WithArgs(webhookId).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.DeleteWebhook(ctx, webhookId), ErrWebhookNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("claim", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(claimWebhookDeliveries)).
+			WithArgs(10, float64(20)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "payload", "attempts", "url", "secret"}).
+				AddRow(deliveryId, "product.added", []byte(`{}`), 2, "https://example.com", "secret"))
+
+		messages, err := repo.ClaimWebhookDeliveries(ctx, 10, 20*time.Second)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, 2, messages[0].Attempts)
+		assert.Equal(t, "secret", messages[0].Secret)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("save attempt", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(saveWebhookAttempt)).
+			WithArgs(deliveryId, "pending", 3, testTime, int64(503), "receiver responded with 503", nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.SaveWebhookAttempt(ctx, dto.WebhookAttempt{
+			DeliveryId:    deliveryId,
+			Status:        "pending",
+			Attempts:      3,
+			NextAttemptAt: testTime,
+			StatusCode:    503,
+			Error:         "receiver responded with 503",
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("save stale attempt", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(saveWebhookAttempt)).
+			WithArgs(deliveryId, "delivered", 3, testTime, int64(200), nil, testTime).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.SaveWebhookAttempt(ctx, dto.WebhookAttempt{
+			DeliveryId:    deliveryId,
+			Status:        "delivered",
+			Attempts:      3,
+			NextAttemptAt: testTime,
+			StatusCode:    200,
+			DeliveredAt:   &testTime,
+		})
+		assert.ErrorIs(t, err, ErrWebhookAttemptStale)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("redeliver missing", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(redeliverWebhook)).
+			WithArgs(deliveryId).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(regexp.QuoteMeta(webhookDeliveryExists)).
+			WithArgs(deliveryId).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		_, err := repo.RedeliverWebhook(ctx, deliveryId)
+		assert.ErrorIs(t, err, ErrWebhookDeliveryNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("redeliver pending", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(redeliverWebhook)).
+			WithArgs(deliveryId).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(regexp.QuoteMeta(webhookDeliveryExists)).
+			WithArgs(deliveryId).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		_, err := repo.RedeliverWebhook(ctx, deliveryId)
+		assert.ErrorIs(t, err, ErrWebhookDeliveryPending)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_StreamReceptionReport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	pvzId := uuid.New()
+	productId := uuid.New()
+	testTime := time.Now().UTC().Truncate(time.Second)
+	filter := dto.ReceptionReportFilter{StartDate: testTime.Add(-time.Hour), Cities: []string{"Казань"}, PvzIds: []uuid.UUID{pvzId}}
+	columns := []string{"id", "city", "id", "date_time", "status", "id", "date_time", "type"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DECLARE reception_report NO SCROLL CURSOR FOR")).
+		WithArgs(filter.StartDate, pq.StringArray{"Казань"}, pq.StringArray{pvzId.String()}).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FETCH 500 FROM reception_report")).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(pvzId, "Казань", uuid.New(), testTime, "close", productId, testTime, "обувь").
+			AddRow(pvzId, "Казань", uuid.New(), testTime, "in_progress", nil, nil, nil))
+	mock.ExpectCommit()
+
+	var rows []dto.ReceptionReportRow
+	err = repo.StreamReceptionReport(context.Background(), filter, func(row dto.ReceptionReportRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, productId, *rows[0].ProductId)
+	assert.Equal(t, "обувь", *rows[0].ProductType)
+	assert.Nil(t, rows[1].ProductId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	t.Run("callback error stops the stream", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("DECLARE reception_report")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("FETCH 500 FROM reception_report")).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(pvzId, "Казань", uuid.New(), testTime, "close", nil, nil, nil))
+		mock.ExpectRollback()
+
+		stop := errors.New("client gone")
+		err := repo.StreamReceptionReport(context.Background(), dto.ReceptionReportFilter{}, func(dto.ReceptionReportRow) error {
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_ImportPvz(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	testTime := time.Now().UTC().Truncate(time.Second)
+	pvzs := []models.PVZ{
+		{Id: uuid.New(), RegistrationDate: testTime, City: "Москва", ExternalCode: "MSK-1"},
+		{Id: uuid.New(), RegistrationDate: testTime, City: "Казань", ExternalCode: "KZN-1"},
+	}
+	expectInserts := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(importPVZ)).
+			WithArgs(pvzs[0].Id, testTime, pvzs[0].City, "MSK-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(pvzs[0].Id))
+		mock.ExpectQuery(regexp.QuoteMeta(importPVZ)).
+			WithArgs(pvzs[1].Id, testTime, pvzs[1].City, "KZN-1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	}
+
+	t.Run("import", func(t *testing.T) {
+		expectInserts()
+		mock.ExpectCommit()
+
+		created, err := repo.ImportPvz(context.Background(), pvzs, false)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false}, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("dry run rolls back", func(t *testing.T) {
+		expectInserts()
+		mock.ExpectRollback()
+
+		created, err := repo.ImportPvz(context.Background(), pvzs, true)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false}, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_InTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	pvzId := uuid.New()
+	failed := errors.New("conflict")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(getActiveReception)).WithArgs(pvzId).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec(regexp.QuoteMeta("ROLLBACK TO SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT sp_2")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(getProductFromReception)).WithArgs(pvzId).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectQuery("WITH deleted AS").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id"}).
+			AddRow(uuid.New(), time.Now(), "обувь", uuid.New()))
+	mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT sp_2")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = repo.InTx(context.Background(), func(ctx context.Context) error {
+		err := repo.InTx(ctx, func(ctx context.Context) error {
+			if _, err := repo.GetActiveReception(ctx, pvzId); err != nil {
+				return failed
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, failed)
+
+		// DeleteLastProduct joins the ambient transaction instead of beginning its own.
+		_, err = repo.DeleteLastProduct(ctx, pvzId, uuid.Nil)
+		return err
+	})
+	require.NoError(t, err)
+}
+
+func TestRepository_SyncResult(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	userId, receptionId := uuid.New(), uuid.New()
+	op := dto.SyncOperation{ClientId: uuid.New(), ClientTime: time.Now(), Type: "open_reception", PvzId: uuid.New()}
+	result := dto.SyncResult{ClientId: op.ClientId, Status: "applied", ReceptionId: &receptionId}
+
+	mock.ExpectQuery(regexp.QuoteMeta(getSyncOperation)).WithArgs(op.ClientId, userId).
+		WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetSyncResult(context.Background(), userId, op.ClientId)
+	assert.ErrorIs(t, err, ErrSyncOperationNotFound)
+
+	mock.ExpectExec(regexp.QuoteMeta(saveSyncOperation)).
+		WithArgs(op.ClientId, userId, op.Type, op.ClientTime, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.SaveSyncResult(context.Background(), userId, op, result))
+
+	mock.ExpectQuery(regexp.QuoteMeta(getSyncOperation)).WithArgs(op.ClientId, userId).
+		WillReturnRows(sqlmock.NewRows([]string{"result"}).
+			AddRow([]byte(`{"clientId":"` + op.ClientId.String() + `","status":"applied","receptionId":"` + receptionId.String() + `"}`)))
+	stored, err := repo.GetSyncResult(context.Background(), userId, op.ClientId)
+	require.NoError(t, err)
+	assert.Equal(t, result, *stored)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetIntakeReport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	filter := dto.IntakeReportFilter{
+		StartDate: start,
+		Cities:    []string{"Казань"},
+		GroupBy:   []models.IntakeDimension{models.IntakeByCity, models.IntakeByType, models.IntakeByWeek},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT p.city, pr.type, to_char(date_trunc('week', pr.date_time AT TIME ZONE COALESCE(tz.name, 'UTC')), 'YYYY-MM-DD'), count(pr.id)")).
+		WithArgs(pq.StringArray{"Москва", "Санкт-Петербург", "Казань"}, sqlmock.AnyArg(), start, pq.StringArray{"Казань"}).
+		WillReturnRows(sqlmock.NewRows([]string{"city", "type", "period", "products", "receptions"}).
+			AddRow("Казань", "обувь", "2025-03-03", 12, 2))
+
+	rows, err := repo.GetIntakeReport(context.Background(), filter)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Казань", *rows[0].City)
+	assert.Equal(t, "обувь", *rows[0].ProductType)
+	assert.Equal(t, "2025-03-03", *rows[0].Period)
+	assert.Nil(t, rows[0].PvzId)
+	assert.Equal(t, int64(12), rows[0].Products)
+	assert.Equal(t, int64(2), rows[0].Receptions)
+
+	t.Run("totals without dimensions", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT count\(pr.id\), count\(DISTINCT \(pr.reception_id, .*\)\)\s+FROM product pr.*WHERE pr.deleted_at IS NULL$`).
+			WillReturnRows(sqlmock.NewRows([]string{"products", "receptions"}).AddRow(40, 7))
+
+		rows, err := repo.GetIntakeReport(context.Background(), dto.IntakeReportFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []dto.IntakeReportRow{{Products: 40, Receptions: 7}}, rows)
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_RefreshRecentDailyStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	until := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := until.Add(time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(initDailyStatsState)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(lockDailyStatsState)).
+		WillReturnRows(sqlmock.NewRows([]string{"refreshed_until", "now"}).AddRow(until, now))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pvz_daily_stats")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), until.Add(-5*time.Minute), now).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(regexp.QuoteMeta(saveDailyStatsState)).WithArgs(now).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	refreshed, err := repo.RefreshRecentDailyStats(context.Background(), 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), refreshed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	t.Run("failed refresh keeps the watermark", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(initDailyStatsState)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(lockDailyStatsState)).
+			WillReturnRows(sqlmock.NewRows([]string{"refreshed_until", "now"}).AddRow(until, now))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pvz_daily_stats")).WillReturnError(errors.New("timeout"))
+		mock.ExpectRollback()
+
+		_, err := repo.RefreshRecentDailyStats(context.Background(), 5*time.Minute)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_GetDailyIntakeReport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{db: sqlx.NewDb(db, "sqlmock")}
+	firstDay := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("by type", func(t *testing.T) {
+		filter := dto.IntakeReportFilter{GroupBy: []models.IntakeDimension{models.IntakeByType, models.IntakeByMonth}}
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT s.product_type, to_char(date_trunc('month', s.day), 'YYYY-MM-DD'), COALESCE(sum(s.products_kept), 0), COALESCE(sum(s.receptions) FILTER (WHERE TRUE), 0)")).
+			WithArgs("2025-03-01", "2025-03-31").
+			WillReturnRows(sqlmock.NewRows([]string{"type", "period", "products", "receptions"}).
+				AddRow("обувь", "2025-03-01", 30, 4))
+
+		rows, err := repo.GetDailyIntakeReport(context.Background(), filter, firstDay, lastDay)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, "обувь", *rows[0].ProductType)
+		assert.Equal(t, int64(30), rows[0].Products)
+	})
+
+	t.Run("without type counts receptions once", func(t *testing.T) {
+		filter := dto.IntakeReportFilter{Cities: []string{"Казань"}, GroupBy: []models.IntakeDimension{models.IntakeByCity}}
+		mock.ExpectQuery(`FILTER \(WHERE s.product_type = ''\).*WHERE s.day <= \$1::date AND p.city = ANY\(\$2::text\[\]\) GROUP BY 1 ORDER BY 1`).
+			WithArgs("2025-03-31", pq.StringArray{"Казань"}).
+			WillReturnRows(sqlmock.NewRows([]string{"city", "products", "receptions"}).AddRow("Казань", 12, 3))
+
+		rows, err := repo.GetDailyIntakeReport(context.Background(), filter, time.Time{}, lastDay)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), rows[0].Receptions)
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
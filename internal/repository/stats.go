@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RefreshRecentDailyStats rolls up activity since the previous refresh into
+// pvz_daily_stats and moves the watermark to now. It re-reads lag before the
+// watermark as well, to catch changes that were stamped earlier than they
+// committed. Concurrent refreshes wait for each other.
+func (r *Repository) RefreshRecentDailyStats(ctx context.Context, lag time.Duration) (int64, error) {
+	var refreshed int64
+	err := r.InTx(ctx, func(ctx context.Context) error {
+		q := r.conn(ctx)
+		if _, err := q.ExecContext(ctx, initDailyStatsState); err != nil {
+			return fmt.Errorf("failed to init daily stats state: %w", err)
+		}
+
+		var until, now time.Time
+		if err := q.QueryRowxContext(ctx, lockDailyStatsState).Scan(&until, &now); err != nil {
+			return fmt.Errorf("failed to lock daily stats state: %w", err)
+		}
+
+		var err error
+		if refreshed, err = r.refreshDailyStats(ctx, until.Add(-lag), now); err != nil {
+			return err
+		}
+
+		if _, err := q.ExecContext(ctx, saveDailyStatsState, now); err != nil {
+			return fmt.Errorf("failed to save daily stats state: %w", err)
+		}
+		return nil
+	})
+	return refreshed, err
+}
+
+// BackfillDailyStats recomputes the days with activity between from and to,
+// regardless of the watermark.
+func (r *Repository) BackfillDailyStats(ctx context.Context, from, to time.Time) (int64, error) {
+	return r.refreshDailyStats(ctx, from, to)
+}
+
+func (r *Repository) refreshDailyStats(ctx context.Context, from, to time.Time) (int64, error) {
+	cities, zones := cityTimeZones()
+	result, err := r.conn(ctx).ExecContext(ctx, refreshDailyStats, cities, zones, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh daily stats: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// DailyStatsRefreshedUntil returns the watermark of the last refresh, zero
+// before the first one.
+func (r *Repository) DailyStatsRefreshedUntil(ctx context.Context) (time.Time, error) {
+	var until time.Time
+	err := r.conn(ctx).QueryRowxContext(ctx, getDailyStatsState).Scan(&until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get daily stats state: %w", err)
+	}
+	return until, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+// GetSyncResult returns the outcome stored when the user first synced the
+// operation with clientId.
+func (r *Repository) GetSyncResult(ctx context.Context, userId, clientId uuid.UUID) (*dto.SyncResult, error) {
+	var payload []byte
+	err := r.conn(ctx).QueryRowxContext(ctx, getSyncOperation, clientId, userId).Scan(&payload)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("sync operation %s: %w", clientId, ErrSyncOperationNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync operation: %w", err)
+	}
+
+	var result dto.SyncResult
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode sync operation: %w", err)
+	}
+	return &result, nil
+}
+
+func (r *Repository) SaveSyncResult(ctx context.Context, userId uuid.UUID, op dto.SyncOperation, result dto.SyncResult) error {
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode sync operation: %w", err)
+	}
+	if _, err := r.conn(ctx).ExecContext(ctx, saveSyncOperation, op.ClientId, userId, op.Type, op.ClientTime, payload); err != nil {
+		return fmt.Errorf("failed to save sync operation: %w", err)
+	}
+	return nil
+}
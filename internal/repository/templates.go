@@ -11,41 +11,308 @@ const (
 
 	userExists = `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
 
-	createPVZ = `INSERT INTO pvz (id, registration_date, city) VALUES ($1, $2, $3) RETURNING id`
+	createPVZ = `INSERT INTO pvz (id, registration_date, city, external_code) VALUES ($1, $2, $3, NULLIF($4, '')) RETURNING id`
 
-	getPVZWithReceptions = `SELECT p.id, p.registration_date, p.city,
-                                r.id, r.date_time, r.status,
-                                pr.id, pr.date_time, pr.type
-                             FROM pvz p
-                             LEFT JOIN reception r ON p.id = r.pvz_id
-                             LEFT JOIN product pr ON r.id = pr.reception_id
-                             WHERE ($1::timestamp IS NULL OR r.date_time >= $1)
-                             AND ($2::timestamp IS NULL OR r.date_time <= $2)
-                             ORDER BY p.registration_date
-                             LIMIT $3 OFFSET $4`
+	importPVZ = `INSERT INTO pvz (id, registration_date, city, external_code) VALUES ($1, $2, $3, NULLIF($4, ''))
+                 ON CONFLICT (external_code) DO NOTHING
+                 RETURNING id`
+
+	getPvzById = `SELECT id, registration_date, city FROM pvz WHERE id = $1`
+
+	selectPVZPage = `SELECT s.id, s.registration_date, s.city, s.sort_key::text
+                     FROM (SELECT p.id, p.registration_date, p.city, %s AS sort_key FROM pvz p%s) s`
+
+	countPVZ = `SELECT count(*) FROM pvz p`
+
+	selectPVZReceptionsOnly = `SELECT r.id, r.date_time, r.pvz_id, r.status FROM reception r`
+
+	selectPVZSummary = `SELECT r.pvz_id,
+                            count(DISTINCT r.id),
+                            count(DISTINCT r.id) FILTER (WHERE r.status = 'in_progress'),
+                            count(pr.id)
+                        FROM reception r
+                        LEFT JOIN product pr ON r.id = pr.reception_id`
+
+	selectPVZReceptions = `SELECT r.id, r.date_time, r.pvz_id, r.status,
+                               pr.id, pr.date_time, pr.type, pr.deleted_at, pr.deleted_by
+                           FROM reception r
+                           LEFT JOIN product pr ON r.id = pr.reception_id`
 
 	createReception = `INSERT INTO reception (id, date_time, pvz_id, status) VALUES ($1, $2, $3, 'in_progress') RETURNING id, date_time, status`
 
-	closeLastReception = `UPDATE reception 
-                            SET status = 'close' 
-                            WHERE id = (
-								SELECT r.id
-								FROM reception r
-								WHERE r.pvz_id = $1
-								ORDER BY r.date_time DESC
-								LIMIT 1
-							)
-							RETURNING id, date_time, pvz_id, status`
+	// closeLastReception reads the status under the row lock, so of two
+	// concurrent closes only one sees in_progress.
+	closeLastReception = `WITH last AS (
+                              SELECT r.id, r.status
+                              FROM reception r
+                              WHERE r.pvz_id = $1
+                              ORDER BY r.date_time DESC
+                              LIMIT 1
+                              FOR UPDATE
+                          )
+                          UPDATE reception r
+                          SET status = 'close', closed_at = COALESCE(r.closed_at, now())
+                          FROM last
+                          WHERE r.id = last.id
+                          RETURNING r.id, r.date_time, r.pvz_id, r.status, last.status = 'in_progress' AS closed`
 
 	getActiveReception = `SELECT id, date_time, pvz_id, status FROM reception WHERE pvz_id = $1 AND status = 'in_progress' LIMIT 1`
 
 	getProductFromReception = `SELECT id FROM reception WHERE pvz_id = $1 AND status = 'in_progress' FOR UPDATE`
 
-	createProduct = `INSERT INTO product (id, date_time, type, reception_id) VALUES ($1, $2, $3, $4) RETURNING id`
+	createProduct = `WITH created AS (
+                         INSERT INTO product (id, date_time, type, reception_id) VALUES ($1, $2, $3, $4)
+                         RETURNING id, date_time, reception_id
+                     )
+                     INSERT INTO product_event (id, product_id, reception_id, type, date_time)
+                     SELECT gen_random_uuid(), id, reception_id, 'added', date_time FROM created
+                     RETURNING product_id`
+
+	deleteProduct = `WITH deleted AS (
+                         UPDATE product SET deleted_at = now(), deleted_by = $2
+                         WHERE id = (
+                             SELECT id FROM product
+                             WHERE reception_id = $1 AND deleted_at IS NULL
+                             ORDER BY date_time DESC
+                             LIMIT 1
+                         )
+                         RETURNING id, date_time, type, reception_id, deleted_at, deleted_by
+                     ), event AS (
+                         INSERT INTO product_event (id, product_id, reception_id, type, date_time, user_id)
+                         SELECT gen_random_uuid(), id, reception_id, 'deleted', deleted_at, deleted_by FROM deleted
+                     )
+                     SELECT id, date_time, type, reception_id FROM deleted`
+
+	restoreProduct = `WITH restored AS (
+                          UPDATE product SET deleted_at = NULL, deleted_by = NULL
+                          WHERE id = (
+                              SELECT id FROM product
+                              WHERE reception_id = $1 AND deleted_at IS NOT NULL
+                              ORDER BY deleted_at DESC
+                              LIMIT 1
+                          )
+                          RETURNING id, date_time, type, reception_id
+                      ), event AS (
+                          INSERT INTO product_event (id, product_id, reception_id, type, date_time, user_id)
+                          SELECT gen_random_uuid(), id, reception_id, 'restored', now(), $2 FROM restored
+                      )
+                      SELECT id, date_time, type, reception_id FROM restored`
+
+	getProductWithReception = `SELECT pr.id, pr.date_time, pr.type, pr.deleted_at, pr.deleted_by,
+                                   r.id, r.date_time, r.status,
+                                   p.id, p.registration_date, p.city
+                                FROM product pr
+                                JOIN reception r ON r.id = pr.reception_id
+                                JOIN pvz p ON p.id = r.pvz_id
+                                WHERE pr.id = $1`
+
+	getReceptionLabels = `SELECT pr.id, pr.date_time, pr.type, pr.deleted_at, pr.deleted_by,
+                              r.id, r.date_time, r.status,
+                              p.id, p.registration_date, p.city
+                           FROM product pr
+                           JOIN reception r ON r.id = pr.reception_id
+                           JOIN pvz p ON p.id = r.pvz_id
+                           WHERE pr.reception_id = $1 AND pr.deleted_at IS NULL
+                           ORDER BY pr.date_time`
+
+	receptionExists = `SELECT EXISTS(SELECT 1 FROM reception WHERE id = $1)`
+
+	getProductEvents = `SELECT type, date_time, reception_id, user_id, pvz_id FROM product_event WHERE product_id = $1 ORDER BY date_time, id`
+
+	lockProduct = `SELECT id FROM product WHERE id = $1 FOR UPDATE`
+
+	addProductEvent = `INSERT INTO product_event (id, product_id, reception_id, type, date_time, user_id, pvz_id)
+                       SELECT gen_random_uuid(), id, reception_id, $2, now(), $3, $4 FROM product WHERE id = $1`
+
+	reserveIdempotencyKey = `INSERT INTO idempotency_key (user_id, key, request_hash, expires_at)
+                             VALUES ($1, $2, $3, now() + $4 * interval '1 second')
+                             ON CONFLICT (user_id, key) DO UPDATE
+                             SET request_hash = EXCLUDED.request_hash,
+                                 status_code = NULL,
+                                 content_type = NULL,
+                                 response_body = NULL,
+                                 created_at = now(),
+                                 expires_at = EXCLUDED.expires_at
+                             WHERE idempotency_key.expires_at < now()
+                             RETURNING user_id`
+
+	getIdempotencyKey = `SELECT request_hash, status_code, content_type, response_body
+                         FROM idempotency_key
+                         WHERE user_id = $1 AND key = $2`
+
+	saveIdempotencyResponse = `UPDATE idempotency_key
+                               SET status_code = $3, content_type = $4, response_body = $5
+                               WHERE user_id = $1 AND key = $2`
+
+	deleteIdempotencyKey = `DELETE FROM idempotency_key WHERE user_id = $1 AND key = $2`
+
+	deleteExpiredIdempotencyKeys = `DELETE FROM idempotency_key WHERE expires_at < now()`
+
+	createWebhook = `INSERT INTO webhook_subscription (id, url, event_types, secret, created_by)
+                     VALUES ($1, $2, $3, $4, $5)
+                     RETURNING created_at`
 
-	deleteLastProduct = `DELETE FROM product WHERE reception_id = $1 ORDER BY date_time DESC LIMIT 1 RETURNING id`
+	listWebhooks = `SELECT id, url, event_types, created_by, created_at FROM webhook_subscription ORDER BY created_at, id`
 
-	deleteProduct = `DELETE FROM product WHERE id = (SELECT id FROM product WHERE reception_id = $1 ORDER BY date_time DESC LIMIT 1)`
+	deleteWebhook = `DELETE FROM webhook_subscription WHERE id = $1`
 
-	deleteLastProductQuery = `WITH active_reception AS (SELECT id FROM reception WHERE pvz_id = $1 AND status = 'in_progress' LIMIT 1) DELETE FROM product WHERE id = (SELECT id FROM product WHERE reception_id = (SELECT id FROM active_reception) ORDER BY date_time DESC LIMIT 1)`
+	enqueueWebhookDeliveries = `INSERT INTO webhook_delivery (id, subscription_id, event_type, payload)
+                                SELECT d.id, d.subscription_id, $1, jsonb_build_object('id', d.id) || $2::jsonb
+                                FROM (
+                                    SELECT gen_random_uuid() AS id, id AS subscription_id
+                                    FROM webhook_subscription
+                                    WHERE $1 = ANY(event_types)
+                                ) d`
+
+	claimWebhookDeliveries = `UPDATE webhook_delivery d
+                              SET next_attempt_at = now() + $2 * interval '1 second'
+                              FROM webhook_subscription s
+                              WHERE s.id = d.subscription_id AND d.id IN (
+                                  SELECT id FROM webhook_delivery
+                                  WHERE status = 'pending' AND next_attempt_at <= now()
+                                  ORDER BY next_attempt_at
+                                  LIMIT $1
+                                  FOR UPDATE SKIP LOCKED
+                              )
+                              RETURNING d.id, d.event_type, d.payload, d.attempts, s.url, s.secret`
+
+	saveWebhookAttempt = `UPDATE webhook_delivery
+                          SET status = $2, attempts = $3, next_attempt_at = $4,
+                              last_status_code = $5, last_error = $6, delivered_at = $7
+                          WHERE id = $1 AND status = 'pending' AND attempts = $3 - 1`
+
+	webhookDeliveryColumns = `id, subscription_id, event_type, status, attempts, next_attempt_at,
+                              last_status_code, last_error, created_at, delivered_at`
+
+	listWebhookDeliveries = `SELECT ` + webhookDeliveryColumns + ` FROM webhook_delivery
+                             WHERE subscription_id = $1 AND ($2::text = '' OR status = $2::text)
+                             ORDER BY created_at DESC, id
+                             LIMIT $3`
+
+	redeliverWebhook = `UPDATE webhook_delivery
+                        SET status = 'pending', attempts = 0, next_attempt_at = now(), last_error = NULL
+                        WHERE id = $1 AND status IN ('dead', 'delivered')
+                        RETURNING ` + webhookDeliveryColumns
+
+	webhookDeliveryExists = `SELECT EXISTS(SELECT 1 FROM webhook_delivery WHERE id = $1)`
+
+	declareReceptionReport = `DECLARE reception_report NO SCROLL CURSOR FOR
+                              SELECT p.id, p.city, r.id, r.date_time, r.status, pr.id, pr.date_time, pr.type
+                              FROM reception r
+                              JOIN pvz p ON p.id = r.pvz_id
+                              LEFT JOIN product pr ON pr.reception_id = r.id AND pr.deleted_at IS NULL%s
+                              ORDER BY r.date_time, r.id, pr.date_time, pr.id`
+
+	fetchReceptionReportRows = `FETCH %d FROM reception_report`
+
+	selectIntakeReport = `SELECT %scount(pr.id), count(DISTINCT (pr.reception_id, (pr.date_time AT TIME ZONE COALESCE(tz.name, 'UTC'))::date))
+                          FROM product pr
+                          JOIN reception r ON r.id = pr.reception_id
+                          JOIN pvz p ON p.id = r.pvz_id
+                          LEFT JOIN unnest(%s::text[], %s::text[]) AS tz(city, name) ON tz.city = p.city%s`
+
+	intakePeriod = `to_char(date_trunc('%s', pr.date_time AT TIME ZONE COALESCE(tz.name, 'UTC')), 'YYYY-MM-DD')`
+
+	getSyncOperation = `SELECT result FROM sync_operation WHERE client_id = $1 AND user_id = $2`
+
+	saveSyncOperation = `INSERT INTO sync_operation (client_id, user_id, type, client_time, result)
+                         VALUES ($1, $2, $3, $4, $5)
+                         ON CONFLICT (user_id, client_id) DO NOTHING`
+
+	selectDailyIntakeReport = `SELECT %sCOALESCE(sum(s.products_kept), 0), COALESCE(sum(s.receptions) FILTER (WHERE %s), 0)
+                               FROM pvz_daily_stats s
+                               JOIN pvz p ON p.id = s.pvz_id%s`
+
+	dailyPeriod = `to_char(date_trunc('%s', s.day), 'YYYY-MM-DD')`
+
+	initDailyStatsState = `INSERT INTO pvz_daily_stats_state (refreshed_until) VALUES ('epoch') ON CONFLICT DO NOTHING`
+
+	lockDailyStatsState = `SELECT refreshed_until, now() FROM pvz_daily_stats_state FOR UPDATE`
+
+	saveDailyStatsState = `UPDATE pvz_daily_stats_state SET refreshed_until = $1`
+
+	getDailyStatsState = `SELECT refreshed_until FROM pvz_daily_stats_state`
+
+	// refreshDailyStats recomputes every local day of a PVZ that had activity
+	// between $3 and $4. products_added and products_kept count the products
+	// added that day, all of them and those not deleted since; the intake
+	// report sums the latter. products_deleted counts the deletions made that
+	// day, whenever the product was added. A product_event therefore marks
+	// both its own day and the day its product was added as dirty. Products
+	// are only soft deleted, and neither receptions nor product events are
+	// ever removed, so a recompute yields every row it wrote before and an
+	// upsert is enough.
+	refreshDailyStats = `WITH zone AS (
+                             SELECT p.id, COALESCE(tz.name, 'UTC') AS name
+                             FROM pvz p
+                             LEFT JOIN unnest($1::text[], $2::text[]) AS tz(city, name) ON tz.city = p.city
+                         ), event AS (
+                             SELECT r.pvz_id, e.date_time, pr.date_time AS added_at
+                             FROM product_event e
+                             JOIN product pr ON pr.id = e.product_id
+                             JOIN reception r ON r.id = pr.reception_id
+                             WHERE e.date_time >= $3 AND e.date_time < $4
+                         ), touched AS (
+                             SELECT pvz_id, date_time AS at FROM event
+                             UNION ALL
+                             SELECT pvz_id, added_at FROM event
+                             UNION ALL
+                             SELECT pvz_id, date_time FROM reception WHERE date_time >= $3 AND date_time < $4
+                             UNION ALL
+                             SELECT pvz_id, closed_at FROM reception WHERE closed_at >= $3 AND closed_at < $4
+                         ), dirty AS (
+                             SELECT DISTINCT t.pvz_id, (t.at AT TIME ZONE z.name)::date AS day, z.name AS zone
+                             FROM touched t
+                             JOIN zone z ON z.id = t.pvz_id
+                         ), bounds AS (
+                             SELECT pvz_id, day,
+                                    day::timestamp AT TIME ZONE zone AS day_start,
+                                    (day + 1)::timestamp AT TIME ZONE zone AS day_end
+                             FROM dirty
+                         )
+                         INSERT INTO pvz_daily_stats (day, pvz_id, product_type, products_added, products_deleted, products_kept,
+                                                      receptions, receptions_opened, receptions_closed, reception_seconds)
+                         SELECT b.day, b.pvz_id, t.type, sum(t.added), sum(t.deleted), sum(t.kept),
+                                count(DISTINCT t.reception_id) FILTER (WHERE t.kept = 1), 0, 0, 0
+                         FROM bounds b
+                         CROSS JOIN LATERAL (
+                             SELECT pr.type, pr.reception_id, 1 AS added, 0 AS deleted,
+                                    CASE WHEN pr.deleted_at IS NULL THEN 1 ELSE 0 END AS kept
+                             FROM product pr
+                             JOIN reception r ON r.id = pr.reception_id
+                             WHERE r.pvz_id = b.pvz_id AND pr.date_time >= b.day_start AND pr.date_time < b.day_end
+                             UNION ALL
+                             SELECT pr.type, pr.reception_id, 0, 1, 0
+                             FROM product_event e
+                             JOIN product pr ON pr.id = e.product_id
+                             JOIN reception r ON r.id = pr.reception_id
+                             WHERE r.pvz_id = b.pvz_id AND e.type = 'deleted'
+                               AND e.date_time >= b.day_start AND e.date_time < b.day_end
+                         ) t
+                         GROUP BY b.day, b.pvz_id, t.type
+                         UNION ALL
+                         SELECT b.day, b.pvz_id, '', 0, 0, 0,
+                                (SELECT count(DISTINCT pr.reception_id)
+                                 FROM product pr
+                                 JOIN reception r ON r.id = pr.reception_id
+                                 WHERE r.pvz_id = b.pvz_id AND pr.deleted_at IS NULL
+                                   AND pr.date_time >= b.day_start AND pr.date_time < b.day_end),
+                                (SELECT count(*) FROM reception r
+                                 WHERE r.pvz_id = b.pvz_id AND r.date_time >= b.day_start AND r.date_time < b.day_end),
+                                closed.count, closed.seconds
+                         FROM bounds b
+                         CROSS JOIN LATERAL (
+                             SELECT count(*) AS count,
+                                    COALESCE(sum(EXTRACT(EPOCH FROM r.closed_at - r.date_time)), 0)::bigint AS seconds
+                             FROM reception r
+                             WHERE r.pvz_id = b.pvz_id AND r.closed_at >= b.day_start AND r.closed_at < b.day_end
+                         ) closed
+                         ON CONFLICT (day, pvz_id, product_type) DO UPDATE
+                         SET products_added = EXCLUDED.products_added,
+                             products_deleted = EXCLUDED.products_deleted,
+                             products_kept = EXCLUDED.products_kept,
+                             receptions = EXCLUDED.receptions,
+                             receptions_opened = EXCLUDED.receptions_opened,
+                             receptions_closed = EXCLUDED.receptions_closed,
+                             reception_seconds = EXCLUDED.reception_seconds`
 )
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/senorUVE/pvz_service/internal/dto"
+)
+
+const webhookDeliveriesLimit = 100
+
+func (r *Repository) CreateWebhook(ctx context.Context, request dto.WebhookRequest, createdBy uuid.UUID) (*dto.WebhookResponse, error) {
+	webhook := dto.WebhookResponse{
+		Id:         uuid.New(),
+		URL:        request.URL,
+		EventTypes: request.EventTypes,
+		CreatedBy:  &createdBy,
+	}
+	err := r.db.QueryRowxContext(ctx, createWebhook, webhook.Id, webhook.URL, pq.Array(webhook.EventTypes), request.Secret, createdBy).
+		Scan(&webhook.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+func (r *Repository) ListWebhooks(ctx context.Context) ([]dto.WebhookResponse, error) {
+	rows, err := r.db.QueryxContext(ctx, listWebhooks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []dto.WebhookResponse{}
+	for rows.Next() {
+		var (
+			webhook   dto.WebhookResponse
+			createdBy uuid.NullUUID
+		)
+		if err := rows.Scan(&webhook.Id, &webhook.URL, pq.Array(&webhook.EventTypes), &createdBy, &webhook.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		if createdBy.Valid {
+			webhook.CreatedBy = &createdBy.UUID
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r *Repository) DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, deleteWebhook, webhookId)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("webhook %s: %w", webhookId, ErrWebhookNotFound)
+	}
+	return nil
+}
+
+// EnqueueWebhookDeliveries queues payload for every subscription to eventType,
+// in the transaction carried by ctx if there is one.
+func (r *Repository) EnqueueWebhookDeliveries(ctx context.Context, eventType string, payload []byte) (int64, error) {
+	result, err := r.conn(ctx).ExecContext(ctx, enqueueWebhookDeliveries, eventType, payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ClaimWebhookDeliveries returns up to limit due deliveries and moves their
+// next attempt lease into the future, so that concurrent workers skip them
+// and a crashed worker's claims become due again.
+func (r *Repository) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]dto.WebhookMessage, error) {
+	rows, err := r.db.QueryxContext(ctx, claimWebhookDeliveries, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []dto.WebhookMessage
+	for rows.Next() {
+		var message dto.WebhookMessage
+		if err := rows.Scan(&message.Id, &message.EventType, &message.Payload, &message.Attempts, &message.URL, &message.Secret); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+// SaveWebhookAttempt records the outcome of attempt number attempt.Attempts.
+// It only applies while the delivery is still pending with the attempts it
+// was claimed with, so a worker whose lease ran out and was claimed again
+// cannot overwrite the newer outcome.
+func (r *Repository) SaveWebhookAttempt(ctx context.Context, attempt dto.WebhookAttempt) error {
+	var (
+		statusCode sql.NullInt64
+		lastError  sql.NullString
+	)
+	if attempt.StatusCode != 0 {
+		statusCode = sql.NullInt64{Int64: int64(attempt.StatusCode), Valid: true}
+	}
+	if attempt.Error != "" {
+		lastError = sql.NullString{String: attempt.Error, Valid: true}
+	}
+	result, err := r.db.ExecContext(ctx, saveWebhookAttempt, attempt.DeliveryId, attempt.Status, attempt.Attempts,
+		attempt.NextAttemptAt, statusCode, lastError, attempt.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook attempt: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to save webhook attempt: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("webhook delivery %s: %w", attempt.DeliveryId, ErrWebhookAttemptStale)
+	}
+	return nil
+}
+
+func (r *Repository) ListWebhookDeliveries(ctx context.Context, webhookId uuid.UUID, status string) ([]dto.WebhookDeliveryResponse, error) {
+	deliveries := []dto.WebhookDeliveryResponse{}
+	if err := r.db.SelectContext(ctx, &deliveries, listWebhookDeliveries, webhookId, status, webhookDeliveriesLimit); err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RedeliverWebhook makes a dead or delivered delivery due immediately with a
+// fresh retry budget. A pending delivery is refused: a worker may be sending
+// it right now, and it is retried anyway.
+func (r *Repository) RedeliverWebhook(ctx context.Context, deliveryId uuid.UUID) (*dto.WebhookDeliveryResponse, error) {
+	var delivery dto.WebhookDeliveryResponse
+	err := r.db.GetContext(ctx, &delivery, redeliverWebhook, deliveryId)
+	if err == nil {
+		return &delivery, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to redeliver webhook: %w", err)
+	}
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, webhookDeliveryExists, deliveryId); err != nil {
+		return nil, fmt.Errorf("failed to redeliver webhook: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("webhook delivery %s: %w", deliveryId, ErrWebhookDeliveryPending)
+	}
+	return nil, fmt.Errorf("webhook delivery %s: %w", deliveryId, ErrWebhookDeliveryNotFound)
+}
@@ -0,0 +1,63 @@
+package webhook
+
+import "time"
+
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultMaxAttempts  = 8
+	defaultBaseBackoff  = 30 * time.Second
+	defaultMaxBackoff   = 6 * time.Hour
+	defaultBatchSize    = 50
+	defaultPollInterval = 5 * time.Second
+)
+
+type Config struct {
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxAttempts is how many failed attempts move a delivery to dead.
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	BaseBackoff time.Duration `mapstructure:"base_backoff"`
+	MaxBackoff  time.Duration `mapstructure:"max_backoff"`
+	BatchSize   int           `mapstructure:"batch_size"`
+	// PollInterval is how often the delivery queue is checked.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// AllowPrivateAddresses lets deliveries reach loopback, link-local and
+	// private addresses. Only for local development: such receivers are
+	// refused by default so a subscription cannot reach internal services.
+	AllowPrivateAddresses bool `mapstructure:"allow_private_addresses"`
+}
+
+// WithDefaults fills in unset fields.
+func (c Config) WithDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = defaultBaseBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = defaultMaxBackoff
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	return c
+}
+
+// Backoff is the delay before the next attempt after attempts failures:
+// BaseBackoff doubled per failure, capped at MaxBackoff.
+func (c Config) Backoff(attempts int) time.Duration {
+	delay := c.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= c.MaxBackoff {
+			return c.MaxBackoff
+		}
+	}
+	return min(delay, c.MaxBackoff)
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const userAgent = "pvz-service-webhooks/1"
+
+var ErrForbiddenAddress = errors.New("webhook receiver address is not public")
+
+type Message struct {
+	DeliveryId uuid.UUID
+	EventType  string
+	URL        string
+	Secret     string
+	Payload    []byte
+}
+
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewSender(cfg Config) *Sender {
+	cfg = cfg.WithDefaults()
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateAddresses {
+		dialer.Control = publicOnly
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would make the dialer check the proxy instead of the receiver.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &Sender{
+		client: &http.Client{Timeout: cfg.Timeout, Transport: transport},
+		now:    time.Now,
+	}
+}
+
+// publicOnly refuses connections to addresses that are not public. It runs
+// for every address the host resolved to, redirects included, so neither a
+// DNS name nor a redirect can point a webhook at an internal service.
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	addr := addrPort.Addr().Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return fmt.Errorf("%s: %w", addr, ErrForbiddenAddress)
+	}
+	return nil
+}
+
+// Send posts the signed payload. It returns the response status code, if
+// any, and an error unless the receiver answered with 2xx.
+func (s *Sender) Send(ctx context.Context, message Message) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, message.URL, bytes.NewReader(message.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, message.EventType)
+	req.Header.Set(DeliveryHeader, message.DeliveryId.String())
+	req.Header.Set(SignatureHeader, Sign(message.Secret, s.now(), message.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"reception.opened"}`)
+	header := Sign("secret", now, body)
+
+	assert.NoError(t, Verify("secret", header, body, time.Minute, now.Add(30*time.Second)))
+	assert.ErrorIs(t, Verify("other", header, body, time.Minute, now), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, []byte(`{}`), time.Minute, now), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, body, time.Minute, now.Add(time.Hour)), ErrInvalidSignature)
+	assert.NoError(t, Verify("secret", header, body, 0, now.Add(time.Hour)))
+	assert.ErrorIs(t, Verify("secret", "garbage", body, 0, now), ErrInvalidSignature)
+}
+
+func TestConfig_Backoff(t *testing.T) {
+	cfg := Config{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}.WithDefaults()
+
+	assert.Equal(t, time.Second, cfg.Backoff(1))
+	assert.Equal(t, 2*time.Second, cfg.Backoff(2))
+	assert.Equal(t, 8*time.Second, cfg.Backoff(4))
+	assert.Equal(t, 10*time.Second, cfg.Backoff(5))
+	assert.Equal(t, 10*time.Second, cfg.Backoff(100))
+}
+
+func TestSender_Send(t *testing.T) {
+	deliveryId := uuid.New()
+	payload := []byte(`{"type":"product.added"}`)
+	status := http.StatusNoContent
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, payload, body)
+		assert.Equal(t, "product.added", r.Header.Get(EventHeader))
+		assert.Equal(t, deliveryId.String(), r.Header.Get(DeliveryHeader))
+		assert.NoError(t, Verify("secret", r.Header.Get(SignatureHeader), body, time.Minute, time.Now()))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender := NewSender(Config{AllowPrivateAddresses: true})
+	message := Message{DeliveryId: deliveryId, EventType: "product.added", URL: server.URL, Secret: "secret", Payload: payload}
+
+	code, err := sender.Send(context.Background(), message)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+
+	status = http.StatusServiceUnavailable
+	code, err = sender.Send(context.Background(), message)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
+
+func TestSender_RefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback receiver")
+	}))
+	defer server.Close()
+
+	sender := NewSender(Config{})
+	for _, url := range []string{
+		server.URL,
+		fmt.Sprintf("http://localhost:%d/", server.Listener.Addr().(*net.TCPAddr).Port),
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/",
+		"http://[::1]/",
+	} {
+		message := Message{DeliveryId: uuid.New(), EventType: "product.added", URL: url, Secret: "secret", Payload: []byte(`{}`)}
+		_, err := sender.Send(context.Background(), message)
+		assert.ErrorIs(t, err, ErrForbiddenAddress, url)
+	}
+}
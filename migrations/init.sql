@@ -8,7 +8,8 @@ CREATE TABLE IF NOT EXISTS users (
 CREATE TABLE IF NOT EXISTS pvz (
     id uuid PRIMARY KEY NOT NULL,
     registration_date TIMESTAMP WITH TIME ZONE NOT NULL,
-    city VARCHAR(255) NOT NULL
+    city VARCHAR(255) NOT NULL,
+    external_code VARCHAR(64)
 );
 
 CREATE TABLE IF NOT EXISTS reception (
@@ -16,7 +17,8 @@ CREATE TABLE IF NOT EXISTS reception (
     date_time TIMESTAMP WITH TIME ZONE NOT NULL,
     pvz_id uuid NOT NULL,
     FOREIGN KEY (pvz_id) REFERENCES pvz(id),
-    status VARCHAR(255) NOT NULL
+    status VARCHAR(255) NOT NULL,
+    closed_at TIMESTAMP WITH TIME ZONE
 );
 
 CREATE TABLE IF NOT EXISTS product (
@@ -24,9 +26,103 @@ CREATE TABLE IF NOT EXISTS product (
     date_time TIMESTAMP WITH TIME ZONE NOT NULL,
     type VARCHAR(255) NOT NULL,
     reception_id uuid NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by uuid,
     FOREIGN KEY (reception_id) REFERENCES reception(id)
 );
 
 CREATE INDEX IF NOT EXISTS idx_users_email ON users USING HASH (email);
+CREATE INDEX idx_pvz_registration_date_id ON pvz(registration_date, id);
+CREATE UNIQUE INDEX idx_pvz_external_code ON pvz(external_code);
 CREATE INDEX idx_reception_pvz_id ON reception(pvz_id);
-CREATE INDEX idx_product_reception_id ON product(reception_id);
\ No newline at end of file
+CREATE INDEX idx_reception_date_time ON reception(date_time);
+CREATE INDEX idx_reception_closed_at ON reception(closed_at);
+CREATE INDEX idx_product_reception_id ON product(reception_id);
+CREATE INDEX idx_product_reception_id_alive ON product(reception_id, date_time) WHERE deleted_at IS NULL;
+CREATE INDEX idx_product_date_time_alive ON product(date_time) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS product_event (
+    id uuid PRIMARY KEY NOT NULL,
+    product_id uuid NOT NULL,
+    reception_id uuid NOT NULL,
+    type VARCHAR(255) NOT NULL,
+    date_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    user_id uuid,
+    pvz_id uuid
+);
+
+CREATE INDEX idx_product_event_product_id ON product_event(product_id);
+CREATE INDEX idx_product_event_date_time ON product_event(date_time);
+
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    user_id uuid NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INTEGER,
+    content_type VARCHAR(255),
+    response_body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX idx_idempotency_key_expires_at ON idempotency_key(expires_at);
+
+CREATE TABLE IF NOT EXISTS webhook_subscription (
+    id uuid PRIMARY KEY NOT NULL,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    created_by uuid,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+    id uuid PRIMARY KEY NOT NULL,
+    subscription_id uuid NOT NULL,
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscription(id) ON DELETE CASCADE,
+    event_type VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    last_status_code INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_webhook_delivery_due ON webhook_delivery(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_delivery_subscription_id ON webhook_delivery(subscription_id, created_at);
+
+CREATE TABLE IF NOT EXISTS sync_operation (
+    client_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    client_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    result JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, client_id)
+);
+
+-- Activity per local day of the PVZ's city. Product counters are kept per
+-- product type; reception counters live in the row with an empty type.
+CREATE TABLE IF NOT EXISTS pvz_daily_stats (
+    day DATE NOT NULL,
+    pvz_id uuid NOT NULL,
+    FOREIGN KEY (pvz_id) REFERENCES pvz(id),
+    product_type VARCHAR(255) NOT NULL,
+    products_added INTEGER NOT NULL DEFAULT 0,
+    products_deleted INTEGER NOT NULL DEFAULT 0,
+    products_kept INTEGER NOT NULL DEFAULT 0,
+    receptions INTEGER NOT NULL DEFAULT 0,
+    receptions_opened INTEGER NOT NULL DEFAULT 0,
+    receptions_closed INTEGER NOT NULL DEFAULT 0,
+    reception_seconds BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, pvz_id, product_type)
+);
+
+CREATE TABLE IF NOT EXISTS pvz_daily_stats_state (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    refreshed_until TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -46,7 +46,7 @@ func setupTestServer() (*httptest.Server, func()) {
 
 	logrus.Info("Pvz service initialized successfully")
 
-	pvzHandler := handler.NewPvzHandler(pvzService, authService, cfg.AppPort)
+	pvzHandler := handler.NewPvzHandler(pvzService, authService, cfg.AppPort, cfg.APIConfig)
 
 	logrus.Info("Pvz handler initialized successfully")
 
@@ -82,7 +82,7 @@ func setupTestServer() (*httptest.Server, func()) {
 		}
 		defer conn.Close()
 
-		_, err = conn.Exec("TRUNCATE TABLE users, pvz, product, reception RESTART IDENTITY CASCADE;")
+		_, err = conn.Exec("TRUNCATE TABLE users, pvz, product, reception, product_event, idempotency_key, webhook_subscription, webhook_delivery, sync_operation, pvz_daily_stats, pvz_daily_stats_state RESTART IDENTITY CASCADE;")
 		if err != nil {
 			logrus.Fatalf("Failed to truncate tables: %v", err)
 		}
@@ -6,11 +6,13 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 	dto "github.com/senorUVE/pvz_service/internal/dto"
+	events "github.com/senorUVE/pvz_service/internal/events"
 	models "github.com/senorUVE/pvz_service/internal/models"
 )
 
@@ -127,18 +129,47 @@ func (mr *MockPvzServiceMockRecorder) CreateUser(ctx, request interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockPvzService)(nil).CreateUser), ctx, request)
 }
 
+// CreateWebhook mocks base method.
+func (m *MockPvzService) CreateWebhook(ctx context.Context, request *dto.WebhookRequest, userId uuid.UUID) (*dto.WebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, request, userId)
+	ret0, _ := ret[0].(*dto.WebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockPvzServiceMockRecorder) CreateWebhook(ctx, request, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockPvzService)(nil).CreateWebhook), ctx, request, userId)
+}
+
 // DeleteLastProduct mocks base method.
-func (m *MockPvzService) DeleteLastProduct(ctx context.Context, pvzId uuid.UUID) error {
+func (m *MockPvzService) DeleteLastProduct(ctx context.Context, pvzId, userId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteLastProduct", ctx, pvzId)
+	ret := m.ctrl.Call(m, "DeleteLastProduct", ctx, pvzId, userId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteLastProduct indicates an expected call of DeleteLastProduct.
-func (mr *MockPvzServiceMockRecorder) DeleteLastProduct(ctx, pvzId interface{}) *gomock.Call {
+func (mr *MockPvzServiceMockRecorder) DeleteLastProduct(ctx, pvzId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastProduct", reflect.TypeOf((*MockPvzService)(nil).DeleteLastProduct), ctx, pvzId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastProduct", reflect.TypeOf((*MockPvzService)(nil).DeleteLastProduct), ctx, pvzId, userId)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockPvzService) DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, webhookId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockPvzServiceMockRecorder) DeleteWebhook(ctx, webhookId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockPvzService)(nil).DeleteWebhook), ctx, webhookId)
 }
 
 // DummyLogin mocks base method.
@@ -156,11 +187,85 @@ func (mr *MockPvzServiceMockRecorder) DummyLogin(ctx, role interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DummyLogin", reflect.TypeOf((*MockPvzService)(nil).DummyLogin), ctx, role)
 }
 
+// ExportReceptions mocks base method.
+func (m *MockPvzService) ExportReceptions(ctx context.Context, request *dto.ReceptionReportRequest, fn func(dto.ReceptionReportRow) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportReceptions", ctx, request, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportReceptions indicates an expected call of ExportReceptions.
+func (mr *MockPvzServiceMockRecorder) ExportReceptions(ctx, request, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportReceptions", reflect.TypeOf((*MockPvzService)(nil).ExportReceptions), ctx, request, fn)
+}
+
+// FindProduct mocks base method.
+func (m *MockPvzService) FindProduct(ctx context.Context, request *dto.GetProductRequest) (*dto.ProductDetailsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProduct", ctx, request)
+	ret0, _ := ret[0].(*dto.ProductDetailsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProduct indicates an expected call of FindProduct.
+func (mr *MockPvzServiceMockRecorder) FindProduct(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProduct", reflect.TypeOf((*MockPvzService)(nil).FindProduct), ctx, request)
+}
+
+// GetIntakeReport mocks base method.
+func (m *MockPvzService) GetIntakeReport(ctx context.Context, request *dto.IntakeReportRequest) (*dto.IntakeReportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIntakeReport", ctx, request)
+	ret0, _ := ret[0].(*dto.IntakeReportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIntakeReport indicates an expected call of GetIntakeReport.
+func (mr *MockPvzServiceMockRecorder) GetIntakeReport(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIntakeReport", reflect.TypeOf((*MockPvzService)(nil).GetIntakeReport), ctx, request)
+}
+
+// GetProduct mocks base method.
+func (m *MockPvzService) GetProduct(ctx context.Context, productId uuid.UUID) (*dto.ProductDetailsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProduct", ctx, productId)
+	ret0, _ := ret[0].(*dto.ProductDetailsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProduct indicates an expected call of GetProduct.
+func (mr *MockPvzServiceMockRecorder) GetProduct(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProduct", reflect.TypeOf((*MockPvzService)(nil).GetProduct), ctx, productId)
+}
+
+// GetProductLabel mocks base method.
+func (m *MockPvzService) GetProductLabel(ctx context.Context, productId uuid.UUID, format string) (*dto.LabelResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductLabel", ctx, productId, format)
+	ret0, _ := ret[0].(*dto.LabelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductLabel indicates an expected call of GetProductLabel.
+func (mr *MockPvzServiceMockRecorder) GetProductLabel(ctx, productId, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductLabel", reflect.TypeOf((*MockPvzService)(nil).GetProductLabel), ctx, productId, format)
+}
+
 // GetPvz mocks base method.
-func (m *MockPvzService) GetPvz(ctx context.Context, request *dto.GetPvzRequest) ([]*dto.PVZWithReceptions, error) {
+func (m *MockPvzService) GetPvz(ctx context.Context, request *dto.GetPvzRequest) (*dto.GetPvzResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPvz", ctx, request)
-	ret0, _ := ret[0].([]*dto.PVZWithReceptions)
+	ret0, _ := ret[0].(*dto.GetPvzResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -171,6 +276,36 @@ func (mr *MockPvzServiceMockRecorder) GetPvz(ctx, request interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPvz", reflect.TypeOf((*MockPvzService)(nil).GetPvz), ctx, request)
 }
 
+// GetPvzReceptions mocks base method.
+func (m *MockPvzService) GetPvzReceptions(ctx context.Context, pvzIds []uuid.UUID, request *dto.GetPvzRequest) (map[uuid.UUID][]dto.ReceptionWithProducts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPvzReceptions", ctx, pvzIds, request)
+	ret0, _ := ret[0].(map[uuid.UUID][]dto.ReceptionWithProducts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPvzReceptions indicates an expected call of GetPvzReceptions.
+func (mr *MockPvzServiceMockRecorder) GetPvzReceptions(ctx, pvzIds, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPvzReceptions", reflect.TypeOf((*MockPvzService)(nil).GetPvzReceptions), ctx, pvzIds, request)
+}
+
+// GetReceptionLabels mocks base method.
+func (m *MockPvzService) GetReceptionLabels(ctx context.Context, receptionId uuid.UUID, format string) (*dto.LabelResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReceptionLabels", ctx, receptionId, format)
+	ret0, _ := ret[0].(*dto.LabelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceptionLabels indicates an expected call of GetReceptionLabels.
+func (mr *MockPvzServiceMockRecorder) GetReceptionLabels(ctx, receptionId, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceptionLabels", reflect.TypeOf((*MockPvzService)(nil).GetReceptionLabels), ctx, receptionId, format)
+}
+
 // GetUser mocks base method.
 func (m *MockPvzService) GetUser(ctx context.Context, email string) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -185,3 +320,181 @@ func (mr *MockPvzServiceMockRecorder) GetUser(ctx, email interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockPvzService)(nil).GetUser), ctx, email)
 }
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockPvzService) GetWebhookDeliveries(ctx context.Context, webhookId uuid.UUID, request *dto.GetWebhookDeliveriesRequest) ([]dto.WebhookDeliveryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", ctx, webhookId, request)
+	ret0, _ := ret[0].([]dto.WebhookDeliveryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockPvzServiceMockRecorder) GetWebhookDeliveries(ctx, webhookId, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockPvzService)(nil).GetWebhookDeliveries), ctx, webhookId, request)
+}
+
+// ImportPvz mocks base method.
+func (m *MockPvzService) ImportPvz(ctx context.Context, r io.Reader, request *dto.PvzImportRequest) (*dto.PvzImportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportPvz", ctx, r, request)
+	ret0, _ := ret[0].(*dto.PvzImportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportPvz indicates an expected call of ImportPvz.
+func (mr *MockPvzServiceMockRecorder) ImportPvz(ctx, r, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportPvz", reflect.TypeOf((*MockPvzService)(nil).ImportPvz), ctx, r, request)
+}
+
+// IssueProduct mocks base method.
+func (m *MockPvzService) IssueProduct(ctx context.Context, productId, userId uuid.UUID) (*dto.ProductDetailsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueProduct", ctx, productId, userId)
+	ret0, _ := ret[0].(*dto.ProductDetailsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueProduct indicates an expected call of IssueProduct.
+func (mr *MockPvzServiceMockRecorder) IssueProduct(ctx, productId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueProduct", reflect.TypeOf((*MockPvzService)(nil).IssueProduct), ctx, productId, userId)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockPvzService) ListWebhooks(ctx context.Context) ([]dto.WebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]dto.WebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockPvzServiceMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockPvzService)(nil).ListWebhooks), ctx)
+}
+
+// MoveProduct mocks base method.
+func (m *MockPvzService) MoveProduct(ctx context.Context, productId uuid.UUID, request *dto.MoveProductRequest, userId uuid.UUID) (*dto.ProductDetailsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveProduct", ctx, productId, request, userId)
+	ret0, _ := ret[0].(*dto.ProductDetailsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveProduct indicates an expected call of MoveProduct.
+func (mr *MockPvzServiceMockRecorder) MoveProduct(ctx, productId, request, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveProduct", reflect.TypeOf((*MockPvzService)(nil).MoveProduct), ctx, productId, request, userId)
+}
+
+// RedeliverWebhook mocks base method.
+func (m *MockPvzService) RedeliverWebhook(ctx context.Context, deliveryId uuid.UUID) (*dto.WebhookDeliveryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhook", ctx, deliveryId)
+	ret0, _ := ret[0].(*dto.WebhookDeliveryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverWebhook indicates an expected call of RedeliverWebhook.
+func (mr *MockPvzServiceMockRecorder) RedeliverWebhook(ctx, deliveryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhook", reflect.TypeOf((*MockPvzService)(nil).RedeliverWebhook), ctx, deliveryId)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockPvzService) ReleaseIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, userId, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockPvzServiceMockRecorder) ReleaseIdempotencyKey(ctx, userId, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockPvzService)(nil).ReleaseIdempotencyKey), ctx, userId, key)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockPvzService) ReserveIdempotencyKey(ctx context.Context, record dto.IdempotencyRecord) (*dto.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, record)
+	ret0, _ := ret[0].(*dto.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockPvzServiceMockRecorder) ReserveIdempotencyKey(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockPvzService)(nil).ReserveIdempotencyKey), ctx, record)
+}
+
+// RestoreLastProduct mocks base method.
+func (m *MockPvzService) RestoreLastProduct(ctx context.Context, pvzId, userId uuid.UUID) (*dto.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreLastProduct", ctx, pvzId, userId)
+	ret0, _ := ret[0].(*dto.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreLastProduct indicates an expected call of RestoreLastProduct.
+func (mr *MockPvzServiceMockRecorder) RestoreLastProduct(ctx, pvzId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreLastProduct", reflect.TypeOf((*MockPvzService)(nil).RestoreLastProduct), ctx, pvzId, userId)
+}
+
+// SaveIdempotencyResponse mocks base method.
+func (m *MockPvzService) SaveIdempotencyResponse(ctx context.Context, record dto.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotencyResponse", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotencyResponse indicates an expected call of SaveIdempotencyResponse.
+func (mr *MockPvzServiceMockRecorder) SaveIdempotencyResponse(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotencyResponse", reflect.TypeOf((*MockPvzService)(nil).SaveIdempotencyResponse), ctx, record)
+}
+
+// SubscribeEvents mocks base method.
+func (m *MockPvzService) SubscribeEvents(request *dto.EventStreamRequest) (*events.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeEvents", request)
+	ret0, _ := ret[0].(*events.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeEvents indicates an expected call of SubscribeEvents.
+func (mr *MockPvzServiceMockRecorder) SubscribeEvents(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvents", reflect.TypeOf((*MockPvzService)(nil).SubscribeEvents), request)
+}
+
+// Sync mocks base method.
+func (m *MockPvzService) Sync(ctx context.Context, request *dto.SyncRequest, userId uuid.UUID) (*dto.SyncResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, request, userId)
+	ret0, _ := ret[0].(*dto.SyncResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockPvzServiceMockRecorder) Sync(ctx, request, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockPvzService)(nil).Sync), ctx, request, userId)
+}
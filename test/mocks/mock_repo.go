@@ -38,6 +38,50 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddProductEvent mocks base method.
+func (m *MockRepository) AddProductEvent(ctx context.Context, productId uuid.UUID, eventType models.ProductEventType, userId uuid.UUID, pvzId *uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddProductEvent", ctx, productId, eventType, userId, pvzId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddProductEvent indicates an expected call of AddProductEvent.
+func (mr *MockRepositoryMockRecorder) AddProductEvent(ctx, productId, eventType, userId, pvzId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProductEvent", reflect.TypeOf((*MockRepository)(nil).AddProductEvent), ctx, productId, eventType, userId, pvzId)
+}
+
+// BackfillDailyStats mocks base method.
+func (m *MockRepository) BackfillDailyStats(ctx context.Context, from, to time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillDailyStats", ctx, from, to)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BackfillDailyStats indicates an expected call of BackfillDailyStats.
+func (mr *MockRepositoryMockRecorder) BackfillDailyStats(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillDailyStats", reflect.TypeOf((*MockRepository)(nil).BackfillDailyStats), ctx, from, to)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockRepository) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]dto.WebhookMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]dto.WebhookMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) ClaimWebhookDeliveries(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).ClaimWebhookDeliveries), ctx, limit, lease)
+}
+
 // CloseReception mocks base method.
 func (m *MockRepository) CloseReception(ctx context.Context, pvzId uuid.UUID) (*dto.CloseLastReceptionResponse, error) {
 	m.ctrl.T.Helper()
@@ -113,18 +157,92 @@ func (mr *MockRepositoryMockRecorder) CreateUser(ctx, email, password, role inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockRepository)(nil).CreateUser), ctx, email, password, role)
 }
 
-// DeleteLastProduct mocks base method.
-func (m *MockRepository) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error {
+// CreateWebhook mocks base method.
+func (m *MockRepository) CreateWebhook(ctx context.Context, request dto.WebhookRequest, createdBy uuid.UUID) (*dto.WebhookResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteLastProduct", ctx, pvzID)
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, request, createdBy)
+	ret0, _ := ret[0].(*dto.WebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockRepositoryMockRecorder) CreateWebhook(ctx, request, createdBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockRepository)(nil).CreateWebhook), ctx, request, createdBy)
+}
+
+// DailyStatsRefreshedUntil mocks base method.
+func (m *MockRepository) DailyStatsRefreshedUntil(ctx context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DailyStatsRefreshedUntil", ctx)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DailyStatsRefreshedUntil indicates an expected call of DailyStatsRefreshedUntil.
+func (mr *MockRepositoryMockRecorder) DailyStatsRefreshedUntil(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DailyStatsRefreshedUntil", reflect.TypeOf((*MockRepository)(nil).DailyStatsRefreshedUntil), ctx)
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockRepositoryMockRecorder) DeleteExpiredIdempotencyKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockRepository)(nil).DeleteExpiredIdempotencyKeys), ctx)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockRepository) DeleteIdempotencyKey(ctx context.Context, userId uuid.UUID, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", ctx, userId, key)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) DeleteIdempotencyKey(ctx, userId, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).DeleteIdempotencyKey), ctx, userId, key)
+}
+
+// DeleteLastProduct mocks base method.
+func (m *MockRepository) DeleteLastProduct(ctx context.Context, pvzID, userId uuid.UUID) (*dto.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLastProduct", ctx, pvzID, userId)
+	ret0, _ := ret[0].(*dto.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteLastProduct indicates an expected call of DeleteLastProduct.
-func (mr *MockRepositoryMockRecorder) DeleteLastProduct(ctx, pvzID interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) DeleteLastProduct(ctx, pvzID, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastProduct", reflect.TypeOf((*MockRepository)(nil).DeleteLastProduct), ctx, pvzID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLastProduct", reflect.TypeOf((*MockRepository)(nil).DeleteLastProduct), ctx, pvzID, userId)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockRepository) DeleteWebhook(ctx context.Context, webhookId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, webhookId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockRepositoryMockRecorder) DeleteWebhook(ctx, webhookId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockRepository)(nil).DeleteWebhook), ctx, webhookId)
 }
 
 // DummyLogin mocks base method.
@@ -142,6 +260,21 @@ func (mr *MockRepositoryMockRecorder) DummyLogin(ctx, role interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DummyLogin", reflect.TypeOf((*MockRepository)(nil).DummyLogin), ctx, role)
 }
 
+// EnqueueWebhookDeliveries mocks base method.
+func (m *MockRepository) EnqueueWebhookDeliveries(ctx context.Context, eventType string, payload []byte) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookDeliveries", ctx, eventType, payload)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueWebhookDeliveries indicates an expected call of EnqueueWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) EnqueueWebhookDeliveries(ctx, eventType, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).EnqueueWebhookDeliveries), ctx, eventType, payload)
+}
+
 // GetActiveReception mocks base method.
 func (m *MockRepository) GetActiveReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
 	m.ctrl.T.Helper()
@@ -157,19 +290,139 @@ func (mr *MockRepositoryMockRecorder) GetActiveReception(ctx, pvzID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveReception", reflect.TypeOf((*MockRepository)(nil).GetActiveReception), ctx, pvzID)
 }
 
+// GetDailyIntakeReport mocks base method.
+func (m *MockRepository) GetDailyIntakeReport(ctx context.Context, filter dto.IntakeReportFilter, firstDay, lastDay time.Time) ([]dto.IntakeReportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyIntakeReport", ctx, filter, firstDay, lastDay)
+	ret0, _ := ret[0].([]dto.IntakeReportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyIntakeReport indicates an expected call of GetDailyIntakeReport.
+func (mr *MockRepositoryMockRecorder) GetDailyIntakeReport(ctx, filter, firstDay, lastDay interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyIntakeReport", reflect.TypeOf((*MockRepository)(nil).GetDailyIntakeReport), ctx, filter, firstDay, lastDay)
+}
+
+// GetIntakeReport mocks base method.
+func (m *MockRepository) GetIntakeReport(ctx context.Context, filter dto.IntakeReportFilter) ([]dto.IntakeReportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIntakeReport", ctx, filter)
+	ret0, _ := ret[0].([]dto.IntakeReportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIntakeReport indicates an expected call of GetIntakeReport.
+func (mr *MockRepositoryMockRecorder) GetIntakeReport(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIntakeReport", reflect.TypeOf((*MockRepository)(nil).GetIntakeReport), ctx, filter)
+}
+
+// GetProductDetails mocks base method.
+func (m *MockRepository) GetProductDetails(ctx context.Context, productId uuid.UUID) (*dto.ProductDetailsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductDetails", ctx, productId)
+	ret0, _ := ret[0].(*dto.ProductDetailsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductDetails indicates an expected call of GetProductDetails.
+func (mr *MockRepositoryMockRecorder) GetProductDetails(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductDetails", reflect.TypeOf((*MockRepository)(nil).GetProductDetails), ctx, productId)
+}
+
+// GetProductLabel mocks base method.
+func (m *MockRepository) GetProductLabel(ctx context.Context, productId uuid.UUID) (*dto.ProductLabel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductLabel", ctx, productId)
+	ret0, _ := ret[0].(*dto.ProductLabel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductLabel indicates an expected call of GetProductLabel.
+func (mr *MockRepositoryMockRecorder) GetProductLabel(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductLabel", reflect.TypeOf((*MockRepository)(nil).GetProductLabel), ctx, productId)
+}
+
 // GetPvz mocks base method.
-func (m *MockRepository) GetPvz(ctx context.Context, startDate, endDate time.Time, page, limit int) ([]*dto.PVZWithReceptions, error) {
+func (m *MockRepository) GetPvz(ctx context.Context, filter dto.GetPvzRequest) (*dto.GetPvzResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPvz", ctx, startDate, endDate, page, limit)
-	ret0, _ := ret[0].([]*dto.PVZWithReceptions)
+	ret := m.ctrl.Call(m, "GetPvz", ctx, filter)
+	ret0, _ := ret[0].(*dto.GetPvzResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPvz indicates an expected call of GetPvz.
-func (mr *MockRepositoryMockRecorder) GetPvz(ctx, startDate, endDate, page, limit interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetPvz(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPvz", reflect.TypeOf((*MockRepository)(nil).GetPvz), ctx, filter)
+}
+
+// GetPvzById mocks base method.
+func (m *MockRepository) GetPvzById(ctx context.Context, pvzId uuid.UUID) (*dto.PVZResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPvzById", ctx, pvzId)
+	ret0, _ := ret[0].(*dto.PVZResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPvzById indicates an expected call of GetPvzById.
+func (mr *MockRepositoryMockRecorder) GetPvzById(ctx, pvzId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPvzById", reflect.TypeOf((*MockRepository)(nil).GetPvzById), ctx, pvzId)
+}
+
+// GetPvzReceptions mocks base method.
+func (m *MockRepository) GetPvzReceptions(ctx context.Context, pvzIds []uuid.UUID, filter dto.GetPvzRequest) (map[uuid.UUID][]dto.ReceptionWithProducts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPvzReceptions", ctx, pvzIds, filter)
+	ret0, _ := ret[0].(map[uuid.UUID][]dto.ReceptionWithProducts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPvzReceptions indicates an expected call of GetPvzReceptions.
+func (mr *MockRepositoryMockRecorder) GetPvzReceptions(ctx, pvzIds, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPvzReceptions", reflect.TypeOf((*MockRepository)(nil).GetPvzReceptions), ctx, pvzIds, filter)
+}
+
+// GetReceptionLabels mocks base method.
+func (m *MockRepository) GetReceptionLabels(ctx context.Context, receptionId uuid.UUID) ([]*dto.ProductLabel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReceptionLabels", ctx, receptionId)
+	ret0, _ := ret[0].([]*dto.ProductLabel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceptionLabels indicates an expected call of GetReceptionLabels.
+func (mr *MockRepositoryMockRecorder) GetReceptionLabels(ctx, receptionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceptionLabels", reflect.TypeOf((*MockRepository)(nil).GetReceptionLabels), ctx, receptionId)
+}
+
+// GetSyncResult mocks base method.
+func (m *MockRepository) GetSyncResult(ctx context.Context, userId, clientId uuid.UUID) (*dto.SyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncResult", ctx, userId, clientId)
+	ret0, _ := ret[0].(*dto.SyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSyncResult indicates an expected call of GetSyncResult.
+func (mr *MockRepositoryMockRecorder) GetSyncResult(ctx, userId, clientId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPvz", reflect.TypeOf((*MockRepository)(nil).GetPvz), ctx, startDate, endDate, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncResult", reflect.TypeOf((*MockRepository)(nil).GetSyncResult), ctx, userId, clientId)
 }
 
 // GetUser mocks base method.
@@ -186,3 +439,206 @@ func (mr *MockRepositoryMockRecorder) GetUser(ctx, email interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockRepository)(nil).GetUser), ctx, email)
 }
+
+// ImportPvz mocks base method.
+func (m *MockRepository) ImportPvz(ctx context.Context, pvzs []models.PVZ, dryRun bool) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportPvz", ctx, pvzs, dryRun)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportPvz indicates an expected call of ImportPvz.
+func (mr *MockRepositoryMockRecorder) ImportPvz(ctx, pvzs, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportPvz", reflect.TypeOf((*MockRepository)(nil).ImportPvz), ctx, pvzs, dryRun)
+}
+
+// InTx mocks base method.
+func (m *MockRepository) InTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx.
+func (mr *MockRepositoryMockRecorder) InTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockRepository)(nil).InTx), ctx, fn)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockRepository) ListWebhookDeliveries(ctx context.Context, webhookId uuid.UUID, status string) ([]dto.WebhookDeliveryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, webhookId, status)
+	ret0, _ := ret[0].([]dto.WebhookDeliveryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) ListWebhookDeliveries(ctx, webhookId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).ListWebhookDeliveries), ctx, webhookId, status)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockRepository) ListWebhooks(ctx context.Context) ([]dto.WebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]dto.WebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockRepositoryMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockRepository)(nil).ListWebhooks), ctx)
+}
+
+// LockProduct mocks base method.
+func (m *MockRepository) LockProduct(ctx context.Context, productId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockProduct", ctx, productId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockProduct indicates an expected call of LockProduct.
+func (mr *MockRepositoryMockRecorder) LockProduct(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockProduct", reflect.TypeOf((*MockRepository)(nil).LockProduct), ctx, productId)
+}
+
+// Ping mocks base method.
+func (m *MockRepository) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockRepositoryMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRepository)(nil).Ping), ctx)
+}
+
+// RedeliverWebhook mocks base method.
+func (m *MockRepository) RedeliverWebhook(ctx context.Context, deliveryId uuid.UUID) (*dto.WebhookDeliveryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhook", ctx, deliveryId)
+	ret0, _ := ret[0].(*dto.WebhookDeliveryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverWebhook indicates an expected call of RedeliverWebhook.
+func (mr *MockRepositoryMockRecorder) RedeliverWebhook(ctx, deliveryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhook", reflect.TypeOf((*MockRepository)(nil).RedeliverWebhook), ctx, deliveryId)
+}
+
+// RefreshRecentDailyStats mocks base method.
+func (m *MockRepository) RefreshRecentDailyStats(ctx context.Context, lag time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshRecentDailyStats", ctx, lag)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshRecentDailyStats indicates an expected call of RefreshRecentDailyStats.
+func (mr *MockRepositoryMockRecorder) RefreshRecentDailyStats(ctx, lag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshRecentDailyStats", reflect.TypeOf((*MockRepository)(nil).RefreshRecentDailyStats), ctx, lag)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockRepository) ReserveIdempotencyKey(ctx context.Context, record dto.IdempotencyRecord, ttl time.Duration) (*dto.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, record, ttl)
+	ret0, _ := ret[0].(*dto.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) ReserveIdempotencyKey(ctx, record, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).ReserveIdempotencyKey), ctx, record, ttl)
+}
+
+// RestoreLastProduct mocks base method.
+func (m *MockRepository) RestoreLastProduct(ctx context.Context, pvzID, userId uuid.UUID) (*dto.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreLastProduct", ctx, pvzID, userId)
+	ret0, _ := ret[0].(*dto.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreLastProduct indicates an expected call of RestoreLastProduct.
+func (mr *MockRepositoryMockRecorder) RestoreLastProduct(ctx, pvzID, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreLastProduct", reflect.TypeOf((*MockRepository)(nil).RestoreLastProduct), ctx, pvzID, userId)
+}
+
+// SaveIdempotencyResponse mocks base method.
+func (m *MockRepository) SaveIdempotencyResponse(ctx context.Context, record dto.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotencyResponse", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotencyResponse indicates an expected call of SaveIdempotencyResponse.
+func (mr *MockRepositoryMockRecorder) SaveIdempotencyResponse(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotencyResponse", reflect.TypeOf((*MockRepository)(nil).SaveIdempotencyResponse), ctx, record)
+}
+
+// SaveSyncResult mocks base method.
+func (m *MockRepository) SaveSyncResult(ctx context.Context, userId uuid.UUID, op dto.SyncOperation, result dto.SyncResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSyncResult", ctx, userId, op, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSyncResult indicates an expected call of SaveSyncResult.
+func (mr *MockRepositoryMockRecorder) SaveSyncResult(ctx, userId, op, result interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSyncResult", reflect.TypeOf((*MockRepository)(nil).SaveSyncResult), ctx, userId, op, result)
+}
+
+// SaveWebhookAttempt mocks base method.
+func (m *MockRepository) SaveWebhookAttempt(ctx context.Context, attempt dto.WebhookAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWebhookAttempt", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWebhookAttempt indicates an expected call of SaveWebhookAttempt.
+func (mr *MockRepositoryMockRecorder) SaveWebhookAttempt(ctx, attempt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWebhookAttempt", reflect.TypeOf((*MockRepository)(nil).SaveWebhookAttempt), ctx, attempt)
+}
+
+// StreamReceptionReport mocks base method.
+func (m *MockRepository) StreamReceptionReport(ctx context.Context, filter dto.ReceptionReportFilter, fn func(dto.ReceptionReportRow) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamReceptionReport", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamReceptionReport indicates an expected call of StreamReceptionReport.
+func (mr *MockRepositoryMockRecorder) StreamReceptionReport(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamReceptionReport", reflect.TypeOf((*MockRepository)(nil).StreamReceptionReport), ctx, filter, fn)
+}